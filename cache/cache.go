@@ -0,0 +1,263 @@
+// Package cache 提供泛型的分片 LRU 缓存，支持 TTL 过期、容量淘汰、
+// 合并并发加载的 GetOrLoad 以及命中率等统计指标。
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultShards 默认分片数
+const DefaultShards = 16
+
+// ErrLoaderPanicked 加载函数 panic 时，等待同一次加载的其他调用方收到的错误
+var ErrLoaderPanicked = errors.New("cache: loader panicked")
+
+// Options 缓存选项，零值表示不限容量、不过期、使用 DefaultShards 个分片
+type Options struct {
+	// Capacity 总容量，平均分配到各个分片，分片满时淘汰最久未使用的条目；<= 0 表示不限制
+	Capacity int
+	// TTL 条目写入后的有效期，<= 0 表示不过期
+	TTL time.Duration
+	// Shards 分片数，默认 DefaultShards；每个分片独立加锁，降低并发访问时的锁竞争
+	Shards int
+}
+
+// Cache 泛型分片 LRU 缓存，可并发使用
+type Cache[K comparable, V any] struct {
+	shards []*shard[K, V]
+	seed   maphash.Seed
+	ttl    atomic.Int64 // 纳秒
+	flight flightGroup[K, V]
+	stats  counters
+	now    func() time.Time
+}
+
+// shard 单个分片，内部使用双向链表维护 LRU 顺序
+type shard[K comparable, V any] struct {
+	mu       sync.Mutex
+	items    map[K]*list.Element
+	order    *list.List // 队首为最近使用的条目
+	capacity int        // <= 0 表示不限制
+}
+
+// entry LRU 链表中的节点数据
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	storedAt time.Time
+}
+
+// New 创建缓存
+func New[K comparable, V any](opts Options) *Cache[K, V] {
+	n := opts.Shards
+	if n <= 0 {
+		n = DefaultShards
+	}
+	c := &Cache[K, V]{
+		shards: make([]*shard[K, V], n),
+		seed:   maphash.MakeSeed(),
+		now:    time.Now,
+	}
+	for i := range c.shards {
+		c.shards[i] = &shard[K, V]{items: make(map[K]*list.Element), order: list.New()}
+	}
+	c.Resize(opts.Capacity)
+	c.SetTTL(opts.TTL)
+	return c
+}
+
+// getShard 返回键所在的分片
+func (c *Cache[K, V]) getShard(key K) *shard[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// expired 判断条目是否过期
+func (c *Cache[K, V]) expired(e *entry[K, V], now time.Time) bool {
+	ttl := time.Duration(c.ttl.Load())
+	return ttl > 0 && now.Sub(e.storedAt) > ttl
+}
+
+// Get 读取缓存值，过期条目会被移除
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	now := c.now()
+	s := c.getShard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		c.stats.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if c.expired(e, now) {
+		s.remove(elem)
+		c.stats.expirations.Add(1)
+		c.stats.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	s.order.MoveToFront(elem)
+	c.stats.hits.Add(1)
+	return e.value, true
+}
+
+// Set 写入缓存值，分片满时淘汰最久未使用的条目
+func (c *Cache[K, V]) Set(key K, value V) {
+	now := c.now()
+	s := c.getShard(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value, e.storedAt = value, now
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.items[key] = s.order.PushFront(&entry[K, V]{key: key, value: value, storedAt: now})
+	c.stats.evictions.Add(uint64(s.evictOverflow()))
+}
+
+// GetOrLoad 读取缓存值，不存在时调用 loader 加载并写入缓存。
+// 同一个键的并发调用只执行一次 loader，其他调用方等待并共享结果；等待期间 ctx 结束时返回 ctx.Err()。
+// loader 返回错误时不写入缓存，需要缓存失败结果时应将其编码在 V 中。
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	return c.flight.do(ctx, key, func() (V, error) {
+		// 等待加载锁期间其他调用方可能已经写入
+		if value, ok := c.peek(key); ok {
+			return value, nil
+		}
+		c.stats.loads.Add(1)
+		value, err := loader(ctx, key)
+		if err != nil {
+			c.stats.loadErrors.Add(1)
+			return value, err
+		}
+		c.Set(key, value)
+		return value, nil
+	})
+}
+
+// peek 读取未过期的缓存值，不更新 LRU 顺序与统计
+func (c *Cache[K, V]) peek(key K) (V, bool) {
+	now := c.now()
+	s := c.getShard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		if e := elem.Value.(*entry[K, V]); !c.expired(e, now) {
+			return e.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete 移除缓存条目
+func (c *Cache[K, V]) Delete(key K) {
+	s := c.getShard(key)
+	s.mu.Lock()
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+	s.mu.Unlock()
+}
+
+// RemoveExpired 清理所有过期条目，返回清理数量；TTL 不大于 0 时不做任何事
+func (c *Cache[K, V]) RemoveExpired() int {
+	if c.ttl.Load() <= 0 {
+		return 0
+	}
+	now := c.now()
+	removed := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		// 从队尾（最久未使用）开始扫描
+		for elem := s.order.Back(); elem != nil; {
+			prev := elem.Prev()
+			if c.expired(elem.Value.(*entry[K, V]), now) {
+				s.remove(elem)
+				removed++
+			}
+			elem = prev
+		}
+		s.mu.Unlock()
+	}
+	c.stats.expirations.Add(uint64(removed))
+	return removed
+}
+
+// Clear 清空所有条目，统计指标保留
+func (c *Cache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.items = make(map[K]*list.Element)
+		s.order.Init()
+		s.mu.Unlock()
+	}
+}
+
+// Len 返回条目总数（含尚未清理的过期条目）
+func (c *Cache[K, V]) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		s.mu.Lock()
+		total += len(s.items)
+		s.mu.Unlock()
+	}
+	return total
+}
+
+// SetTTL 修改有效期，对已有条目同样生效（按写入时间计算）
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+// Resize 调整总容量，超出部分按 LRU 顺序淘汰；<= 0 表示不限制
+func (c *Cache[K, V]) Resize(capacity int) {
+	perShard := 0
+	if capacity > 0 {
+		perShard = (capacity + len(c.shards) - 1) / len(c.shards)
+	}
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.capacity = perShard
+		c.stats.evictions.Add(uint64(s.evictOverflow()))
+		s.mu.Unlock()
+	}
+}
+
+// evictOverflow 淘汰超出容量的条目并返回淘汰数量，调用方需持有锁
+func (s *shard[K, V]) evictOverflow() int {
+	if s.capacity <= 0 {
+		return 0
+	}
+	evicted := 0
+	for len(s.items) > s.capacity {
+		s.remove(s.order.Back())
+		evicted++
+	}
+	return evicted
+}
+
+// remove 从分片中删除节点，调用方需持有锁
+func (s *shard[K, V]) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.items, elem.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_LRUEviction(t *testing.T) {
+	c := New[string, int](Options{Capacity: 2, Shards: 1})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // a 成为最近使用
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v", v, ok)
+	}
+	if st := c.Stats(); st.Evictions != 1 {
+		t.Errorf("Evictions = %d, want 1", st.Evictions)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	now := time.Now()
+	c := New[string, string](Options{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	c.Set("stale", "x")
+	now = now.Add(2 * time.Minute)
+	c.Set("fresh", "y")
+
+	if _, ok := c.Get("stale"); ok {
+		t.Error("expired entry should not be returned")
+	}
+	if _, ok := c.Get("fresh"); !ok {
+		t.Error("fresh entry should be returned")
+	}
+
+	now = now.Add(30 * time.Second)
+	c.Set("late", "z")
+	now = now.Add(45 * time.Second) // fresh 已写入 75 秒，late 45 秒
+	if removed := c.RemoveExpired(); removed != 1 {
+		t.Errorf("RemoveExpired() = %d, want 1", removed)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+
+	// 修改 TTL 对已有条目同样生效
+	c.SetTTL(30 * time.Second)
+	if _, ok := c.Get("late"); ok {
+		t.Error("entry should expire after shortening TTL")
+	}
+}
+
+func TestCache_Resize(t *testing.T) {
+	c := New[int, int](Options{})
+	for i := 0; i < 200; i++ {
+		c.Set(i, i)
+	}
+	if c.Len() != 200 {
+		t.Fatalf("unbounded cache Len() = %d, want 200", c.Len())
+	}
+
+	c.Resize(DefaultShards * 2)
+	if c.Len() > DefaultShards*2 {
+		t.Errorf("Len() after Resize = %d, want <= %d", c.Len(), DefaultShards*2)
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Len() after Clear = %d", c.Len())
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	c := New[string, int](Options{Capacity: 100})
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(ctx, "key", func(context.Context, string) (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("GetOrLoad() = %d, %v", v, err)
+			}
+		}()
+	}
+
+	// 等待所有 goroutine 进入等待状态后再放行
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	if v, ok := c.Get("key"); !ok || v != 42 {
+		t.Errorf("loaded value should be cached: %d, %v", v, ok)
+	}
+
+	// 加载失败不写入缓存
+	loadErr := errors.New("db down")
+	if _, err := c.GetOrLoad(ctx, "missing", func(context.Context, string) (int, error) {
+		return 0, loadErr
+	}); !errors.Is(err, loadErr) {
+		t.Errorf("GetOrLoad() error = %v", err)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("failed load should not be cached")
+	}
+
+	st := c.Stats()
+	if st.Loads != 2 || st.LoadErrors != 1 {
+		t.Errorf("Stats() = %+v", st)
+	}
+}
+
+func TestCache_GetOrLoadWaiterContext(t *testing.T) {
+	c := New[string, int](Options{})
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go c.GetOrLoad(context.Background(), "slow", func(context.Context, string) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetOrLoad(ctx, "slow", func(context.Context, string) (int, error) {
+		t.Error("waiter should not run the loader")
+		return 0, nil
+	}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiter error = %v, want deadline exceeded", err)
+	}
+	close(release)
+}
+
+func TestStats_HitRate(t *testing.T) {
+	c := New[string, int](Options{})
+	if c.Stats().HitRate() != 0 {
+		t.Error("HitRate without access should be 0")
+	}
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	if got := c.Stats().HitRate(); got != 0.75 {
+		t.Errorf("HitRate() = %v, want 0.75", got)
+	}
+}
+
+func BenchmarkCache_Set(b *testing.B) {
+	c := New[string, int](Options{Capacity: 1000, TTL: time.Minute})
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%len(keys)], i)
+	}
+}
+
+func BenchmarkCache_ParallelGet(b *testing.B) {
+	c := New[string, int](Options{Capacity: 10000, TTL: time.Minute})
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		c.Set(keys[i], i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// flightCall 一次进行中的加载
+type flightCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// flightGroup 合并对同一个键的并发加载，只有首个调用者执行加载
+type flightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+// do 执行 fn，同一个键的并发调用共享同一次执行结果
+func (g *flightGroup[K, V]) do(ctx context.Context, key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+	call := &flightCall[V]{done: make(chan struct{}), err: ErrLoaderPanicked}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// fn panic 时等待方收到 ErrLoaderPanicked，panic 继续向调用方传播
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = fn()
+	return call.value, call.err
+}
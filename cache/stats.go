@@ -0,0 +1,39 @@
+package cache
+
+import "sync/atomic"
+
+// Stats 缓存统计指标，自创建以来累计
+type Stats struct {
+	Hits        uint64 // Get 命中次数（含 GetOrLoad 的首次查找）
+	Misses      uint64 // Get 未命中次数，含过期
+	Evictions   uint64 // 因容量不足被淘汰的条目数
+	Expirations uint64 // 因过期被移除的条目数
+	Loads       uint64 // GetOrLoad 实际调用 loader 的次数
+	LoadErrors  uint64 // loader 返回错误的次数
+}
+
+// HitRate 返回命中率，没有访问时返回 0
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// counters 统计计数器
+type counters struct {
+	hits, misses, evictions, expirations, loads, loadErrors atomic.Uint64
+}
+
+// Stats 返回统计指标快照
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        c.stats.hits.Load(),
+		Misses:      c.stats.misses.Load(),
+		Evictions:   c.stats.evictions.Load(),
+		Expirations: c.stats.expirations.Load(),
+		Loads:       c.stats.loads.Load(),
+		LoadErrors:  c.stats.loadErrors.Load(),
+	}
+}
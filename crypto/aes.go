@@ -3,13 +3,12 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
 	"math"
 	"sync"
+	"sync/atomic"
 )
 
 // EncodingType 定义编码类型
@@ -47,6 +46,13 @@ type AESEncryptor struct {
 	key        []byte
 	block      cipher.Block
 	blockMutex sync.RWMutex
+
+	// Nonce 策略与加密次数统计
+	nonceMutex  sync.RWMutex
+	nonceSource NonceSource
+	nonceLimit  *NonceLimit
+	messages    atomic.Uint64
+	limitWarned atomic.Bool
 }
 
 // NewAESEncryptor 创建新的 AES-GCM 加密器。
@@ -72,7 +78,18 @@ func NewAESEncryptorWithMode(key []byte, mode EncryptionMode) (*AESEncryptor, er
 	if keySize < 32 {
 		fmt.Printf("WARNING: Using AES-%d is less secure than AES-256. Consider upgrading to a 32-byte key.\n", keySize*8)
 	}
-	
+
+	return newAESEncryptor(key)
+}
+
+// newAESEncryptor 校验密钥并创建 AES-GCM 加密器，不输出弱密钥警告，
+// 供配置解密、密钥环等会反复创建加密器的内部路径使用
+func newAESEncryptor(key []byte) (*AESEncryptor, error) {
+	keySize := len(key)
+	if keySize != 16 && keySize != 24 && keySize != 32 {
+		return nil, errors.New("invalid key size: must be 16, 24, or 32 bytes")
+	}
+
 	// Check key entropy
 	entropy := calculateKeyEntropy(key)
 	if entropy < 3.0 {
@@ -120,9 +137,9 @@ func (e *AESEncryptor) EncryptWithOptions(plaintext []byte, encoding EncodingTyp
 		return "", err
 	}
 
-	// 生成一个随机的 Nonce。对于同一个密钥，每次加密的 Nonce 都必须是唯一的。
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	// 生成 Nonce（默认随机，可通过 SetNonceSource 切换为计数器）。对于同一个密钥，每次加密的 Nonce 都必须是唯一的。
+	nonce, err := e.nextNonce(aesGCM.NonceSize())
+	if err != nil {
 		return "", err
 	}
 
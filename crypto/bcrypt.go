@@ -1,7 +1,8 @@
+//go:build !cryptolite && !tinygo
+
 package crypto
 
 import (
-	"crypto/rand"
 	"errors"
 
 	"golang.org/x/crypto/bcrypt"
@@ -34,16 +35,6 @@ func CompareHashAndPassword(hashedPassword, password []byte) error {
 	return bcrypt.CompareHashAndPassword(hashedPassword, password)
 }
 
-// GenerateRandomBytes 生成指定长度的随机字节
-func GenerateRandomBytes(length int) ([]byte, error) {
-	b := make([]byte, length)
-	_, err := rand.Read(b)
-	if err != nil {
-		return nil, err
-	}
-	return b, nil
-}
-
 // BcryptHasher bcrypt哈希器
 type BcryptHasher struct {
 	cost BcryptCost
@@ -1,3 +1,5 @@
+//go:build !cryptolite && !tinygo
+
 package crypto
 
 import "time"
@@ -0,0 +1,226 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// bloomMagic 布隆过滤器序列化格式的魔数与版本
+var bloomMagic = []byte("BLM1")
+
+// bloomHeaderSize 序列化头部长度：魔数 + 哈希函数个数 + 位数 + 已添加元素数
+const bloomHeaderSize = 4 + 4 + 8 + 8
+
+var (
+	// ErrInvalidBloomParams 预期元素数或误判率不合法
+	ErrInvalidBloomParams = errors.New("invalid bloom filter parameters")
+	// ErrBloomFilterMismatch 合并的两个过滤器参数不同
+	ErrBloomFilterMismatch = errors.New("bloom filter parameters do not match")
+	// ErrInvalidBloomData 序列化数据格式错误
+	ErrInvalidBloomData = errors.New("invalid bloom filter data")
+)
+
+// BloomFilter 布隆过滤器，用于在内存中低成本地预筛选超大的撤销列表或泄露哈希列表
+// Test 返回 false 时元素一定不存在，返回 true 时可能存在，需要再查询权威数据源确认。
+// 可并发使用。
+type BloomFilter struct {
+	mu    sync.RWMutex
+	bits  []uint64
+	m     uint64 // 位数
+	k     uint32 // 哈希函数个数
+	count uint64 // 已添加元素数（含重复添加）
+}
+
+// NewBloomFilter 按预期元素数与目标误判率创建布隆过滤器，误判率取值范围为 (0, 1)
+func NewBloomFilter(expectedItems uint64, falsePositiveRate float64) (*BloomFilter, error) {
+	if expectedItems == 0 || falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("%w: items=%d, rate=%g", ErrInvalidBloomParams, expectedItems, falsePositiveRate)
+	}
+	n := float64(expectedItems)
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/n*math.Ln2))
+	return newBloomFilter(uint64(m), uint32(k)), nil
+}
+
+// newBloomFilter 创建指定位数与哈希函数个数的过滤器，位数向上取整为 64 的倍数
+func newBloomFilter(m uint64, k uint32) *BloomFilter {
+	words := (m + 63) / 64
+	return &BloomFilter{bits: make([]uint64, words), m: words * 64, k: k}
+}
+
+// bloomHashes 返回双重哈希（Kirsch-Mitzenmacher）的两个基础哈希，第 i 个位位置为 h1 + i*h2
+// 基于 SHA-256，结果与进程无关，序列化后可在其他进程中继续使用
+func bloomHashes(data []byte) (h1, h2 uint64) {
+	sum := sha256.Sum256(data)
+	h1 = binary.BigEndian.Uint64(sum[0:8])
+	h2 = binary.BigEndian.Uint64(sum[8:16]) | 1
+	return h1, h2
+}
+
+// Add 添加元素
+func (f *BloomFilter) Add(data []byte) {
+	h1, h2 := bloomHashes(data)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := uint64(0); i < uint64(f.k); i++ {
+		pos := (h1 + i*h2) % f.m
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+	f.count++
+}
+
+// AddString 添加字符串元素
+func (f *BloomFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test 判断元素是否可能存在
+func (f *BloomFilter) Test(data []byte) bool {
+	h1, h2 := bloomHashes(data)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for i := uint64(0); i < uint64(f.k); i++ {
+		pos := (h1 + i*h2) % f.m
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString 判断字符串元素是否可能存在
+func (f *BloomFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Count 返回已添加的元素数（重复添加会重复计数）
+func (f *BloomFilter) Count() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.count
+}
+
+// Cap 返回位数与哈希函数个数
+func (f *BloomFilter) Cap() (bitCount uint64, hashes uint32) {
+	return f.m, f.k
+}
+
+// EstimatedFalsePositiveRate 按当前已置位比例估算误判率
+func (f *BloomFilter) EstimatedFalsePositiveRate() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var set int
+	for _, w := range f.bits {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Union 将 other 中的元素合并到当前过滤器，两者必须使用相同参数创建
+func (f *BloomFilter) Union(other *BloomFilter) error {
+	if f == other {
+		return nil
+	}
+	// 先复制 other 再加锁 f，避免两个过滤器互相合并时死锁
+	other.mu.RLock()
+	m, k, count := other.m, other.k, other.count
+	otherBits := append([]uint64(nil), other.bits...)
+	other.mu.RUnlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.m != m || f.k != k {
+		return fmt.Errorf("%w: %d/%d vs %d/%d", ErrBloomFilterMismatch, f.m, f.k, m, k)
+	}
+	for i, w := range otherBits {
+		f.bits[i] |= w
+	}
+	f.count += count
+	return nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	_, err := f.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo 实现 io.WriterTo 接口，写出序列化数据
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	header := make([]byte, bloomHeaderSize)
+	copy(header, bloomMagic)
+	binary.BigEndian.PutUint32(header[4:8], f.k)
+	binary.BigEndian.PutUint64(header[8:16], f.m)
+	binary.BigEndian.PutUint64(header[16:24], f.count)
+	n, err := w.Write(header)
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	word := make([]byte, 8)
+	for _, b := range f.bits {
+		binary.BigEndian.PutUint64(word, b)
+		n, err = w.Write(word)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// ReadFrom 实现 io.ReaderFrom 接口，读取 WriteTo 写出的数据并替换当前内容
+func (f *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, bloomHeaderSize)
+	n, err := io.ReadFull(r, header)
+	read := int64(n)
+	if err != nil {
+		return read, fmt.Errorf("%w: %v", ErrInvalidBloomData, err)
+	}
+	if !bytes.Equal(header[:4], bloomMagic) {
+		return read, fmt.Errorf("%w: bad magic", ErrInvalidBloomData)
+	}
+	k := binary.BigEndian.Uint32(header[4:8])
+	m := binary.BigEndian.Uint64(header[8:16])
+	if k == 0 || m == 0 || m%64 != 0 {
+		return read, fmt.Errorf("%w: bits=%d, hashes=%d", ErrInvalidBloomData, m, k)
+	}
+
+	// 分块读取，避免按头部声明的长度一次性分配过大的内存
+	words := make([]uint64, 0, min(m/64, 1<<16))
+	word := make([]byte, 8)
+	for i := uint64(0); i < m/64; i++ {
+		n, err = io.ReadFull(r, word)
+		read += int64(n)
+		if err != nil {
+			return read, fmt.Errorf("%w: %v", ErrInvalidBloomData, err)
+		}
+		words = append(words, binary.BigEndian.Uint64(word))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bits, f.m, f.k = words, m, k
+	f.count = binary.BigEndian.Uint64(header[16:24])
+	return read, nil
+}
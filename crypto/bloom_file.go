@@ -0,0 +1,32 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bufio"
+	"os"
+)
+
+// SaveBloomFilter 将布隆过滤器写入文件，写入时先写临时文件再重命名
+func SaveBloomFilter(path string, f *BloomFilter) error {
+	data, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, string(data))
+}
+
+// LoadBloomFilter 从 SaveBloomFilter 写入的文件加载布隆过滤器
+func LoadBloomFilter(path string) (*BloomFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	f := &BloomFilter{}
+	if _, err := f.ReadFrom(bufio.NewReader(file)); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,125 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	const n = 10000
+	f, err := NewBloomFilter(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		f.AddString(fmt.Sprintf("revoked-%d", i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.TestString(fmt.Sprintf("revoked-%d", i)) {
+			t.Fatalf("added item %d not found", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if f.TestString(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / n; rate > 0.02 {
+		t.Errorf("false positive rate = %.4f, want about 0.01", rate)
+	}
+	if est := f.EstimatedFalsePositiveRate(); est > 0.02 {
+		t.Errorf("EstimatedFalsePositiveRate() = %.4f", est)
+	}
+	if f.Count() != n {
+		t.Errorf("Count() = %d", f.Count())
+	}
+
+	for _, tt := range []struct {
+		n    uint64
+		rate float64
+	}{{0, 0.01}, {10, 0}, {10, 1}} {
+		if _, err := NewBloomFilter(tt.n, tt.rate); !errors.Is(err, ErrInvalidBloomParams) {
+			t.Errorf("NewBloomFilter(%d, %g) error = %v", tt.n, tt.rate, err)
+		}
+	}
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	a, _ := NewBloomFilter(1000, 0.01)
+	b, _ := NewBloomFilter(1000, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.TestString("a") || !a.TestString("b") || a.Count() != 2 {
+		t.Error("union should contain items of both filters")
+	}
+
+	other, _ := NewBloomFilter(10, 0.1)
+	if err := a.Union(other); !errors.Is(err, ErrBloomFilterMismatch) {
+		t.Errorf("error = %v, want ErrBloomFilterMismatch", err)
+	}
+}
+
+func TestBloomFilter_Serialization(t *testing.T) {
+	f, _ := NewBloomFilter(1000, 0.001)
+	f.AddString("hash-1")
+	f.AddString("hash-2")
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored BloomFilter
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.TestString("hash-1") || !restored.TestString("hash-2") || restored.Count() != 2 {
+		t.Error("restored filter lost items")
+	}
+	m1, k1 := f.Cap()
+	if m2, k2 := restored.Cap(); m1 != m2 || k1 != k2 {
+		t.Errorf("Cap() = %d/%d, want %d/%d", m2, k2, m1, k1)
+	}
+
+	for name, bad := range map[string][]byte{
+		"magic":     append([]byte("XXXX"), data[4:]...),
+		"truncated": data[:len(data)-1],
+		"empty":     nil,
+	} {
+		if err := new(BloomFilter).UnmarshalBinary(bad); !errors.Is(err, ErrInvalidBloomData) {
+			t.Errorf("%s: error = %v, want ErrInvalidBloomData", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if n, err := f.WriteTo(&buf); err != nil || n != int64(len(data)) {
+		t.Errorf("WriteTo() = %d, %v", n, err)
+	}
+}
+
+func TestBloomFilter_SaveLoad(t *testing.T) {
+	f, _ := NewBloomFilter(1000, 0.01)
+	f.AddString("breached-password-hash")
+
+	path := filepath.Join(t.TempDir(), "revoked.bloom")
+	if err := SaveBloomFilter(path, f); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBloomFilter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.TestString("breached-password-hash") || loaded.TestString("unknown") {
+		t.Error("loaded filter does not match the saved one")
+	}
+}
@@ -0,0 +1,338 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+// 证书相关错误
+var (
+	ErrInvalidCertKeyType = errors.New("invalid certificate key type")
+	ErrNoCertificate      = errors.New("no certificate found in PEM data")
+)
+
+// CertKeyType 证书密钥类型
+type CertKeyType string
+
+const (
+	CertKeyECDSAP256 CertKeyType = "ecdsa-p256"
+	CertKeyECDSAP384 CertKeyType = "ecdsa-p384"
+	CertKeyRSA2048   CertKeyType = "rsa-2048"
+	CertKeyRSA4096   CertKeyType = "rsa-4096"
+	CertKeyEd25519   CertKeyType = "ed25519"
+)
+
+// defaultCertValidity 默认证书有效期
+const defaultCertValidity = 365 * 24 * time.Hour
+
+// CertificateOptions 证书与证书签名请求（CSR）的生成选项
+type CertificateOptions struct {
+	CommonName   string
+	Organization []string
+	// 主题备用名称（SAN）；两者都为空时 CommonName 作为 DNS 名称或 IP 写入 SAN
+	DNSNames    []string
+	IPAddresses []net.IP
+	// 有效期，默认一年；NotBefore 为空时为当前时间前一分钟，容忍时钟偏差
+	ValidFor  time.Duration
+	NotBefore time.Time
+	// 密钥类型，默认 ECDSA P-256；PrivateKey 不为 nil 时使用已有私钥，忽略 KeyType
+	KeyType    CertKeyType
+	PrivateKey stdcrypto.Signer
+	// 是否为 CA 证书，CA 证书可以作为 Parent 签发其他证书
+	IsCA bool
+	// 扩展用途，默认服务端与客户端认证
+	ExtKeyUsage []x509.ExtKeyUsage
+	// 签发者，为 nil 时生成自签名证书；CSR 忽略此项
+	Parent *Certificate
+}
+
+// Certificate 生成的证书及其私钥
+type Certificate struct {
+	Cert       *x509.Certificate
+	CertPEM    []byte
+	PrivateKey stdcrypto.Signer
+	KeyPEM     []byte // PKCS#8 PEM
+}
+
+// TLSCertificate 转换为 tls.Certificate，可直接用于 tls.Config
+func (c *Certificate) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+}
+
+// CertificateRequest 生成的证书签名请求及其私钥
+type CertificateRequest struct {
+	CSR        *x509.CertificateRequest
+	CSRPEM     []byte
+	PrivateKey stdcrypto.Signer
+	KeyPEM     []byte // PKCS#8 PEM
+}
+
+// GenerateCertificate 生成证书：未设置 Parent 时为自签名证书，否则由 Parent 签发
+func GenerateCertificate(opts CertificateOptions) (*Certificate, error) {
+	key, err := certPrivateKey(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	notBefore := opts.NotBefore
+	if notBefore.IsZero() {
+		notBefore = time.Now().Add(-time.Minute)
+	}
+	validFor := opts.ValidFor
+	if validFor <= 0 {
+		validFor = defaultCertValidity
+	}
+	extKeyUsage := opts.ExtKeyUsage
+	if extKeyUsage == nil {
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               certSubject(opts),
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  opts.IsCA,
+	}
+	template.DNSNames, template.IPAddresses = certSANs(opts)
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	if opts.IsCA {
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	}
+
+	parent, signer := template, key
+	if opts.Parent != nil {
+		parent, signer = opts.Parent.Cert, opts.Parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := marshalPrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{
+		Cert:       cert,
+		CertPEM:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		PrivateKey: key,
+		KeyPEM:     keyPEM,
+	}, nil
+}
+
+// GenerateSelfSignedCertificate 生成自签名证书，适用于内部服务与测试环境
+func GenerateSelfSignedCertificate(opts CertificateOptions) (*Certificate, error) {
+	opts.Parent = nil
+	return GenerateCertificate(opts)
+}
+
+// GenerateCSR 生成证书签名请求（PKCS#10），用于向 CA 申请证书
+func GenerateCSR(opts CertificateOptions) (*CertificateRequest, error) {
+	key, err := certPrivateKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.CertificateRequest{Subject: certSubject(opts)}
+	template.DNSNames, template.IPAddresses = certSANs(opts)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := marshalPrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateRequest{
+		CSR:        csr,
+		CSRPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+		PrivateKey: key,
+		KeyPEM:     keyPEM,
+	}, nil
+}
+
+// certPrivateKey 返回已有私钥或按密钥类型生成新私钥
+func certPrivateKey(opts CertificateOptions) (stdcrypto.Signer, error) {
+	if opts.PrivateKey != nil {
+		return opts.PrivateKey, nil
+	}
+	var (
+		key stdcrypto.Signer
+		err error
+	)
+	switch opts.KeyType {
+	case "", CertKeyECDSAP256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case CertKeyECDSAP384:
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case CertKeyRSA2048:
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case CertKeyRSA4096:
+		key, err = rsa.GenerateKey(rand.Reader, 4096)
+	case CertKeyEd25519:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCertKeyType, opts.KeyType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", opts.KeyType, err)
+	}
+	return key, nil
+}
+
+// certSubject 构造证书主题
+func certSubject(opts CertificateOptions) pkix.Name {
+	return pkix.Name{CommonName: opts.CommonName, Organization: opts.Organization}
+}
+
+// certSANs 返回主题备用名称，未设置时使用 CommonName
+func certSANs(opts CertificateOptions) ([]string, []net.IP) {
+	if len(opts.DNSNames) > 0 || len(opts.IPAddresses) > 0 || opts.CommonName == "" {
+		return opts.DNSNames, opts.IPAddresses
+	}
+	if ip := net.ParseIP(opts.CommonName); ip != nil {
+		return nil, []net.IP{ip}
+	}
+	return []string{opts.CommonName}, nil
+}
+
+// marshalPrivateKeyPEM 将私钥编码为 PKCS#8 PEM
+func marshalPrivateKeyPEM(key stdcrypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParseCertificatesPEM 解析 PEM 数据中的全部证书（如证书链），忽略其他类型的块
+func ParseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, ErrNoCertificate
+	}
+	return certs, nil
+}
+
+// CertificateInfo 证书的摘要信息
+type CertificateInfo struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string // 十六进制
+	DNSNames     []string
+	IPAddresses  []string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	IsCA         bool
+	SelfSigned   bool
+	KeyType      string // 如 "ECDSA P-256"、"RSA 2048"、"Ed25519"
+	Fingerprint  string // SHA-256 指纹，格式同 openssl x509 -fingerprint -sha256
+}
+
+// ExpiresIn 返回距离过期的时长，已过期时为负数
+func (i CertificateInfo) ExpiresIn(now time.Time) time.Duration {
+	return i.NotAfter.Sub(now)
+}
+
+// Expired 判断证书在 now 时是否已过期
+func (i CertificateInfo) Expired(now time.Time) bool {
+	return now.After(i.NotAfter)
+}
+
+// InspectCertificate 提取证书的主题、有效期、指纹等信息
+func InspectCertificate(cert *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.Text(16),
+		DNSNames:     cert.DNSNames,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		IsCA:         cert.IsCA,
+		KeyType:      certKeyDescription(cert.PublicKey),
+		Fingerprint:  CertificateFingerprint(cert),
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	// CheckSignatureFrom 要求签发者为 CA，非 CA 的自签名证书需要直接校验签名
+	info.SelfSigned = bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	return info
+}
+
+// CertificateFingerprint 返回证书 DER 编码的 SHA-256 指纹，大写十六进制，以冒号分隔
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hexSum := strings.ToUpper(hex.EncodeToString(sum[:]))
+	parts := make([]string, 0, len(sum))
+	for i := 0; i < len(hexSum); i += 2 {
+		parts = append(parts, hexSum[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// certKeyDescription 描述公钥类型与长度
+func certKeyDescription(pub interface{}) string {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", pub)
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSignedCertificate_KeyTypes(t *testing.T) {
+	for _, keyType := range []CertKeyType{CertKeyECDSAP256, CertKeyECDSAP384, CertKeyRSA2048, CertKeyEd25519} {
+		t.Run(string(keyType), func(t *testing.T) {
+			c, err := GenerateSelfSignedCertificate(CertificateOptions{
+				CommonName: "svc.internal",
+				KeyType:    keyType,
+				ValidFor:   24 * time.Hour,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.TLSCertificate(); err != nil {
+				t.Errorf("TLSCertificate() error = %v", err)
+			}
+
+			info := InspectCertificate(c.Cert)
+			if !info.SelfSigned || info.IsCA {
+				t.Errorf("SelfSigned = %v, IsCA = %v", info.SelfSigned, info.IsCA)
+			}
+			if len(info.DNSNames) != 1 || info.DNSNames[0] != "svc.internal" {
+				t.Errorf("CommonName should become the DNS SAN, got %v", info.DNSNames)
+			}
+			if d := info.ExpiresIn(time.Now()); d <= 23*time.Hour || d > 24*time.Hour {
+				t.Errorf("ExpiresIn() = %v", d)
+			}
+		})
+	}
+
+	if _, err := GenerateSelfSignedCertificate(CertificateOptions{KeyType: "dsa"}); !errors.Is(err, ErrInvalidCertKeyType) {
+		t.Errorf("error = %v, want ErrInvalidCertKeyType", err)
+	}
+}
+
+func TestGenerateCertificate_CAAndTLS(t *testing.T) {
+	ca, err := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "Test CA", IsCA: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := GenerateCertificate(CertificateOptions{
+		CommonName:  "localhost",
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		Parent:      ca,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := InspectCertificate(leaf.Cert)
+	if info.SelfSigned || info.Issuer != "CN=Test CA" || len(info.IPAddresses) != 1 {
+		t.Errorf("unexpected leaf info: %+v", info)
+	}
+
+	// 使用生成的证书完成一次真实的 TLS 握手
+	tlsCert, _ := leaf.TLSCertificate()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{tlsCert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("TLS request failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestGenerateCSR(t *testing.T) {
+	key, _ := GenerateSelfSignedCertificate(CertificateOptions{KeyType: CertKeyEd25519})
+	req, err := GenerateCSR(CertificateOptions{
+		CommonName:   "api.example.com",
+		Organization: []string{"Example"},
+		DNSNames:     []string{"api.example.com", "www.example.com"},
+		PrivateKey:   key.PrivateKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := req.CSR.CheckSignature(); err != nil {
+		t.Errorf("CSR signature invalid: %v", err)
+	}
+	if req.CSR.Subject.CommonName != "api.example.com" || len(req.CSR.DNSNames) != 2 {
+		t.Errorf("unexpected CSR: %+v", req.CSR.Subject)
+	}
+	if !strings.HasPrefix(string(req.CSRPEM), "-----BEGIN CERTIFICATE REQUEST-----") {
+		t.Error("CSRPEM should be a PEM certificate request")
+	}
+	if string(req.KeyPEM) != string(key.KeyPEM) {
+		t.Error("CSR should use the provided private key")
+	}
+}
+
+func TestParseCertificatesPEM(t *testing.T) {
+	a, _ := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "a"})
+	b, _ := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "b"})
+
+	bundle := append(append(append([]byte{}, a.CertPEM...), a.KeyPEM...), b.CertPEM...)
+	certs, err := ParseCertificatesPEM(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || certs[0].Subject.CommonName != "a" || certs[1].Subject.CommonName != "b" {
+		t.Errorf("parsed %d certificates", len(certs))
+	}
+
+	if _, err := ParseCertificatesPEM(a.KeyPEM); !errors.Is(err, ErrNoCertificate) {
+		t.Errorf("error = %v, want ErrNoCertificate", err)
+	}
+
+	sum := sha256.Sum256(a.Cert.Raw)
+	fp := CertificateFingerprint(a.Cert)
+	if strings.ReplaceAll(fp, ":", "") != strings.ToUpper(hex.EncodeToString(sum[:])) || len(fp) != 95 {
+		t.Errorf("CertificateFingerprint() = %s", fp)
+	}
+}
@@ -0,0 +1,205 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ConfigValuePrefix 加密配置值的前缀，格式为 enc:v1:{Base64(nonce || 密文 || tag)}
+const ConfigValuePrefix = "enc:v1:"
+
+// ErrNotEncryptedValue 配置值不是以 ConfigValuePrefix 开头的加密值
+var ErrNotEncryptedValue = errors.New("value is not an encrypted config value")
+
+// KeyProvider 提供解密配置值所需的主密钥
+type KeyProvider interface {
+	MasterKey() ([]byte, error)
+}
+
+// StaticKeyProvider 使用固定主密钥
+type StaticKeyProvider []byte
+
+// MasterKey 实现 KeyProvider 接口
+func (k StaticKeyProvider) MasterKey() ([]byte, error) {
+	return []byte(k), nil
+}
+
+// EnvKeyProvider 从环境变量读取 Base64 编码的主密钥
+type EnvKeyProvider string
+
+// MasterKey 实现 KeyProvider 接口
+func (e EnvKeyProvider) MasterKey() ([]byte, error) {
+	encoded := os.Getenv(string(e))
+	if encoded == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("environment variable %s is not valid base64: %w", string(e), err)
+	}
+	return key, nil
+}
+
+// IsEncryptedConfigValue 判断字符串是否为加密配置值
+func IsEncryptedConfigValue(value string) bool {
+	return strings.HasPrefix(value, ConfigValuePrefix)
+}
+
+// EncryptConfigValue 使用主密钥加密配置值，返回带 enc:v1: 前缀的字符串，可直接写入 YAML 等配置文件
+func EncryptConfigValue(masterKey []byte, plaintext string) (string, error) {
+	encryptor, err := newAESEncryptor(masterKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := encryptor.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return ConfigValuePrefix + ciphertext, nil
+}
+
+// DecryptConfigValue 解密带 enc:v1: 前缀的配置值
+func DecryptConfigValue(masterKey []byte, value string) (string, error) {
+	if !IsEncryptedConfigValue(value) {
+		return "", ErrNotEncryptedValue
+	}
+	encryptor, err := newAESEncryptor(masterKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := encryptor.Decrypt(strings.TrimPrefix(value, ConfigValuePrefix))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// DecryptStructFields 遍历已加载的配置结构体，将所有 enc:v1: 前缀的字符串原地解密
+// v 必须是指向结构体的指针；支持嵌套结构体、指针、切片、数组以及 map 中的字符串值
+// 未加密的值保持不变，任一值解密失败时返回带字段路径的错误
+func DecryptStructFields(v interface{}, provider KeyProvider) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("DecryptStructFields requires a non-nil pointer to a struct")
+	}
+	if provider == nil {
+		return errors.New("key provider cannot be nil")
+	}
+
+	key, err := provider.MasterKey()
+	if err != nil {
+		return fmt.Errorf("failed to load master key: %w", err)
+	}
+	encryptor, err := newAESEncryptor(key)
+	if err != nil {
+		return err
+	}
+
+	d := &configDecrypter{encryptor: encryptor}
+	return d.walk(rv.Elem(), rv.Elem().Type().Name())
+}
+
+// configDecrypter 递归解密配置值
+type configDecrypter struct {
+	encryptor *AESEncryptor
+}
+
+// decrypt 解密单个值，未加密的值原样返回
+func (d *configDecrypter) decrypt(value, path string) (string, bool, error) {
+	if !IsEncryptedConfigValue(value) {
+		return value, false, nil
+	}
+	plaintext, err := d.encryptor.Decrypt(strings.TrimPrefix(value, ConfigValuePrefix))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+	return string(plaintext), true, nil
+}
+
+// walk 递归处理任意值
+func (d *configDecrypter) walk(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		plain, changed, err := d.decrypt(v.String(), path)
+		if err != nil {
+			return err
+		}
+		if changed {
+			v.SetString(plain)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			return d.walkInterface(v, path)
+		}
+		return d.walk(v.Elem(), path)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := d.walk(v.Field(i), path+"."+t.Field(i).Name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return d.walkMap(v, path)
+	}
+	return nil
+}
+
+// walkInterface 处理 interface{} 中的值，字符串无法原地修改，需要整体替换
+func (d *configDecrypter) walkInterface(v reflect.Value, path string) error {
+	elem := v.Elem()
+	if elem.Kind() == reflect.String {
+		plain, changed, err := d.decrypt(elem.String(), path)
+		if err != nil {
+			return err
+		}
+		if changed && v.CanSet() {
+			v.Set(reflect.ValueOf(plain))
+		}
+		return nil
+	}
+	return d.walk(elem, path)
+}
+
+// walkMap 处理 map 的值，map 元素不可寻址，需要通过 SetMapIndex 写回
+func (d *configDecrypter) walkMap(v reflect.Value, path string) error {
+	if v.IsNil() {
+		return nil
+	}
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		elemPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+		value := iter.Value()
+
+		// 复制到可寻址的临时值后处理，再写回 map
+		tmp := reflect.New(value.Type()).Elem()
+		tmp.Set(value)
+		if err := d.walk(tmp, elemPath); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, tmp)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func testMasterKey() []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i*7 + 3)
+	}
+	return key
+}
+
+func TestEncryptDecryptConfigValue(t *testing.T) {
+	key := testMasterKey()
+
+	enc, err := EncryptConfigValue(key, "s3cr3t-password")
+	if err != nil {
+		t.Fatalf("EncryptConfigValue() error = %v", err)
+	}
+	if !strings.HasPrefix(enc, ConfigValuePrefix) {
+		t.Fatalf("encrypted value should start with %q, got %q", ConfigValuePrefix, enc)
+	}
+
+	plain, err := DecryptConfigValue(key, enc)
+	if err != nil || plain != "s3cr3t-password" {
+		t.Errorf("DecryptConfigValue() = %q, %v", plain, err)
+	}
+
+	if _, err := DecryptConfigValue(key, "plain-value"); err != ErrNotEncryptedValue {
+		t.Errorf("expected ErrNotEncryptedValue, got %v", err)
+	}
+}
+
+func TestConfigValue_NoStdoutWarning(t *testing.T) {
+	key := testMasterKey()[:16] // AES-128 会触发 NewAESEncryptor 的弱密钥警告
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	enc, encErr := EncryptConfigValue(key, "v")
+	_, decErr := DecryptConfigValue(key, enc)
+	ring := NewKeyRing()
+	addErr := ring.AddKey("k1", key)
+	os.Stdout = stdout
+	w.Close()
+
+	out, _ := io.ReadAll(r)
+	if encErr != nil || decErr != nil || addErr != nil {
+		t.Fatalf("errors = %v, %v, %v", encErr, decErr, addErr)
+	}
+	if len(out) > 0 {
+		t.Errorf("config helpers wrote to stdout: %q", out)
+	}
+}
+
+func TestDecryptStructFields(t *testing.T) {
+	key := testMasterKey()
+	mustEnc := func(s string) string {
+		v, err := EncryptConfigValue(key, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	type Database struct {
+		Host     string
+		Password string
+	}
+	type Config struct {
+		Name     string
+		DB       Database
+		Replica  *Database
+		Tokens   []string
+		Secrets  map[string]string
+		Extra    map[string]interface{}
+		internal string
+	}
+
+	cfg := Config{
+		Name:     "app",
+		DB:       Database{Host: "localhost", Password: mustEnc("db-pass")},
+		Replica:  &Database{Password: mustEnc("replica-pass")},
+		Tokens:   []string{mustEnc("t1"), "plain"},
+		Secrets:  map[string]string{"api": mustEnc("api-key")},
+		Extra:    map[string]interface{}{"nested": mustEnc("nested-secret"), "count": 3},
+		internal: mustEnc("untouched"),
+	}
+
+	if err := DecryptStructFields(&cfg, StaticKeyProvider(key)); err != nil {
+		t.Fatalf("DecryptStructFields() error = %v", err)
+	}
+
+	if cfg.DB.Password != "db-pass" || cfg.Replica.Password != "replica-pass" {
+		t.Errorf("nested passwords not decrypted: %+v %+v", cfg.DB, cfg.Replica)
+	}
+	if cfg.Tokens[0] != "t1" || cfg.Tokens[1] != "plain" {
+		t.Errorf("Tokens = %v", cfg.Tokens)
+	}
+	if cfg.Secrets["api"] != "api-key" {
+		t.Errorf("Secrets[api] = %q", cfg.Secrets["api"])
+	}
+	if cfg.Extra["nested"] != "nested-secret" || cfg.Extra["count"] != 3 {
+		t.Errorf("Extra = %v", cfg.Extra)
+	}
+	if !IsEncryptedConfigValue(cfg.internal) {
+		t.Error("unexported fields should be left alone")
+	}
+}
+
+func TestDecryptStructFields_Errors(t *testing.T) {
+	type Config struct{ Password string }
+
+	if err := DecryptStructFields(Config{}, StaticKeyProvider(testMasterKey())); err == nil {
+		t.Error("expected error for non-pointer argument")
+	}
+
+	cfg := Config{Password: ConfigValuePrefix + "bm90LXZhbGlk"}
+	err := DecryptStructFields(&cfg, StaticKeyProvider(testMasterKey()))
+	if err == nil || !strings.Contains(err.Error(), "Config.Password") {
+		t.Errorf("expected error mentioning field path, got %v", err)
+	}
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	key := testMasterKey()
+	t.Setenv("TEST_CONFIG_MASTER_KEY", base64.StdEncoding.EncodeToString(key))
+
+	got, err := EnvKeyProvider("TEST_CONFIG_MASTER_KEY").MasterKey()
+	if err != nil || string(got) != string(key) {
+		t.Errorf("MasterKey() = %v, %v", got, err)
+	}
+
+	if _, err := EnvKeyProvider("TEST_CONFIG_MISSING_KEY").MasterKey(); err == nil {
+		t.Error("expected error for missing environment variable")
+	}
+}
@@ -1,14 +1,18 @@
+// Package crypto 提供对称加密、哈希、密码哈希、密钥管理与签名等工具。
+//
+// 使用 cryptolite 构建标签（TinyGo 下自动启用）时只编译精简子集：AES-GCM 加密、
+// SHA-2 哈希、HMAC 与安全随机数，不依赖 golang.org/x/crypto，也不读写文件，
+// 可嵌入 WASM 边缘函数：
+//
+//	GOOS=js GOARCH=wasm go build -tags cryptolite ./...
+//	tinygo build -target wasm ./...
 package crypto
 
 import (
-	"regexp"
 	"sync"
 )
 
 var (
-	// 预编译正则表达式
-	nonAlphanumericRegex = regexp.MustCompile(`[^a-zA-Z0-9]`)
-
 	// 对象池，用于加密和解密操作的缓冲区
 	bufferPool = sync.Pool{
 		New: func() interface{} {
@@ -17,4 +21,4 @@ var (
 			return &buf
 		},
 	}
-)
\ No newline at end of file
+)
@@ -1,133 +1,13 @@
+//go:build !cryptolite && !tinygo
+
 package crypto
 
 import (
 	"bytes"
-	"encoding/base64"
 	"fmt"
 	"testing"
 )
 
-func TestNewAESEncryptor(t *testing.T) {
-	// 测试有效密钥长度
-	validKeys := []int{16, 24, 32}
-	for _, length := range validKeys {
-		key := make([]byte, length)
-		// Fill with some entropy to pass validation
-		for i := range key {
-			key[i] = byte(i % 256)
-		}
-		encryptor, err := NewAESEncryptor(key)
-		if err != nil {
-			t.Errorf("Failed to create encryptor with key length %d: %v", length, err)
-		}
-		if encryptor == nil {
-			t.Errorf("Encryptor is nil for key length %d", length)
-		}
-	}
-
-	// 测试无效密钥长度
-	invalidKey := make([]byte, 20)
-	_, err := NewAESEncryptor(invalidKey)
-	if err == nil {
-		t.Error("Expected error for invalid key size")
-	}
-}
-
-func TestAESEncryptor_EncryptDecrypt(t *testing.T) {
-	key := make([]byte, 32)
-	// Fill with some entropy to pass validation
-	for i := range key {
-		key[i] = byte(i % 256)
-	}
-	encryptor, _ := NewAESEncryptor(key)
-
-	// 测试加密解密
-	plaintext := []byte("Hello, World!")
-	ciphertext, err := encryptor.Encrypt(plaintext)
-	if err != nil {
-		t.Fatalf("Encryption failed: %v", err)
-	}
-
-	// 验证密文不等于明文
-	if base64.StdEncoding.EncodeToString(plaintext) == ciphertext {
-		t.Error("Ciphertext should not equal plaintext")
-	}
-
-	// 测试解密
-	decrypted, err := encryptor.Decrypt(ciphertext)
-	if err != nil {
-		t.Fatalf("Decryption failed: %v", err)
-	}
-
-	// 验证解密后的文本等于原文
-	if !bytes.Equal(decrypted, plaintext) {
-		t.Error("Decrypted text does not match original")
-	}
-
-	// 测试无效密文
-	_, err = encryptor.Decrypt("invalid-base64")
-	if err == nil {
-		t.Error("Expected error for invalid base64")
-	}
-}
-
-
-func TestAESEncryptor_URLSafeEncoding(t *testing.T) {
-	key := make([]byte, 32)
-	// Fill with some entropy to pass validation
-	for i := range key {
-		key[i] = byte(i % 256)
-	}
-	encryptor, _ := NewAESEncryptor(key)
-
-	// 测试 URL 安全编码
-	plaintext := []byte("Hello, URL-safe encoding!")
-	ciphertext, err := encryptor.EncryptWithOptions(plaintext, EncodingURLSafe)
-	if err != nil {
-		t.Fatalf("URL-safe encryption failed: %v", err)
-	}
-
-	// 验证使用 URL 安全编码
-	for _, c := range ciphertext {
-		if c == '+' || c == '/' {
-			t.Error("URL-safe encoding should not contain '+' or '/'")
-			break
-		}
-	}
-
-	// 测试 URL 安全解密
-	decrypted, err := encryptor.DecryptWithOptions(ciphertext, EncodingURLSafe)
-	if err != nil {
-		t.Fatalf("URL-safe decryption failed: %v", err)
-	}
-
-	// 验证解密后的文本等于原文
-	if !bytes.Equal(decrypted, plaintext) {
-		t.Error("URL-safe decrypted text does not match original")
-	}
-}
-
-func TestHashFunctions(t *testing.T) {
-	data := []byte("test data")
-
-	// 测试 SHA256
-	sha256Hash := HashSHA256(data)
-	if len(sha256Hash) != 32 { // SHA256 produces 32 bytes
-		t.Error("SHA256 hash length incorrect")
-	}
-
-	// 测试 SHA512
-	sha512Hash := HashSHA512(data)
-	if len(sha512Hash) != 64 { // SHA512 produces 64 bytes
-		t.Error("SHA512 hash length incorrect")
-	}
-
-	// 验证相同输入产生相同哈希
-	if !bytes.Equal(HashSHA256(data), HashSHA256(data)) {
-		t.Error("SHA256 hash not consistent")
-	}
-}
-
 func TestPasswordPolicy(t *testing.T) {
 	policy := NewDefaultPasswordPolicy()
 
@@ -224,91 +104,6 @@ func TestHashPasswordWithCost(t *testing.T) {
 	}
 }
 
-func TestSecureCompare(t *testing.T) {
-	a := []byte("test-string")
-	b := []byte("test-string")
-	c := []byte("different-string")
-
-	// 测试相同的字节切片
-	if !SecureCompare(a, b) {
-		t.Error("Identical byte slices should compare equal")
-	}
-
-	// 测试不同的字节切片
-	if SecureCompare(a, c) {
-		t.Error("Different byte slices should not compare equal")
-	}
-}
-
-func TestGenerateRandomBytes(t *testing.T) {
-	length := 32
-
-	// 生成随机字节
-	b1, err := GenerateRandomBytes(length)
-	if err != nil {
-		t.Fatalf("Failed to generate random bytes: %v", err)
-	}
-
-	// 验证长度
-	if len(b1) != length {
-		t.Errorf("Expected length %d, got %d", length, len(b1))
-	}
-
-	// 验证两次生成的随机字节不相同
-	b2, _ := GenerateRandomBytes(length)
-	if bytes.Equal(b1, b2) {
-		t.Error("Generated random bytes should not be equal")
-	}
-}
-
-func BenchmarkAESEncryptor_Encrypt(b *testing.B) {
-	key := make([]byte, 32)
-	// Fill with some entropy to pass validation
-	for i := range key {
-		key[i] = byte(i % 256)
-	}
-	encryptor, _ := NewAESEncryptor(key)
-	plaintext := []byte("Hello, World!")
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_, _ = encryptor.Encrypt(plaintext)
-	}
-}
-
-func BenchmarkAESEncryptor_Decrypt(b *testing.B) {
-	key := make([]byte, 32)
-	// Fill with some entropy to pass validation
-	for i := range key {
-		key[i] = byte(i % 256)
-	}
-	encryptor, _ := NewAESEncryptor(key)
-	plaintext := []byte("Hello, World!")
-	ciphertext, _ := encryptor.Encrypt(plaintext)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_, _ = encryptor.Decrypt(ciphertext)
-	}
-}
-
-
-func BenchmarkHashFunctions(b *testing.B) {
-	data := []byte("test data")
-
-	b.Run("SHA256", func(b *testing.B) {
-		for i := 0; i < b.N; i++ {
-			_ = HashSHA256(data)
-		}
-	})
-
-	b.Run("SHA512", func(b *testing.B) {
-		for i := 0; i < b.N; i++ {
-			_ = HashSHA512(data)
-		}
-	})
-}
-
 func BenchmarkPasswordHashingWithCost(b *testing.B) {
 	password := []byte("test-password")
 
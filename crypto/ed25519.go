@@ -0,0 +1,265 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Ed25519 相关错误
+var (
+	ErrInvalidEd25519PrivateKey = errors.New("invalid Ed25519 private key size")
+	ErrInvalidEd25519PublicKey  = errors.New("invalid Ed25519 public key size")
+	ErrInvalidSignatureEncoding = errors.New("invalid signature encoding")
+)
+
+// SignatureEncoding 定义签名的文本编码方式
+type SignatureEncoding int
+
+const (
+	// SignatureBase64 标准 Base64 编码
+	SignatureBase64 SignatureEncoding = iota
+	// SignatureBase64URL URL 安全的 Base64 编码（无填充），适合放在请求头或查询参数中
+	SignatureBase64URL
+	// SignatureHex 十六进制编码
+	SignatureHex
+)
+
+// Ed25519KeyPair Ed25519 密钥对
+type Ed25519KeyPair struct {
+	PublicKey  ed25519.PublicKey
+	PrivateKey ed25519.PrivateKey
+}
+
+// GenerateEd25519KeyPair 使用 crypto/rand 生成新的 Ed25519 密钥对
+func GenerateEd25519KeyPair() (*Ed25519KeyPair, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+	}
+	return &Ed25519KeyPair{PublicKey: pub, PrivateKey: priv}, nil
+}
+
+// Ed25519Sign 使用私钥对消息签名
+func Ed25519Sign(privateKey ed25519.PrivateKey, message []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidEd25519PrivateKey
+	}
+	return ed25519.Sign(privateKey, message), nil
+}
+
+// Ed25519Verify 使用公钥验证消息签名，公钥长度不正确时返回 false
+func Ed25519Verify(publicKey ed25519.PublicKey, message, signature []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(publicKey, message, signature)
+}
+
+// Ed25519SignEncoded 对消息签名并返回编码后的签名字符串
+func Ed25519SignEncoded(privateKey ed25519.PrivateKey, message []byte, encoding SignatureEncoding) (string, error) {
+	sig, err := Ed25519Sign(privateKey, message)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSignature(sig, encoding)
+}
+
+// Ed25519VerifyEncoded 验证编码后的签名字符串
+func Ed25519VerifyEncoded(publicKey ed25519.PublicKey, message []byte, signature string, encoding SignatureEncoding) (bool, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false, ErrInvalidEd25519PublicKey
+	}
+	sig, err := DecodeSignature(signature, encoding)
+	if err != nil {
+		return false, err
+	}
+	return ed25519.Verify(publicKey, message, sig), nil
+}
+
+// EncodeSignature 将签名编码为字符串
+func EncodeSignature(signature []byte, encoding SignatureEncoding) (string, error) {
+	switch encoding {
+	case SignatureBase64:
+		return base64.StdEncoding.EncodeToString(signature), nil
+	case SignatureBase64URL:
+		return base64.RawURLEncoding.EncodeToString(signature), nil
+	case SignatureHex:
+		return hex.EncodeToString(signature), nil
+	default:
+		return "", ErrInvalidSignatureEncoding
+	}
+}
+
+// DecodeSignature 将编码后的签名字符串还原为字节
+func DecodeSignature(signature string, encoding SignatureEncoding) ([]byte, error) {
+	signature = strings.TrimSpace(signature)
+	var (
+		sig []byte
+		err error
+	)
+	switch encoding {
+	case SignatureBase64:
+		sig, err = base64.StdEncoding.DecodeString(signature)
+	case SignatureBase64URL:
+		sig, err = base64.RawURLEncoding.DecodeString(signature)
+	case SignatureHex:
+		sig, err = hex.DecodeString(signature)
+	default:
+		return nil, ErrInvalidSignatureEncoding
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignatureEncoding, err)
+	}
+	return sig, nil
+}
+
+// Ed25519SignReader 对流式数据签名（Ed25519ph，先计算 SHA-512 摘要再签名）
+// 适用于无法一次性读入内存的大文件，签名只能用 Ed25519VerifyReader 验证
+func Ed25519SignReader(privateKey ed25519.PrivateKey, r io.Reader) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidEd25519PrivateKey
+	}
+	digest, err := sha512Digest(r)
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.Sign(nil, digest, &ed25519.Options{Hash: stdcrypto.SHA512})
+}
+
+// Ed25519VerifyReader 验证 Ed25519SignReader 生成的流式签名
+func Ed25519VerifyReader(publicKey ed25519.PublicKey, r io.Reader, signature []byte) (bool, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false, ErrInvalidEd25519PublicKey
+	}
+	digest, err := sha512Digest(r)
+	if err != nil {
+		return false, err
+	}
+	err = ed25519.VerifyWithOptions(publicKey, digest, signature, &ed25519.Options{Hash: stdcrypto.SHA512})
+	return err == nil, nil
+}
+
+// sha512Digest 计算流的 SHA-512 摘要
+func sha512Digest(r io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// SignFileDetached 对文件生成分离签名，并以 Base64 文本写入 sigPath
+// sigPath 为空时默认写入 filePath + ".sig"
+func SignFileDetached(privateKey ed25519.PrivateKey, filePath, sigPath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sig, err := Ed25519SignReader(privateKey, f)
+	if err != nil {
+		return err
+	}
+
+	if sigPath == "" {
+		sigPath = filePath + ".sig"
+	}
+	encoded := base64.StdEncoding.EncodeToString(sig) + "\n"
+	return os.WriteFile(sigPath, []byte(encoded), 0o644)
+}
+
+// VerifyFileDetached 使用分离签名文件验证文件
+// sigPath 为空时默认读取 filePath + ".sig"
+func VerifyFileDetached(publicKey ed25519.PublicKey, filePath, sigPath string) (bool, error) {
+	if sigPath == "" {
+		sigPath = filePath + ".sig"
+	}
+	encoded, err := os.ReadFile(sigPath)
+	if err != nil {
+		return false, err
+	}
+	sig, err := DecodeSignature(string(encoded), SignatureBase64)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	return Ed25519VerifyReader(publicKey, f, sig)
+}
+
+// MarshalEd25519PrivateKeyPEM 将私钥编码为 PKCS#8 PEM
+func MarshalEd25519PrivateKeyPEM(privateKey ed25519.PrivateKey) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidEd25519PrivateKey
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalEd25519PublicKeyPEM 将公钥编码为 PKIX PEM
+func MarshalEd25519PublicKeyPEM(publicKey ed25519.PublicKey) ([]byte, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidEd25519PublicKey
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParseEd25519PrivateKeyPEM 解析 PKCS#8 PEM 格式的 Ed25519 私钥
+func ParseEd25519PrivateKeyPEM(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("PEM does not contain an Ed25519 private key")
+	}
+	return priv, nil
+}
+
+// ParseEd25519PublicKeyPEM 解析 PKIX PEM 格式的 Ed25519 公钥
+func ParseEd25519PublicKeyPEM(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("PEM does not contain an Ed25519 public key")
+	}
+	return pub, nil
+}
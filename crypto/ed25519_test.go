@@ -0,0 +1,103 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEd25519SignVerify(t *testing.T) {
+	kp, err := GenerateEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("GenerateEd25519KeyPair() error = %v", err)
+	}
+
+	message := []byte("webhook payload")
+	sig, err := Ed25519Sign(kp.PrivateKey, message)
+	if err != nil {
+		t.Fatalf("Ed25519Sign() error = %v", err)
+	}
+	if !Ed25519Verify(kp.PublicKey, message, sig) {
+		t.Error("valid signature should verify")
+	}
+	if Ed25519Verify(kp.PublicKey, []byte("tampered"), sig) {
+		t.Error("signature should not verify for a different message")
+	}
+
+	if _, err := Ed25519Sign([]byte("short"), message); err != ErrInvalidEd25519PrivateKey {
+		t.Errorf("expected ErrInvalidEd25519PrivateKey, got %v", err)
+	}
+	if Ed25519Verify([]byte("short"), message, sig) {
+		t.Error("invalid public key should not verify")
+	}
+}
+
+func TestEd25519EncodedSignatures(t *testing.T) {
+	kp, _ := GenerateEd25519KeyPair()
+	message := []byte("artifact")
+
+	for _, enc := range []SignatureEncoding{SignatureBase64, SignatureBase64URL, SignatureHex} {
+		sig, err := Ed25519SignEncoded(kp.PrivateKey, message, enc)
+		if err != nil {
+			t.Fatalf("Ed25519SignEncoded(%d) error = %v", enc, err)
+		}
+		ok, err := Ed25519VerifyEncoded(kp.PublicKey, message, sig, enc)
+		if err != nil || !ok {
+			t.Errorf("Ed25519VerifyEncoded(%d) = %v, %v", enc, ok, err)
+		}
+	}
+
+	if _, err := Ed25519VerifyEncoded(kp.PublicKey, message, "not-hex", SignatureHex); err == nil {
+		t.Error("expected decode error for malformed signature")
+	}
+}
+
+func TestSignFileDetached(t *testing.T) {
+	kp, _ := GenerateEd25519KeyPair()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "artifact.tar.gz")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("data"), 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SignFileDetached(kp.PrivateKey, path, ""); err != nil {
+		t.Fatalf("SignFileDetached() error = %v", err)
+	}
+	ok, err := VerifyFileDetached(kp.PublicKey, path, "")
+	if err != nil || !ok {
+		t.Fatalf("VerifyFileDetached() = %v, %v", ok, err)
+	}
+
+	if err := os.WriteFile(path, []byte("modified"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ok, err = VerifyFileDetached(kp.PublicKey, path, "")
+	if err != nil || ok {
+		t.Errorf("modified file should fail verification, got %v, %v", ok, err)
+	}
+}
+
+func TestEd25519PEMRoundTrip(t *testing.T) {
+	kp, _ := GenerateEd25519KeyPair()
+
+	privPEM, err := MarshalEd25519PrivateKeyPEM(kp.PrivateKey)
+	if err != nil {
+		t.Fatalf("MarshalEd25519PrivateKeyPEM() error = %v", err)
+	}
+	pubPEM, err := MarshalEd25519PublicKeyPEM(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalEd25519PublicKeyPEM() error = %v", err)
+	}
+
+	priv, err := ParseEd25519PrivateKeyPEM(privPEM)
+	if err != nil || !bytes.Equal(priv, kp.PrivateKey) {
+		t.Errorf("private key round trip failed: %v", err)
+	}
+	pub, err := ParseEd25519PublicKeyPEM(pubPEM)
+	if err != nil || !bytes.Equal(pub, kp.PublicKey) {
+		t.Errorf("public key round trip failed: %v", err)
+	}
+}
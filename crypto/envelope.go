@@ -0,0 +1,403 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// 信封加密相关错误
+var (
+	ErrInvalidEnvelope       = errors.New("invalid envelope ciphertext")
+	ErrEnvelopeKeyNotFound   = errors.New("master key not found for envelope")
+	ErrInvalidMasterKeyID    = errors.New("master key ID must be 1 to 255 bytes")
+	ErrUnwrapNotSupported    = errors.New("key wrapper cannot unwrap data keys")
+	ErrDuplicateMasterKeyID  = errors.New("duplicate master key ID")
+	ErrInvalidWrappedDataKey = errors.New("invalid wrapped data key")
+)
+
+const (
+	envelopeVersion     = 1
+	envelopeDataKeySize = 32 // AES-256
+)
+
+// KeyWrapper 主密钥（KEK），负责包装与解包数据密钥（DEK）
+// 可以自行实现以对接 KMS 等外部密钥服务，主密钥本身不离开服务。
+type KeyWrapper interface {
+	// KeyID 主密钥标识，写入密文，用于在主密钥轮换后选择正确的主密钥解包
+	KeyID() string
+	// WrapKey 加密数据密钥
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey 解密 WrapKey 的结果
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// aesKeyWrapper 使用 AES-GCM 包装数据密钥，主密钥标识作为附加数据
+type aesKeyWrapper struct {
+	id  string
+	kek []byte
+}
+
+// NewAESKeyWrapper 创建以 AES 密钥为主密钥的 KeyWrapper，kek 长度须为 16、24 或 32 字节
+func NewAESKeyWrapper(id string, kek []byte) (KeyWrapper, error) {
+	if err := validateMasterKeyID(id); err != nil {
+		return nil, err
+	}
+	if n := len(kek); n != 16 && n != 24 && n != 32 {
+		return nil, errors.New("invalid key size: must be 16, 24, or 32 bytes")
+	}
+	return &aesKeyWrapper{id: id, kek: append([]byte(nil), kek...)}, nil
+}
+
+// KeyID 实现 KeyWrapper 接口
+func (w *aesKeyWrapper) KeyID() string { return w.id }
+
+// WrapKey 实现 KeyWrapper 接口，输出 nonce || 密文 || tag
+func (w *aesKeyWrapper) WrapKey(dataKey []byte) ([]byte, error) {
+	gcm, err := newGCM(w.kek)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(dataKey)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, out); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(out, out, dataKey, []byte(w.id)), nil
+}
+
+// UnwrapKey 实现 KeyWrapper 接口
+func (w *aesKeyWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(w.kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrInvalidWrappedDataKey
+	}
+	dataKey, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], []byte(w.id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWrappedDataKey, err)
+	}
+	return dataKey, nil
+}
+
+// rsaKeyWrapper 使用 RSA-OAEP(SHA-256) 包装数据密钥，主密钥标识作为 OAEP 标签
+type rsaKeyWrapper struct {
+	id   string
+	pub  *rsa.PublicKey
+	priv *rsa.PrivateKey
+}
+
+// NewRSAKeyWrapper 创建以 RSA 密钥对为主密钥的 KeyWrapper，可以包装和解包数据密钥
+func NewRSAKeyWrapper(id string, privateKey *rsa.PrivateKey) (KeyWrapper, error) {
+	if privateKey == nil {
+		return nil, ErrUnsupportedKeyType
+	}
+	w, err := NewRSAPublicKeyWrapper(id, &privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	w.(*rsaKeyWrapper).priv = privateKey
+	return w, nil
+}
+
+// NewRSAPublicKeyWrapper 创建只持有 RSA 公钥的 KeyWrapper，只能加密，解包时返回 ErrUnwrapNotSupported
+// 适用于上传服务只负责加密、由另一个服务持有私钥解密的场景
+func NewRSAPublicKeyWrapper(id string, publicKey *rsa.PublicKey) (KeyWrapper, error) {
+	if err := validateMasterKeyID(id); err != nil {
+		return nil, err
+	}
+	if publicKey == nil || publicKey.Size() < 256 {
+		return nil, fmt.Errorf("%w: RSA keys must be at least 2048 bits", ErrUnsupportedKeyType)
+	}
+	return &rsaKeyWrapper{id: id, pub: publicKey}, nil
+}
+
+// KeyID 实现 KeyWrapper 接口
+func (w *rsaKeyWrapper) KeyID() string { return w.id }
+
+// WrapKey 实现 KeyWrapper 接口
+func (w *rsaKeyWrapper) WrapKey(dataKey []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, w.pub, dataKey, []byte(w.id))
+}
+
+// UnwrapKey 实现 KeyWrapper 接口
+func (w *rsaKeyWrapper) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if w.priv == nil {
+		return nil, ErrUnwrapNotSupported
+	}
+	dataKey, err := w.priv.Decrypt(rand.Reader, wrapped, &rsa.OAEPOptions{Hash: stdcrypto.SHA256, Label: []byte(w.id)})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWrappedDataKey, err)
+	}
+	return dataKey, nil
+}
+
+// validateMasterKeyID 检查主密钥标识长度
+func validateMasterKeyID(id string) error {
+	if len(id) == 0 || len(id) > 0xFF {
+		return ErrInvalidMasterKeyID
+	}
+	return nil
+}
+
+// DataKey 数据密钥：Plaintext 用于加密数据，用完应调用 Destroy 清零；
+// Wrapped 为主密钥包装后的数据密钥，与密文一起保存
+type DataKey struct {
+	KeyID     string
+	Plaintext []byte
+	Wrapped   []byte
+}
+
+// Destroy 清零明文数据密钥
+func (k *DataKey) Destroy() {
+	clear(k.Plaintext)
+}
+
+// EnvelopeEncryptor 信封加密器：每次加密随机生成 AES-256 数据密钥，以 GCM 模式加密数据，
+// 再用主密钥包装数据密钥并与密文一起输出，解密时自动解包。避免用同一个密钥加密所有对象，
+// 轮换主密钥时只需重新包装数据密钥（Rewrap），无需重新加密数据。可并发使用。
+//
+// 密文格式：
+//
+//	版本(1) || 主密钥标识长度(1) || 主密钥标识 || 包装密钥长度(2) || 包装后的数据密钥 || nonce(12) || 密文 || tag
+//
+// 包装后的数据密钥被替换时解出的数据密钥不同，GCM 认证会失败，因此头部无需参与认证，
+// 轮换主密钥时可以只替换头部。
+type EnvelopeEncryptor struct {
+	mu       sync.RWMutex
+	primary  KeyWrapper
+	wrappers map[string]KeyWrapper
+}
+
+// NewEnvelopeEncryptor 创建信封加密器，primary 用于加密，others 为仅用于解密的历史主密钥
+func NewEnvelopeEncryptor(primary KeyWrapper, others ...KeyWrapper) (*EnvelopeEncryptor, error) {
+	e := &EnvelopeEncryptor{wrappers: make(map[string]KeyWrapper)}
+	for _, w := range append([]KeyWrapper{primary}, others...) {
+		if err := e.AddKey(w); err != nil {
+			return nil, err
+		}
+	}
+	e.primary = primary
+	return e, nil
+}
+
+// AddKey 添加用于解密的主密钥
+func (e *EnvelopeEncryptor) AddKey(w KeyWrapper) error {
+	if w == nil {
+		return fmt.Errorf("%w: nil key wrapper", ErrUnsupportedKeyType)
+	}
+	id := w.KeyID()
+	if err := validateMasterKeyID(id); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.wrappers[id]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateMasterKeyID, id)
+	}
+	e.wrappers[id] = w
+	return nil
+}
+
+// SetPrimary 切换用于加密的主密钥，主密钥须已添加
+func (e *EnvelopeEncryptor) SetPrimary(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	w, ok := e.wrappers[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrEnvelopeKeyNotFound, id)
+	}
+	e.primary = w
+	return nil
+}
+
+// PrimaryKeyID 返回当前用于加密的主密钥标识
+func (e *EnvelopeEncryptor) PrimaryKeyID() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.primary.KeyID()
+}
+
+// wrapper 返回指定标识的主密钥
+func (e *EnvelopeEncryptor) wrapper(id string) (KeyWrapper, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	w, ok := e.wrappers[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEnvelopeKeyNotFound, id)
+	}
+	return w, nil
+}
+
+// GenerateDataKey 生成数据密钥并用当前主密钥包装，适用于调用方自行加密大文件或流式数据的场景
+func (e *EnvelopeEncryptor) GenerateDataKey() (*DataKey, error) {
+	e.mu.RLock()
+	primary := e.primary
+	e.mu.RUnlock()
+
+	plaintext := make([]byte, envelopeDataKeySize)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return nil, err
+	}
+	wrapped, err := primary.WrapKey(plaintext)
+	if err != nil {
+		clear(plaintext)
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return &DataKey{KeyID: primary.KeyID(), Plaintext: plaintext, Wrapped: wrapped}, nil
+}
+
+// DecryptDataKey 使用指定主密钥解包数据密钥
+func (e *EnvelopeEncryptor) DecryptDataKey(keyID string, wrapped []byte) ([]byte, error) {
+	w, err := e.wrapper(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return w.UnwrapKey(wrapped)
+}
+
+// Encrypt 使用新的数据密钥加密数据，associatedData 可为 nil，解密时须提供相同的值
+// 加密数据库字段时可以把表名、主键作为附加数据，防止密文被挪到其他行使用
+func (e *EnvelopeEncryptor) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	dk, err := e.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+	defer dk.Destroy()
+
+	header, err := envelopeHeader(dk.KeyID, dk.Wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dk.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(header)+gcm.NonceSize(), len(header)+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(out, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt 解包密文中的数据密钥并解密数据
+func (e *EnvelopeEncryptor) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	env, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := e.DecryptDataKey(env.keyID, env.wrapped)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(dataKey)
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWrappedDataKey, err)
+	}
+	if len(env.body) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+	nonce, sealed := env.body[:gcm.NonceSize()], env.body[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, associatedData)
+}
+
+// EnvelopeKeyID 返回密文使用的主密钥标识，不解密数据
+func EnvelopeKeyID(ciphertext []byte) (string, error) {
+	env, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return env.keyID, nil
+}
+
+// NeedsRewrap 判断密文是否使用了非当前主密钥，格式错误的密文返回 false
+func (e *EnvelopeEncryptor) NeedsRewrap(ciphertext []byte) bool {
+	id, err := EnvelopeKeyID(ciphertext)
+	return err == nil && id != e.PrimaryKeyID()
+}
+
+// Rewrap 用当前主密钥重新包装密文中的数据密钥，数据部分保持不变，用于主密钥轮换后迁移存量密文；
+// 已使用当前主密钥时原样返回，changed 为 false。只解包数据密钥，不需要附加数据，也不接触明文数据。
+func (e *EnvelopeEncryptor) Rewrap(ciphertext []byte) (result []byte, changed bool, err error) {
+	env, err := parseEnvelope(ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	e.mu.RLock()
+	primary := e.primary
+	e.mu.RUnlock()
+	if env.keyID == primary.KeyID() {
+		return ciphertext, false, nil
+	}
+
+	dataKey, err := e.DecryptDataKey(env.keyID, env.wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	defer clear(dataKey)
+	wrapped, err := primary.WrapKey(dataKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	header, err := envelopeHeader(primary.KeyID(), wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(header, env.body...), true, nil
+}
+
+// envelopeHeader 构造密文头部
+func envelopeHeader(keyID string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) == 0 || len(wrapped) > 0xFFFF {
+		return nil, fmt.Errorf("%w: wrapped data key is %d bytes", ErrInvalidWrappedDataKey, len(wrapped))
+	}
+	header := make([]byte, 0, 4+len(keyID)+len(wrapped))
+	header = append(header, envelopeVersion, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+	return append(header, wrapped...), nil
+}
+
+// envelope 解析后的信封密文
+type envelope struct {
+	keyID   string
+	wrapped []byte
+	body    []byte // nonce || 密文 || tag
+}
+
+// parseEnvelope 解析信封密文头部
+func parseEnvelope(data []byte) (*envelope, error) {
+	if len(data) < 2 || data[0] != envelopeVersion {
+		return nil, ErrInvalidEnvelope
+	}
+	idLen := int(data[1])
+	pos := 2 + idLen
+	if idLen == 0 || len(data) < pos+2 {
+		return nil, ErrInvalidEnvelope
+	}
+	keyID := string(data[2:pos])
+	wrappedLen := int(binary.BigEndian.Uint16(data[pos:]))
+	pos += 2
+	if wrappedLen == 0 || len(data) < pos+wrappedLen {
+		return nil, ErrInvalidEnvelope
+	}
+	return &envelope{
+		keyID:   keyID,
+		wrapped: data[pos : pos+wrappedLen],
+		body:    data[pos+wrappedLen:],
+	}, nil
+}
@@ -0,0 +1,159 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestEnvelopeEncryptor(t *testing.T) {
+	aesWrapper, err := NewAESKeyWrapper("kek-aes", bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaWrapper, err := NewRSAKeyWrapper("kek-rsa", rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := bytes.Repeat([]byte("uploaded file "), 1024)
+	aad := []byte("users:42:id_number")
+
+	for _, w := range []KeyWrapper{aesWrapper, rsaWrapper} {
+		t.Run(w.KeyID(), func(t *testing.T) {
+			e, err := NewEnvelopeEncryptor(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first, err := e.Encrypt(plaintext, aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, _ := e.Encrypt(plaintext, aad)
+			if bytes.Equal(first, second) {
+				t.Error("each encryption should use a fresh data key")
+			}
+			if id, _ := EnvelopeKeyID(first); id != w.KeyID() {
+				t.Errorf("EnvelopeKeyID() = %q", id)
+			}
+
+			got, err := e.Decrypt(first, aad)
+			if err != nil || !bytes.Equal(got, plaintext) {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if _, err := e.Decrypt(first, []byte("users:43:id_number")); err == nil {
+				t.Error("decrypting with different associated data should fail")
+			}
+
+			// 替换包装后的数据密钥会导致认证失败
+			other, _ := e.Encrypt([]byte("x"), aad)
+			otherEnv, _ := parseEnvelope(other)
+			firstEnv, _ := parseEnvelope(first)
+			header, _ := envelopeHeader(w.KeyID(), otherEnv.wrapped)
+			if _, err := e.Decrypt(append(header, firstEnv.body...), aad); err == nil {
+				t.Error("swapping the wrapped data key should fail authentication")
+			}
+		})
+	}
+}
+
+func TestEnvelopeEncryptor_Rotation(t *testing.T) {
+	oldKEK, _ := NewAESKeyWrapper("kek-2024", bytes.Repeat([]byte{1}, 32))
+	newKEK, _ := NewAESKeyWrapper("kek-2025", bytes.Repeat([]byte{2}, 32))
+
+	e, err := NewEnvelopeEncryptor(oldKEK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, _ := e.Encrypt([]byte("secret"), nil)
+
+	if err := e.AddKey(newKEK); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddKey(newKEK); !errors.Is(err, ErrDuplicateMasterKeyID) {
+		t.Errorf("AddKey() duplicate error = %v", err)
+	}
+	if err := e.SetPrimary("kek-2025"); err != nil {
+		t.Fatal(err)
+	}
+	if !e.NeedsRewrap(ciphertext) {
+		t.Fatal("ciphertext under the old master key should need rewrap")
+	}
+
+	rewrapped, changed, err := e.Rewrap(ciphertext)
+	if err != nil || !changed {
+		t.Fatalf("Rewrap() = %v, %v", changed, err)
+	}
+	oldEnv, _ := parseEnvelope(ciphertext)
+	newEnv, _ := parseEnvelope(rewrapped)
+	if newEnv.keyID != "kek-2025" || !bytes.Equal(oldEnv.body, newEnv.body) {
+		t.Error("Rewrap should only replace the header")
+	}
+	if _, changed, _ := e.Rewrap(rewrapped); changed {
+		t.Error("ciphertext under the primary key should not change")
+	}
+
+	// 移除旧主密钥后仍可解密重新包装的密文
+	only, _ := NewEnvelopeEncryptor(newKEK)
+	if got, err := only.Decrypt(rewrapped, nil); err != nil || string(got) != "secret" {
+		t.Errorf("Decrypt() = %q, %v", got, err)
+	}
+	if _, err := only.Decrypt(ciphertext, nil); !errors.Is(err, ErrEnvelopeKeyNotFound) {
+		t.Errorf("Decrypt() with unknown master key error = %v", err)
+	}
+}
+
+func TestEnvelopeEncryptor_DataKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 上传服务只持有公钥
+	publicOnly, _ := NewRSAPublicKeyWrapper("kek-rsa", &rsaKey.PublicKey)
+	uploader, _ := NewEnvelopeEncryptor(publicOnly)
+
+	dk, err := uploader.GenerateDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey := append([]byte(nil), dk.Plaintext...)
+	dk.Destroy()
+	if !bytes.Equal(dk.Plaintext, make([]byte, len(dataKey))) {
+		t.Error("Destroy should zero the plaintext data key")
+	}
+	if _, err := uploader.DecryptDataKey(dk.KeyID, dk.Wrapped); !errors.Is(err, ErrUnwrapNotSupported) {
+		t.Errorf("public-key wrapper unwrap error = %v", err)
+	}
+
+	full, _ := NewRSAKeyWrapper("kek-rsa", rsaKey)
+	reader, _ := NewEnvelopeEncryptor(full)
+	got, err := reader.DecryptDataKey(dk.KeyID, dk.Wrapped)
+	if err != nil || !bytes.Equal(got, dataKey) {
+		t.Errorf("DecryptDataKey() error = %v", err)
+	}
+}
+
+func TestEnvelopeEncryptor_InvalidInput(t *testing.T) {
+	if _, err := NewAESKeyWrapper("", make([]byte, 32)); !errors.Is(err, ErrInvalidMasterKeyID) {
+		t.Errorf("empty key ID error = %v", err)
+	}
+	if _, err := NewAESKeyWrapper("kek", make([]byte, 20)); err == nil {
+		t.Error("expected error for invalid key size")
+	}
+
+	w, _ := NewAESKeyWrapper("kek", make([]byte, 32))
+	e, _ := NewEnvelopeEncryptor(w)
+	for _, data := range [][]byte{nil, {1}, {1, 3, 'k', 'e', 'k'}, {2, 0}} {
+		if _, err := e.Decrypt(data, nil); !errors.Is(err, ErrInvalidEnvelope) {
+			t.Errorf("Decrypt(%v) error = %v", data, err)
+		}
+	}
+}
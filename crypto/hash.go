@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/subtle"
@@ -21,4 +22,28 @@ func HashSHA512(data []byte) []byte {
 // SecureCompare 使用恒定时间比较两个字节切片
 func SecureCompare(a, b []byte) bool {
 	return subtle.ConstantTimeCompare(a, b) == 1
-}
\ No newline at end of file
+}
+
+// HMACSHA256 计算 HMAC-SHA256
+func HMACSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// HMACSHA512 计算 HMAC-SHA512
+func HMACSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSHA256 使用恒定时间比较校验 HMAC-SHA256
+func VerifyHMACSHA256(key, data, expected []byte) bool {
+	return hmac.Equal(HMACSHA256(key, data), expected)
+}
+
+// VerifyHMACSHA512 使用恒定时间比较校验 HMAC-SHA512
+func VerifyHMACSHA512(key, data, expected []byte) bool {
+	return hmac.Equal(HMACSHA512(key, data), expected)
+}
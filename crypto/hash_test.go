@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashFunctions(t *testing.T) {
+	data := []byte("test data")
+
+	// 测试 SHA256
+	sha256Hash := HashSHA256(data)
+	if len(sha256Hash) != 32 { // SHA256 produces 32 bytes
+		t.Error("SHA256 hash length incorrect")
+	}
+
+	// 测试 SHA512
+	sha512Hash := HashSHA512(data)
+	if len(sha512Hash) != 64 { // SHA512 produces 64 bytes
+		t.Error("SHA512 hash length incorrect")
+	}
+
+	// 验证相同输入产生相同哈希
+	if !bytes.Equal(HashSHA256(data), HashSHA256(data)) {
+		t.Error("SHA256 hash not consistent")
+	}
+}
+
+func TestSecureCompare(t *testing.T) {
+	a := []byte("test-string")
+	b := []byte("test-string")
+	c := []byte("different-string")
+
+	// 测试相同的字节切片
+	if !SecureCompare(a, b) {
+		t.Error("Identical byte slices should compare equal")
+	}
+
+	// 测试不同的字节切片
+	if SecureCompare(a, c) {
+		t.Error("Different byte slices should not compare equal")
+	}
+}
+
+func TestGenerateRandomBytes(t *testing.T) {
+	length := 32
+
+	// 生成随机字节
+	b1, err := GenerateRandomBytes(length)
+	if err != nil {
+		t.Fatalf("Failed to generate random bytes: %v", err)
+	}
+
+	// 验证长度
+	if len(b1) != length {
+		t.Errorf("Expected length %d, got %d", length, len(b1))
+	}
+
+	// 验证两次生成的随机字节不相同
+	b2, _ := GenerateRandomBytes(length)
+	if bytes.Equal(b1, b2) {
+		t.Error("Generated random bytes should not be equal")
+	}
+}
+
+func BenchmarkHashFunctions(b *testing.B) {
+	data := []byte("test data")
+
+	b.Run("SHA256", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = HashSHA256(data)
+		}
+	})
+
+	b.Run("SHA512", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = HashSHA512(data)
+		}
+	})
+}
+
+func TestHMAC(t *testing.T) {
+	// RFC 4231 测试用例 2
+	key := []byte("Jefe")
+	data := []byte("what do ya want for nothing?")
+
+	sum256 := HMACSHA256(key, data)
+	if got := hex.EncodeToString(sum256); got != "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843" {
+		t.Errorf("HMACSHA256() = %s", got)
+	}
+	sum512 := HMACSHA512(key, data)
+	if got := hex.EncodeToString(sum512); got != "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737" {
+		t.Errorf("HMACSHA512() = %s", got)
+	}
+
+	if !VerifyHMACSHA256(key, data, sum256) || !VerifyHMACSHA512(key, data, sum512) {
+		t.Error("valid MAC should verify")
+	}
+	if VerifyHMACSHA256([]byte("other"), data, sum256) {
+		t.Error("MAC with wrong key should not verify")
+	}
+	if VerifyHMACSHA512(key, data, sum512[:32]) {
+		t.Error("truncated MAC should not verify")
+	}
+}
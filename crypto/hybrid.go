@@ -0,0 +1,219 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 混合加密相关错误
+var (
+	ErrInvalidHybridEnvelope = errors.New("invalid hybrid encryption envelope")
+	ErrUnsupportedKeyType    = errors.New("unsupported key type for hybrid encryption")
+)
+
+// HybridAlgorithm 标识混合加密信封中包装数据密钥的方式
+type HybridAlgorithm byte
+
+const (
+	// HybridRSAOAEP 使用 RSA-OAEP(SHA-256) 加密数据密钥
+	HybridRSAOAEP HybridAlgorithm = iota + 1
+	// HybridX25519 使用临时 X25519 密钥协商 + HKDF-SHA256 派生数据密钥
+	HybridX25519
+	// HybridECDHP256 使用临时 P-256 密钥协商 + HKDF-SHA256 派生数据密钥
+	HybridECDHP256
+	// HybridECDHP384 使用临时 P-384 密钥协商 + HKDF-SHA256 派生数据密钥
+	HybridECDHP384
+	// HybridECDHP521 使用临时 P-521 密钥协商 + HKDF-SHA256 派生数据密钥
+	HybridECDHP521
+)
+
+const (
+	hybridVersion    = 1
+	hybridKeySize    = 32
+	hybridHeaderSize = 4 // 版本(1) + 算法(1) + 密钥材料长度(2)
+	hybridHKDFInfo   = "utils-pkg/crypto hybrid v1"
+)
+
+// HybridEncrypt 使用接收方公钥加密任意大小的数据
+// 随机生成 AES-256 数据密钥以 GCM 模式加密数据，再用接收方公钥包装数据密钥，输出单个信封：
+//
+//	版本(1) || 算法(1) || 密钥材料长度(2) || 密钥材料 || nonce(12) || 密文 || tag
+//
+// 密钥材料对 RSA 为加密后的数据密钥，对 ECDH 为临时公钥；信封头部作为 GCM 附加数据参与认证。
+// publicKey 支持 *rsa.PublicKey、*ecdh.PublicKey 和 *ecdsa.PublicKey。
+func HybridEncrypt(publicKey stdcrypto.PublicKey, plaintext []byte) ([]byte, error) {
+	var (
+		alg         HybridAlgorithm
+		keyMaterial []byte
+		dataKey     []byte
+		err         error
+	)
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		alg = HybridRSAOAEP
+		dataKey = make([]byte, hybridKeySize)
+		if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+			return nil, err
+		}
+		keyMaterial, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, dataKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap data key: %w", err)
+		}
+	case *ecdsa.PublicKey:
+		ecdhPub, convErr := pub.ECDH()
+		if convErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeyType, convErr)
+		}
+		return HybridEncrypt(ecdhPub, plaintext)
+	case *ecdh.PublicKey:
+		if alg, err = hybridAlgorithmForCurve(pub.Curve()); err != nil {
+			return nil, err
+		}
+		ephemeral, genErr := pub.Curve().GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		keyMaterial = ephemeral.PublicKey().Bytes()
+		if dataKey, err = deriveHybridKey(ephemeral, pub, keyMaterial, pub.Bytes()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	if len(keyMaterial) > 0xFFFF {
+		return nil, fmt.Errorf("key material too large: %d bytes", len(keyMaterial))
+	}
+	header := make([]byte, hybridHeaderSize, hybridHeaderSize+len(keyMaterial))
+	header[0] = hybridVersion
+	header[1] = byte(alg)
+	binary.BigEndian.PutUint16(header[2:], uint16(len(keyMaterial)))
+	header = append(header, keyMaterial...)
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+gcm.Overhead())
+	envelope = append(envelope, header...)
+	envelope = append(envelope, nonce...)
+	return gcm.Seal(envelope, nonce, plaintext, header), nil
+}
+
+// HybridDecrypt 使用接收方私钥解密 HybridEncrypt 生成的信封
+// privateKey 支持 *rsa.PrivateKey、*ecdh.PrivateKey 和 *ecdsa.PrivateKey，类型须与加密时的公钥匹配。
+func HybridDecrypt(privateKey stdcrypto.PrivateKey, envelope []byte) ([]byte, error) {
+	if len(envelope) < hybridHeaderSize || envelope[0] != hybridVersion {
+		return nil, ErrInvalidHybridEnvelope
+	}
+	alg := HybridAlgorithm(envelope[1])
+	keyLen := int(binary.BigEndian.Uint16(envelope[2:]))
+	if len(envelope) < hybridHeaderSize+keyLen {
+		return nil, ErrInvalidHybridEnvelope
+	}
+	header := envelope[:hybridHeaderSize+keyLen]
+	keyMaterial := header[hybridHeaderSize:]
+	body := envelope[len(header):]
+
+	if priv, ok := privateKey.(*ecdsa.PrivateKey); ok {
+		ecdhPriv, err := priv.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeyType, err)
+		}
+		privateKey = ecdhPriv
+	}
+
+	var dataKey []byte
+	switch priv := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if alg != HybridRSAOAEP {
+			return nil, fmt.Errorf("%w: envelope algorithm %d does not match RSA key", ErrInvalidHybridEnvelope, alg)
+		}
+		key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, keyMaterial, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+		}
+		dataKey = key
+	case *ecdh.PrivateKey:
+		expected, err := hybridAlgorithmForCurve(priv.Curve())
+		if err != nil {
+			return nil, err
+		}
+		if alg != expected {
+			return nil, fmt.Errorf("%w: envelope algorithm %d does not match ECDH key", ErrInvalidHybridEnvelope, alg)
+		}
+		ephemeral, err := priv.Curve().NewPublicKey(keyMaterial)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHybridEnvelope, err)
+		}
+		if dataKey, err = deriveHybridKey(priv, ephemeral, keyMaterial, priv.PublicKey().Bytes()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrInvalidHybridEnvelope
+	}
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, header)
+}
+
+// hybridAlgorithmForCurve 根据 ECDH 曲线确定算法标识
+func hybridAlgorithmForCurve(curve ecdh.Curve) (HybridAlgorithm, error) {
+	switch curve {
+	case ecdh.X25519():
+		return HybridX25519, nil
+	case ecdh.P256():
+		return HybridECDHP256, nil
+	case ecdh.P384():
+		return HybridECDHP384, nil
+	case ecdh.P521():
+		return HybridECDHP521, nil
+	}
+	return 0, ErrUnsupportedKeyType
+}
+
+// deriveHybridKey 执行 ECDH 协商并用 HKDF-SHA256 派生数据密钥
+// 临时公钥与接收方公钥作为 salt，将派生密钥绑定到本次协商的双方
+func deriveHybridKey(priv *ecdh.PrivateKey, peer *ecdh.PublicKey, ephemeralPub, recipientPub []byte) ([]byte, error) {
+	shared, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, ephemeralPub...), recipientPub...)
+	return hkdf.Key(sha256.New, shared, salt, hybridHKDFInfo, hybridKeySize)
+}
+
+// newGCM 使用给定密钥创建 AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,91 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestHybridEncryptDecrypt(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x25519Key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	p256Key, _ := ecdh.P256().GenerateKey(rand.Reader)
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	// 超过 RSA 单次加密上限的数据
+	plaintext := bytes.Repeat([]byte("partner payload "), 4096)
+
+	tests := []struct {
+		name string
+		pub  interface{}
+		priv interface{}
+		alg  HybridAlgorithm
+	}{
+		{"RSA", &rsaKey.PublicKey, rsaKey, HybridRSAOAEP},
+		{"X25519", x25519Key.PublicKey(), x25519Key, HybridX25519},
+		{"P256", p256Key.PublicKey(), p256Key, HybridECDHP256},
+		{"ECDSA-P384", &ecdsaKey.PublicKey, ecdsaKey, HybridECDHP384},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := HybridEncrypt(tt.pub, plaintext)
+			if err != nil {
+				t.Fatalf("HybridEncrypt() error = %v", err)
+			}
+			if HybridAlgorithm(envelope[1]) != tt.alg {
+				t.Errorf("algorithm = %d, want %d", envelope[1], tt.alg)
+			}
+
+			got, err := HybridDecrypt(tt.priv, envelope)
+			if err != nil {
+				t.Fatalf("HybridDecrypt() error = %v", err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Error("decrypted payload does not match")
+			}
+
+			// 篡改头部或密文都会导致认证失败
+			tampered := append([]byte(nil), envelope...)
+			tampered[len(tampered)-1] ^= 0x01
+			if _, err := HybridDecrypt(tt.priv, tampered); err == nil {
+				t.Error("expected error for tampered ciphertext")
+			}
+		})
+	}
+}
+
+func TestHybridDecrypt_Errors(t *testing.T) {
+	x25519Key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	p256Key, _ := ecdh.P256().GenerateKey(rand.Reader)
+
+	envelope, err := HybridEncrypt(x25519Key.PublicKey(), []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := HybridDecrypt(p256Key, envelope); !errors.Is(err, ErrInvalidHybridEnvelope) {
+		t.Errorf("expected ErrInvalidHybridEnvelope for mismatched key, got %v", err)
+	}
+	if _, err := HybridDecrypt(x25519Key, []byte{1, 2}); !errors.Is(err, ErrInvalidHybridEnvelope) {
+		t.Errorf("expected ErrInvalidHybridEnvelope for short envelope, got %v", err)
+	}
+	if _, err := HybridEncrypt("not a key", []byte("data")); !errors.Is(err, ErrUnsupportedKeyType) {
+		t.Errorf("expected ErrUnsupportedKeyType, got %v", err)
+	}
+
+	other, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	if _, err := HybridDecrypt(other, envelope); err == nil {
+		t.Error("expected error when decrypting with the wrong private key")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrSaltTooShort PBKDF2 的 salt 长度不足
+var ErrSaltTooShort = errors.New("salt must be at least 16 bytes")
+
+// KDFHash 密钥派生使用的哈希算法
+type KDFHash int
+
+const (
+	// KDFSHA256 使用 SHA-256
+	KDFSHA256 KDFHash = iota
+	// KDFSHA512 使用 SHA-512
+	KDFSHA512
+)
+
+// newHash 返回哈希构造函数
+func (h KDFHash) newHash() func() hash.Hash {
+	if h == KDFSHA512 {
+		return sha512.New
+	}
+	return sha256.New
+}
+
+// PBKDF2Params PBKDF2参数配置
+type PBKDF2Params struct {
+	// 迭代次数
+	Iterations int
+	// Salt长度，用于 GeneratePBKDF2Salt
+	SaltLength int
+	// Key长度
+	KeyLength int
+	// 哈希算法
+	Hash KDFHash
+}
+
+// DefaultPBKDF2Params 返回推荐的PBKDF2参数（OWASP 建议 PBKDF2-HMAC-SHA256 至少 600000 次迭代）
+func DefaultPBKDF2Params() *PBKDF2Params {
+	return &PBKDF2Params{
+		Iterations: 600000,
+		SaltLength: 16,
+		KeyLength:  32, // AES-256
+		Hash:       KDFSHA256,
+	}
+}
+
+// GeneratePBKDF2Salt 按参数生成随机 salt，params 为 nil 时使用默认参数
+func GeneratePBKDF2Salt(params *PBKDF2Params) ([]byte, error) {
+	if params == nil {
+		params = DefaultPBKDF2Params()
+	}
+	return GenerateRandomBytes(max(params.SaltLength, 16))
+}
+
+// DeriveKey 使用 PBKDF2 从口令派生密钥，例如把口令转换为 AES 密钥
+// salt 至少 16 字节且应随密文一起保存，params 为 nil 时使用默认参数
+func DeriveKey(password, salt []byte, params *PBKDF2Params) ([]byte, error) {
+	if params == nil {
+		params = DefaultPBKDF2Params()
+	}
+	if len(salt) < 16 {
+		return nil, ErrSaltTooShort
+	}
+	if params.Iterations <= 0 || params.KeyLength <= 0 {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: iterations=%d, key length=%d", params.Iterations, params.KeyLength)
+	}
+	return pbkdf2.Key(params.Hash.newHash(), string(password), salt, params.Iterations, params.KeyLength)
+}
+
+// HKDFExtract 执行 HKDF 的 extract 步骤，从输入密钥材料得到伪随机密钥（PRK）
+func HKDFExtract(h KDFHash, secret, salt []byte) ([]byte, error) {
+	return hkdf.Extract(h.newHash(), secret, salt)
+}
+
+// HKDFExpand 执行 HKDF 的 expand 步骤，从 PRK 派生 length 字节的密钥
+// 不同用途使用不同的 info，派生出的密钥相互独立
+func HKDFExpand(h KDFHash, prk []byte, info string, length int) ([]byte, error) {
+	return hkdf.Expand(h.newHash(), prk, info, length)
+}
+
+// DeriveSubkey 使用 HKDF-SHA256（extract + expand）从高熵密钥派生用途独立的子密钥
+// 适用于主密钥、ECDH 共享密钥等已具备足够熵的输入；口令请使用 DeriveKey
+func DeriveSubkey(secret, salt []byte, info string, length int) ([]byte, error) {
+	return hkdf.Key(sha256.New, secret, salt, info, length)
+}
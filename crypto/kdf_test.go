@@ -0,0 +1,81 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	params := &PBKDF2Params{Iterations: 1000, KeyLength: 32, Hash: KDFSHA256}
+
+	k1, err := DeriveKey([]byte("correct horse"), salt, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, _ := DeriveKey([]byte("correct horse"), salt, params)
+	if !bytes.Equal(k1, k2) || len(k1) != 32 {
+		t.Fatal("DeriveKey should be deterministic and return KeyLength bytes")
+	}
+	if k3, _ := DeriveKey([]byte("wrong horse"), salt, params); bytes.Equal(k1, k3) {
+		t.Error("different passwords produced the same key")
+	}
+	params.Hash = KDFSHA512
+	if k4, _ := DeriveKey([]byte("correct horse"), salt, params); bytes.Equal(k1, k4) {
+		t.Error("hash algorithm should affect the derived key")
+	}
+
+	// 派生出的密钥可以直接用于 AES
+	enc, err := NewAESEncryptor(k1)
+	if err != nil {
+		t.Fatalf("NewAESEncryptor() error = %v", err)
+	}
+	ct, _ := enc.Encrypt([]byte("secret"))
+	if pt, err := enc.Decrypt(ct); err != nil || string(pt) != "secret" {
+		t.Errorf("round trip = %q, %v", pt, err)
+	}
+}
+
+func TestDeriveKey_Validation(t *testing.T) {
+	if _, err := DeriveKey([]byte("pw"), []byte("short"), nil); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("expected ErrSaltTooShort, got %v", err)
+	}
+	if _, err := DeriveKey([]byte("pw"), make([]byte, 16), &PBKDF2Params{KeyLength: 32}); err == nil {
+		t.Error("expected error for zero iterations")
+	}
+	salt, err := GeneratePBKDF2Salt(nil)
+	if err != nil || len(salt) != 16 {
+		t.Errorf("GeneratePBKDF2Salt() = %d bytes, %v", len(salt), err)
+	}
+}
+
+func TestHKDF_RFC5869(t *testing.T) {
+	// RFC 5869 附录 A.1
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	wantPRK := "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5"
+	wantOKM := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	prk, err := HKDFExtract(KDFSHA256, ikm, salt)
+	if err != nil || hex.EncodeToString(prk) != wantPRK {
+		t.Fatalf("HKDFExtract() = %x, %v", prk, err)
+	}
+	okm, err := HKDFExpand(KDFSHA256, prk, string(info), 42)
+	if err != nil || hex.EncodeToString(okm) != wantOKM {
+		t.Fatalf("HKDFExpand() = %x, %v", okm, err)
+	}
+	if key, _ := DeriveSubkey(ikm, salt, string(info), 42); hex.EncodeToString(key) != wantOKM {
+		t.Errorf("DeriveSubkey() = %x", key)
+	}
+
+	a, _ := DeriveSubkey(ikm, nil, "encryption", 32)
+	b, _ := DeriveSubkey(ikm, nil, "signing", 32)
+	if bytes.Equal(a, b) {
+		t.Error("different info should produce independent keys")
+	}
+}
@@ -0,0 +1,238 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// keyIDSeparator 分隔密文中的密钥 ID 与 AES-GCM 密文
+const keyIDSeparator = ":"
+
+// 密钥环相关错误
+var (
+	ErrKeyNotFound     = errors.New("key not found in key ring")
+	ErrNoPrimaryKey    = errors.New("key ring has no primary key")
+	ErrMissingKeyID    = errors.New("ciphertext does not contain a key ID")
+	ErrInvalidKeyID    = errors.New("key ID must be non-empty and must not contain ':'")
+	ErrKeyAlreadyExist = errors.New("key ID already exists in key ring")
+)
+
+// keyRingEntry 密钥环中的单个密钥
+type keyRingEntry struct {
+	encryptor *AESEncryptor
+	retired   bool
+}
+
+// KeyRing 管理多个以 ID 标识的 AES 密钥，支持不中断业务的密钥轮换
+// 加密时使用主密钥并把密钥 ID 写入密文（格式为 {keyID}:{Base64 密文}），
+// 解密时根据密文中的 ID 自动选择密钥。KeyRing 实现了 Encryptor 接口。
+type KeyRing struct {
+	mu      sync.RWMutex
+	keys    map[string]*keyRingEntry
+	primary string
+}
+
+// NewKeyRing 创建空的密钥环
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: make(map[string]*keyRingEntry)}
+}
+
+// AddKey 添加密钥，第一个添加的密钥自动成为主密钥
+func (r *KeyRing) AddKey(id string, key []byte) error {
+	if id == "" || strings.Contains(id, keyIDSeparator) {
+		return ErrInvalidKeyID
+	}
+	encryptor, err := newAESEncryptor(key)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[id]; exists {
+		return ErrKeyAlreadyExist
+	}
+	r.keys[id] = &keyRingEntry{encryptor: encryptor}
+	if r.primary == "" {
+		r.primary = id
+	}
+	return nil
+}
+
+// SetPrimary 设置用于加密的主密钥，已退役的密钥不能设为主密钥
+func (r *KeyRing) SetPrimary(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if entry.retired {
+		return fmt.Errorf("key %s is retired and cannot be primary", id)
+	}
+	r.primary = id
+	return nil
+}
+
+// RetireKey 退役密钥：不再用于加密，但仍可解密旧数据，直到调用 RemoveKey
+// 主密钥不能退役，需先切换主密钥
+func (r *KeyRing) RetireKey(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if id == r.primary {
+		return fmt.Errorf("cannot retire primary key %s", id)
+	}
+	entry.retired = true
+	return nil
+}
+
+// RemoveKey 彻底移除密钥，使用该密钥加密的数据将无法再解密
+// 应在所有旧密文通过 Reencrypt 迁移完成后调用
+func (r *KeyRing) RemoveKey(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[id]; !ok {
+		return ErrKeyNotFound
+	}
+	if id == r.primary {
+		return fmt.Errorf("cannot remove primary key %s", id)
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+// PrimaryKeyID 返回当前主密钥 ID
+func (r *KeyRing) PrimaryKeyID() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.primary
+}
+
+// KeyIDs 返回所有密钥 ID（已排序）
+func (r *KeyRing) KeyIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.keys))
+	for id := range r.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IsRetired 判断密钥是否已退役
+func (r *KeyRing) IsRetired(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.keys[id]
+	return ok && entry.retired
+}
+
+// primaryEncryptor 返回主密钥 ID 与加密器
+func (r *KeyRing) primaryEncryptor() (string, *AESEncryptor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.keys[r.primary]
+	if !ok {
+		return "", nil, ErrNoPrimaryKey
+	}
+	return r.primary, entry.encryptor, nil
+}
+
+// encryptorFor 返回指定 ID 的加密器
+func (r *KeyRing) encryptorFor(id string) (*AESEncryptor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+	return entry.encryptor, nil
+}
+
+// KeyID 解析密文中的密钥 ID
+func KeyID(ciphertext string) (string, error) {
+	id, _, ok := strings.Cut(ciphertext, keyIDSeparator)
+	if !ok || id == "" {
+		return "", ErrMissingKeyID
+	}
+	return id, nil
+}
+
+// EncryptWithOptions 使用主密钥加密，并在密文前附加密钥 ID
+func (r *KeyRing) EncryptWithOptions(plaintext []byte, encoding EncodingType) (string, error) {
+	id, encryptor, err := r.primaryEncryptor()
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := encryptor.EncryptWithOptions(plaintext, encoding)
+	if err != nil {
+		return "", err
+	}
+	return id + keyIDSeparator + ciphertext, nil
+}
+
+// Encrypt 使用主密钥和标准 Base64 编码加密
+func (r *KeyRing) Encrypt(plaintext []byte) (string, error) {
+	return r.EncryptWithOptions(plaintext, EncodingStandard)
+}
+
+// DecryptWithOptions 根据密文中的密钥 ID 选择密钥解密
+func (r *KeyRing) DecryptWithOptions(ciphertext string, encoding EncodingType) ([]byte, error) {
+	id, err := KeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	encryptor, err := r.encryptorFor(id)
+	if err != nil {
+		return nil, err
+	}
+	return encryptor.DecryptWithOptions(ciphertext[len(id)+len(keyIDSeparator):], encoding)
+}
+
+// Decrypt 使用标准 Base64 编码解密
+func (r *KeyRing) Decrypt(ciphertext string) ([]byte, error) {
+	return r.DecryptWithOptions(ciphertext, EncodingStandard)
+}
+
+// NeedsReencryption 判断密文是否使用非主密钥加密
+func (r *KeyRing) NeedsReencryption(ciphertext string) bool {
+	id, err := KeyID(ciphertext)
+	return err == nil && id != r.PrimaryKeyID()
+}
+
+// Reencrypt 将旧密钥加密的密文用主密钥重新加密
+// 已使用主密钥加密的密文原样返回，changed 为 false
+func (r *KeyRing) Reencrypt(ciphertext string) (result string, changed bool, err error) {
+	if !r.NeedsReencryption(ciphertext) {
+		if _, err := KeyID(ciphertext); err != nil {
+			return "", false, err
+		}
+		return ciphertext, false, nil
+	}
+	plaintext, err := r.Decrypt(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	result, err = r.Encrypt(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return result, true, nil
+}
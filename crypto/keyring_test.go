@@ -0,0 +1,102 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func randomKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	ring := NewKeyRing()
+	if err := ring.AddKey("2024-01", randomKey(t)); err != nil {
+		t.Fatalf("AddKey() error = %v", err)
+	}
+
+	old, err := ring.Encrypt([]byte("card-number"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(old, "2024-01:") {
+		t.Fatalf("ciphertext should carry key ID, got %q", old)
+	}
+
+	// 轮换：添加新密钥并设为主密钥，旧密钥退役
+	if err := ring.AddKey("2024-07", randomKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.SetPrimary("2024-07"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.RetireKey("2024-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 旧密文仍可解密
+	plain, err := ring.Decrypt(old)
+	if err != nil || string(plain) != "card-number" {
+		t.Fatalf("Decrypt(old) = %q, %v", plain, err)
+	}
+	if !ring.NeedsReencryption(old) {
+		t.Error("old ciphertext should need re-encryption")
+	}
+
+	migrated, changed, err := ring.Reencrypt(old)
+	if err != nil || !changed {
+		t.Fatalf("Reencrypt() = %v, %v", changed, err)
+	}
+	if id, _ := KeyID(migrated); id != "2024-07" {
+		t.Errorf("re-encrypted key ID = %q, want 2024-07", id)
+	}
+	if again, changed, _ := ring.Reencrypt(migrated); changed || again != migrated {
+		t.Error("ciphertext under primary key should be returned unchanged")
+	}
+
+	// 迁移完成后移除旧密钥
+	if err := ring.RemoveKey("2024-01"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ring.Decrypt(old); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound after removal, got %v", err)
+	}
+	if plain, err := ring.Decrypt(migrated); err != nil || string(plain) != "card-number" {
+		t.Errorf("Decrypt(migrated) = %q, %v", plain, err)
+	}
+}
+
+func TestKeyRing_Errors(t *testing.T) {
+	ring := NewKeyRing()
+	if _, err := ring.Encrypt([]byte("x")); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf("expected ErrNoPrimaryKey, got %v", err)
+	}
+	if err := ring.AddKey("a:b", randomKey(t)); !errors.Is(err, ErrInvalidKeyID) {
+		t.Errorf("expected ErrInvalidKeyID, got %v", err)
+	}
+
+	_ = ring.AddKey("k1", randomKey(t))
+	if err := ring.AddKey("k1", randomKey(t)); !errors.Is(err, ErrKeyAlreadyExist) {
+		t.Errorf("expected ErrKeyAlreadyExist, got %v", err)
+	}
+	if err := ring.RetireKey("k1"); err == nil {
+		t.Error("primary key should not be retirable")
+	}
+	if err := ring.RemoveKey("k1"); err == nil {
+		t.Error("primary key should not be removable")
+	}
+	if _, err := ring.Decrypt("no-key-id"); !errors.Is(err, ErrMissingKeyID) {
+		t.Errorf("expected ErrMissingKeyID, got %v", err)
+	}
+
+	var _ Encryptor = ring
+}
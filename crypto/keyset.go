@@ -0,0 +1,391 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// KeyStatus 密钥在密钥集中的状态
+type KeyStatus string
+
+const (
+	// KeyStatusPrimary 主密钥，用于加密，同一时间只有一个
+	KeyStatusPrimary KeyStatus = "primary"
+	// KeyStatusEnabled 启用状态，只用于解密
+	KeyStatusEnabled KeyStatus = "enabled"
+	// KeyStatusDisabled 禁用状态，既不加密也不解密，可随时重新启用
+	KeyStatusDisabled KeyStatus = "disabled"
+)
+
+// KeyType 密钥类型
+type KeyType string
+
+const (
+	// KeyTypeAES128GCM 128 位 AES-GCM 密钥
+	KeyTypeAES128GCM KeyType = "AES128_GCM"
+	// KeyTypeAES256GCM 256 位 AES-GCM 密钥
+	KeyTypeAES256GCM KeyType = "AES256_GCM"
+)
+
+// keySize 返回密钥类型对应的长度
+func (t KeyType) keySize() (int, error) {
+	switch t {
+	case KeyTypeAES128GCM:
+		return 16, nil
+	case KeyTypeAES256GCM:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("unsupported key type %q", t)
+}
+
+const (
+	// keysetOutputPrefix 密文前缀版本号，后跟 4 字节密钥 ID
+	keysetOutputPrefix  byte = 0x01
+	keysetPrefixSize         = 5
+	keysetAssociatedTag      = "utils-pkg/crypto keyset v1"
+)
+
+// 密钥集相关错误
+var (
+	ErrKeysetKeyNotFound       = errors.New("key not found in keyset")
+	ErrKeysetNoPrimary         = errors.New("keyset has no primary key")
+	ErrKeysetKeyDisabled       = errors.New("key is disabled")
+	ErrInvalidKeysetCiphertext = errors.New("invalid keyset ciphertext")
+)
+
+// KeyInfo 密钥元信息，不包含密钥材料
+type KeyInfo struct {
+	ID        uint32    `json:"id"`
+	Type      KeyType   `json:"type"`
+	Status    KeyStatus `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// keysetKey 密钥集中的单个密钥
+type keysetKey struct {
+	KeyInfo
+	Material []byte `json:"material"`
+	aead     cipher.AEAD
+}
+
+// Keyset 统一管理多个带 ID、状态和类型的密钥，参考 Tink 的 Keyset 设计
+// 加密总是使用主密钥，密文格式为 0x01 || 密钥ID(4 字节，大端序) || nonce || 密文 || tag，
+// 解密时按密文中的密钥 ID 选择密钥。密钥集可序列化为由主密钥（master key）加密的 JSON，便于存储与分发。
+type Keyset struct {
+	mu   sync.RWMutex
+	keys map[uint32]*keysetKey
+}
+
+// NewKeyset 创建空的密钥集
+func NewKeyset() *Keyset {
+	return &Keyset{keys: make(map[uint32]*keysetKey)}
+}
+
+// GenerateKey 生成指定类型的新密钥并加入密钥集，返回密钥 ID
+// 新密钥为启用状态；密钥集中还没有主密钥时自动成为主密钥
+func (ks *Keyset) GenerateKey(keyType KeyType) (uint32, error) {
+	size, err := keyType.keySize()
+	if err != nil {
+		return 0, err
+	}
+	material := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, material); err != nil {
+		return 0, err
+	}
+	return ks.AddKey(keyType, material)
+}
+
+// AddKey 导入已有的密钥材料，返回随机分配的密钥 ID
+func (ks *Keyset) AddKey(keyType KeyType, material []byte) (uint32, error) {
+	size, err := keyType.keySize()
+	if err != nil {
+		return 0, err
+	}
+	if len(material) != size {
+		return 0, fmt.Errorf("%s requires a %d-byte key, got %d", keyType, size, len(material))
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	id, err := ks.newKeyID()
+	if err != nil {
+		return 0, err
+	}
+	key := &keysetKey{
+		KeyInfo: KeyInfo{
+			ID:        id,
+			Type:      keyType,
+			Status:    KeyStatusEnabled,
+			CreatedAt: time.Now().UTC(),
+		},
+		Material: append([]byte(nil), material...),
+	}
+	if key.aead, err = newGCM(key.Material); err != nil {
+		return 0, err
+	}
+	if ks.primaryLocked() == nil {
+		key.Status = KeyStatusPrimary
+	}
+	ks.keys[id] = key
+	return id, nil
+}
+
+// newKeyID 生成不重复的非零密钥 ID，调用方需持有写锁
+func (ks *Keyset) newKeyID() (uint32, error) {
+	var buf [4]byte
+	for i := 0; i < 16; i++ {
+		if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+			return 0, err
+		}
+		id := binary.BigEndian.Uint32(buf[:])
+		if _, exists := ks.keys[id]; id != 0 && !exists {
+			return id, nil
+		}
+	}
+	return 0, errors.New("failed to allocate a unique key ID")
+}
+
+// primaryLocked 返回主密钥，调用方需持有锁
+func (ks *Keyset) primaryLocked() *keysetKey {
+	for _, k := range ks.keys {
+		if k.Status == KeyStatusPrimary {
+			return k
+		}
+	}
+	return nil
+}
+
+// SetPrimary 将指定密钥设为主密钥，原主密钥降为启用状态
+func (ks *Keyset) SetPrimary(id uint32) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, ok := ks.keys[id]
+	if !ok {
+		return ErrKeysetKeyNotFound
+	}
+	if key.Status == KeyStatusDisabled {
+		return fmt.Errorf("%w: enable key %d before promoting it", ErrKeysetKeyDisabled, id)
+	}
+	if current := ks.primaryLocked(); current != nil {
+		current.Status = KeyStatusEnabled
+	}
+	key.Status = KeyStatusPrimary
+	return nil
+}
+
+// Enable 启用已禁用的密钥
+func (ks *Keyset) Enable(id uint32) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, ok := ks.keys[id]
+	if !ok {
+		return ErrKeysetKeyNotFound
+	}
+	if key.Status == KeyStatusDisabled {
+		key.Status = KeyStatusEnabled
+	}
+	return nil
+}
+
+// Disable 禁用密钥，主密钥不能禁用
+func (ks *Keyset) Disable(id uint32) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, ok := ks.keys[id]
+	if !ok {
+		return ErrKeysetKeyNotFound
+	}
+	if key.Status == KeyStatusPrimary {
+		return fmt.Errorf("cannot disable primary key %d", id)
+	}
+	key.Status = KeyStatusDisabled
+	return nil
+}
+
+// Destroy 删除密钥材料，主密钥不能删除
+func (ks *Keyset) Destroy(id uint32) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, ok := ks.keys[id]
+	if !ok {
+		return ErrKeysetKeyNotFound
+	}
+	if key.Status == KeyStatusPrimary {
+		return fmt.Errorf("cannot destroy primary key %d", id)
+	}
+	delete(ks.keys, id)
+	return nil
+}
+
+// PrimaryKeyID 返回主密钥 ID，没有主密钥时返回 0
+func (ks *Keyset) PrimaryKeyID() uint32 {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if k := ks.primaryLocked(); k != nil {
+		return k.ID
+	}
+	return 0
+}
+
+// Keys 返回所有密钥的元信息（按创建时间排序）
+func (ks *Keyset) Keys() []KeyInfo {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	infos := make([]KeyInfo, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		infos = append(infos, k.KeyInfo)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].CreatedAt.Equal(infos[j].CreatedAt) {
+			return infos[i].ID < infos[j].ID
+		}
+		return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+	})
+	return infos
+}
+
+// Encrypt 使用主密钥加密，associatedData 可为 nil
+func (ks *Keyset) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	ks.mu.RLock()
+	primary := ks.primaryLocked()
+	ks.mu.RUnlock()
+	if primary == nil {
+		return nil, ErrKeysetNoPrimary
+	}
+
+	nonceSize := primary.aead.NonceSize()
+	out := make([]byte, keysetPrefixSize+nonceSize, keysetPrefixSize+nonceSize+len(plaintext)+primary.aead.Overhead())
+	out[0] = keysetOutputPrefix
+	binary.BigEndian.PutUint32(out[1:], primary.ID)
+	nonce := out[keysetPrefixSize:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return primary.aead.Seal(out, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt 按密文中的密钥 ID 选择密钥解密，禁用的密钥不能解密
+func (ks *Keyset) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < keysetPrefixSize || ciphertext[0] != keysetOutputPrefix {
+		return nil, ErrInvalidKeysetCiphertext
+	}
+	id := binary.BigEndian.Uint32(ciphertext[1:])
+
+	ks.mu.RLock()
+	key, ok := ks.keys[id]
+	var status KeyStatus
+	if ok {
+		status = key.Status
+	}
+	ks.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrKeysetKeyNotFound, id)
+	}
+	if status == KeyStatusDisabled {
+		return nil, fmt.Errorf("%w: %d", ErrKeysetKeyDisabled, id)
+	}
+
+	body := ciphertext[keysetPrefixSize:]
+	nonceSize := key.aead.NonceSize()
+	if len(body) < nonceSize+key.aead.Overhead() {
+		return nil, ErrInvalidKeysetCiphertext
+	}
+	return key.aead.Open(nil, body[:nonceSize], body[nonceSize:], associatedData)
+}
+
+// keysetJSON 密钥集的序列化格式
+type keysetJSON struct {
+	Keys []*keysetKey `json:"keys"`
+}
+
+// MarshalEncrypted 将密钥集序列化为 JSON 并用主密钥（16/24/32 字节）以 AES-GCM 加密
+// 输出格式为 nonce || 密文 || tag，密钥材料永远不会以明文离开进程
+func (ks *Keyset) MarshalEncrypted(masterKey []byte) ([]byte, error) {
+	ks.mu.RLock()
+	data := keysetJSON{Keys: make([]*keysetKey, 0, len(ks.keys))}
+	for _, k := range ks.keys {
+		data.Keys = append(data.Keys, k)
+	}
+	plaintext, err := json.Marshal(data)
+	ks.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(keysetAssociatedTag)), nil
+}
+
+// ParseEncryptedKeyset 使用主密钥解密并加载 MarshalEncrypted 生成的密钥集
+func ParseEncryptedKeyset(data, masterKey []byte) (*Keyset, error) {
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("encrypted keyset is too short")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(keysetAssociatedTag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keyset: %w", err)
+	}
+
+	var parsed keysetJSON
+	if err := json.Unmarshal(plaintext, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode keyset: %w", err)
+	}
+
+	ks := NewKeyset()
+	primaries := 0
+	for _, k := range parsed.Keys {
+		size, err := k.Type.keySize()
+		if err != nil {
+			return nil, err
+		}
+		if len(k.Material) != size {
+			return nil, fmt.Errorf("key %d has invalid material length", k.ID)
+		}
+		if _, exists := ks.keys[k.ID]; exists || k.ID == 0 {
+			return nil, fmt.Errorf("duplicate or invalid key ID %d", k.ID)
+		}
+		switch k.Status {
+		case KeyStatusPrimary:
+			primaries++
+		case KeyStatusEnabled, KeyStatusDisabled:
+		default:
+			return nil, fmt.Errorf("key %d has unknown status %q", k.ID, k.Status)
+		}
+		if k.aead, err = newGCM(k.Material); err != nil {
+			return nil, err
+		}
+		ks.keys[k.ID] = k
+	}
+	if primaries > 1 {
+		return nil, errors.New("keyset has more than one primary key")
+	}
+	return ks, nil
+}
@@ -0,0 +1,121 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKeyset_EncryptDecryptAndRotate(t *testing.T) {
+	ks := NewKeyset()
+	first, err := ks.GenerateKey(KeyTypeAES256GCM)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	if ks.PrimaryKeyID() != first {
+		t.Fatal("first key should become primary")
+	}
+
+	aad := []byte("user:42")
+	old, err := ks.Encrypt([]byte("secret"), aad)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	second, _ := ks.GenerateKey(KeyTypeAES128GCM)
+	if err := ks.SetPrimary(second); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := ks.Encrypt([]byte("secret"), aad)
+
+	for _, ct := range [][]byte{old, fresh} {
+		plain, err := ks.Decrypt(ct, aad)
+		if err != nil || string(plain) != "secret" {
+			t.Errorf("Decrypt() = %q, %v", plain, err)
+		}
+	}
+	if _, err := ks.Decrypt(fresh, []byte("user:43")); err == nil {
+		t.Error("expected error for mismatched associated data")
+	}
+
+	// 禁用旧密钥后旧密文无法解密，重新启用后恢复
+	if err := ks.Disable(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Decrypt(old, aad); !errors.Is(err, ErrKeysetKeyDisabled) {
+		t.Errorf("expected ErrKeysetKeyDisabled, got %v", err)
+	}
+	if err := ks.Enable(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Decrypt(old, aad); err != nil {
+		t.Errorf("Decrypt() after Enable error = %v", err)
+	}
+
+	if err := ks.Disable(second); err == nil {
+		t.Error("primary key should not be disabled")
+	}
+
+	statuses := make(map[uint32]KeyStatus)
+	for _, info := range ks.Keys() {
+		statuses[info.ID] = info.Status
+	}
+	if len(statuses) != 2 || statuses[first] != KeyStatusEnabled || statuses[second] != KeyStatusPrimary {
+		t.Errorf("Keys() statuses = %v", statuses)
+	}
+}
+
+func TestKeyset_MarshalEncrypted(t *testing.T) {
+	master := testMasterKey()
+	ks := NewKeyset()
+	id1, _ := ks.GenerateKey(KeyTypeAES256GCM)
+	id2, _ := ks.GenerateKey(KeyTypeAES256GCM)
+	_ = ks.SetPrimary(id2)
+	_ = ks.Disable(id1)
+
+	ct, _ := ks.Encrypt([]byte("payload"), nil)
+
+	data, err := ks.MarshalEncrypted(master)
+	if err != nil {
+		t.Fatalf("MarshalEncrypted() error = %v", err)
+	}
+
+	loaded, err := ParseEncryptedKeyset(data, master)
+	if err != nil {
+		t.Fatalf("ParseEncryptedKeyset() error = %v", err)
+	}
+	if loaded.PrimaryKeyID() != id2 {
+		t.Errorf("PrimaryKeyID() = %d, want %d", loaded.PrimaryKeyID(), id2)
+	}
+	if plain, err := loaded.Decrypt(ct, nil); err != nil || string(plain) != "payload" {
+		t.Errorf("Decrypt() with loaded keyset = %q, %v", plain, err)
+	}
+	if infos := loaded.Keys(); len(infos) != 2 {
+		t.Errorf("loaded %d keys, want 2", len(infos))
+	}
+
+	wrong := make([]byte, 32)
+	if _, err := ParseEncryptedKeyset(data, wrong); err == nil {
+		t.Error("expected error for wrong master key")
+	}
+}
+
+func TestKeyset_Errors(t *testing.T) {
+	ks := NewKeyset()
+	if _, err := ks.Encrypt([]byte("x"), nil); !errors.Is(err, ErrKeysetNoPrimary) {
+		t.Errorf("expected ErrKeysetNoPrimary, got %v", err)
+	}
+	if _, err := ks.AddKey(KeyTypeAES256GCM, make([]byte, 16)); err == nil {
+		t.Error("expected error for wrong key length")
+	}
+	if _, err := ks.GenerateKey("CHACHA"); err == nil {
+		t.Error("expected error for unsupported key type")
+	}
+	if _, err := ks.Decrypt([]byte{0x02, 0, 0, 0, 1}, nil); !errors.Is(err, ErrInvalidKeysetCiphertext) {
+		t.Errorf("expected ErrInvalidKeysetCiphertext, got %v", err)
+	}
+	if _, err := ks.Decrypt([]byte{0x01, 0, 0, 0, 1, 2, 3}, nil); !errors.Is(err, ErrKeysetKeyNotFound) {
+		t.Errorf("expected ErrKeysetKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package macsign 提供与 JWT 无关的 HMAC 消息认证（默认 SHA-256，可选 SHA-512），统一签名串的规范化规则，
+// 使 URL 签名、Webhook 签名和载荷令牌（如分页游标）使用同一套签名与校验逻辑。
+//
+// 规范化规则：
+//   - 查询参数：去掉签名相关参数后按 url.Values.Encode 编码（按键排序，同名参数保持原顺序）
+//   - 带时间戳的消息：十进制 Unix 秒 + 原始消息，中间不加分隔符
+//   - 载荷令牌：{payload}.{signature}，签名只覆盖 payload
+package macsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 预定义错误
+var (
+	ErrEmptyKey         = errors.New("macsign: key cannot be empty")
+	ErrInvalidSignature = errors.New("macsign: invalid signature")
+	ErrInvalidToken     = errors.New("macsign: invalid token format")
+)
+
+// Encoding 签名的文本编码方式
+type Encoding int
+
+const (
+	// EncodingBase64 标准 Base64（带填充），URL 签名使用
+	EncodingBase64 Encoding = iota
+	// EncodingBase64URL URL 安全 Base64（无填充），令牌与游标使用
+	EncodingBase64URL
+	// EncodingHex 十六进制，常见于 Webhook 签名头
+	EncodingHex
+)
+
+// Hash HMAC 使用的摘要算法
+type Hash int
+
+const (
+	// HashSHA256 HMAC-SHA256，New 的默认算法
+	HashSHA256 Hash = iota
+	// HashSHA512 HMAC-SHA512
+	HashSHA512
+)
+
+// Signer HMAC 签名器，创建后可并发使用
+type Signer struct {
+	key      []byte
+	encoding Encoding
+	hash     Hash
+}
+
+// New 创建 HMAC-SHA256 签名器，key 为空时返回 ErrEmptyKey
+func New(key []byte, encoding Encoding) (*Signer, error) {
+	return NewWithHash(key, encoding, HashSHA256)
+}
+
+// NewWithHash 创建使用指定摘要算法的签名器
+func NewWithHash(key []byte, encoding Encoding, hash Hash) (*Signer, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	if hash != HashSHA256 && hash != HashSHA512 {
+		return nil, errors.New("macsign: unsupported hash")
+	}
+	return &Signer{key: append([]byte(nil), key...), encoding: encoding, hash: hash}, nil
+}
+
+// Sign 对消息签名并按配置的编码输出
+func (s *Signer) Sign(message []byte) string {
+	newHash := sha256.New
+	if s.hash == HashSHA512 {
+		newHash = sha512.New
+	}
+	mac := hmac.New(newHash, s.key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	switch s.encoding {
+	case EncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(sum)
+	case EncodingHex:
+		return hex.EncodeToString(sum)
+	default:
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+}
+
+// Verify 使用恒定时间比较校验签名
+func (s *Signer) Verify(message []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(s.Sign(message)))
+}
+
+// TimestampedMessage 按规范拼接时间戳与消息：十进制 Unix 秒 + 消息
+func TimestampedMessage(timestamp int64, message []byte) []byte {
+	buf := make([]byte, 0, 20+len(message))
+	buf = strconv.AppendInt(buf, timestamp, 10)
+	return append(buf, message...)
+}
+
+// SignTimestamped 对带时间戳的消息签名
+func (s *Signer) SignTimestamped(timestamp int64, message []byte) string {
+	return s.Sign(TimestampedMessage(timestamp, message))
+}
+
+// VerifyTimestamped 校验带时间戳消息的签名，时间窗口由调用方检查
+func (s *Signer) VerifyTimestamped(timestamp int64, message []byte, signature string) bool {
+	return s.Verify(TimestampedMessage(timestamp, message), signature)
+}
+
+// CanonicalQuery 返回查询参数的规范化字符串，exclude 中的参数（通常是签名本身）不参与签名
+func CanonicalQuery(values url.Values, exclude ...string) string {
+	if len(exclude) == 0 {
+		return values.Encode()
+	}
+	filtered := make(url.Values, len(values))
+	for k, v := range values {
+		filtered[k] = v
+	}
+	for _, k := range exclude {
+		delete(filtered, k)
+	}
+	return filtered.Encode()
+}
+
+// Seal 生成载荷令牌：{payload}.{signature}
+// payload 本身不应包含 '.'，建议使用 URL 安全 Base64 编码后的内容
+func (s *Signer) Seal(payload string) string {
+	var sb strings.Builder
+	sig := s.Sign([]byte(payload))
+	sb.Grow(len(payload) + 1 + len(sig))
+	sb.WriteString(payload)
+	sb.WriteByte('.')
+	sb.WriteString(sig)
+	return sb.String()
+}
+
+// Open 校验载荷令牌并返回 payload
+func (s *Signer) Open(token string) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || strings.Contains(sig, ".") {
+		return "", ErrInvalidToken
+	}
+	if !s.Verify([]byte(payload), sig) {
+		return "", ErrInvalidSignature
+	}
+	return payload, nil
+}
@@ -0,0 +1,116 @@
+package macsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestSignEncodings(t *testing.T) {
+	key := []byte("secret")
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("message"))
+	sum := mac.Sum(nil)
+
+	tests := []struct {
+		encoding Encoding
+		want     string
+	}{
+		{EncodingBase64, base64.StdEncoding.EncodeToString(sum)},
+		{EncodingBase64URL, base64.RawURLEncoding.EncodeToString(sum)},
+		{EncodingHex, hex.EncodeToString(sum)},
+	}
+	for _, tt := range tests {
+		s, err := New(key, tt.encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Sign([]byte("message")); got != tt.want {
+			t.Errorf("Sign() with encoding %d = %s, want %s", tt.encoding, got, tt.want)
+		}
+		if !s.Verify([]byte("message"), tt.want) {
+			t.Errorf("Verify() with encoding %d failed", tt.encoding)
+		}
+		if s.Verify([]byte("tampered"), tt.want) {
+			t.Errorf("Verify() with encoding %d accepted tampered message", tt.encoding)
+		}
+	}
+}
+
+func TestNewEmptyKey(t *testing.T) {
+	if _, err := New(nil, EncodingBase64); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("New() error = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestNewWithHash(t *testing.T) {
+	key := []byte("secret")
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte("message"))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	s, err := NewWithHash(key, EncodingHex, HashSHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Sign([]byte("message")); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	sha256Signer, _ := New(key, EncodingHex)
+	if sha256Signer.Verify([]byte("message"), want) {
+		t.Error("SHA-256 signer accepted a SHA-512 signature")
+	}
+	if _, err := NewWithHash(key, EncodingHex, Hash(99)); err == nil {
+		t.Error("expected error for unsupported hash")
+	}
+}
+
+func TestTimestampedAndCanonicalQuery(t *testing.T) {
+	s, _ := New([]byte("secret"), EncodingBase64)
+
+	values := url.Values{"b": {"2"}, "a": {"1"}, "_sign": {"x"}}
+	query := CanonicalQuery(values, "_sign")
+	if query != "a=1&b=2" {
+		t.Errorf("CanonicalQuery() = %s", query)
+	}
+	if _, ok := values["_sign"]; !ok {
+		t.Error("CanonicalQuery() must not modify the input")
+	}
+
+	if got := string(TimestampedMessage(1700000000, []byte(query))); got != "1700000000a=1&b=2" {
+		t.Errorf("TimestampedMessage() = %s", got)
+	}
+
+	sig := s.SignTimestamped(1700000000, []byte(query))
+	if !s.VerifyTimestamped(1700000000, []byte(query), sig) {
+		t.Error("VerifyTimestamped() failed")
+	}
+	if s.VerifyTimestamped(1700000001, []byte(query), sig) {
+		t.Error("VerifyTimestamped() accepted a different timestamp")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	s, _ := New([]byte("secret"), EncodingBase64URL)
+	token := s.Seal("eyJpZCI6MX0")
+
+	payload, err := s.Open(token)
+	if err != nil || payload != "eyJpZCI6MX0" {
+		t.Fatalf("Open() = %q, %v", payload, err)
+	}
+
+	other, _ := New([]byte("other"), EncodingBase64URL)
+	if _, err := other.Open(token); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Open() with wrong key error = %v", err)
+	}
+	for _, bad := range []string{"no-dot", "a.b.c"} {
+		if _, err := s.Open(bad); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Open(%q) error = %v, want ErrInvalidToken", bad, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultRandomNonceLimit 随机 96 位 Nonce 下单个密钥建议的最大加密次数（NIST SP 800-38D 的 2^32 上限）
+const DefaultRandomNonceLimit uint64 = 1 << 32
+
+// counterNoncePrefixSize 计数器 Nonce 中固定前缀的长度，剩余 8 字节为计数器
+const counterNoncePrefixSize = 4
+
+var (
+	// ErrNonceLimitExceeded 当前密钥的加密次数已达到上限，必须轮换密钥
+	ErrNonceLimitExceeded = errors.New("nonce limit exceeded for this key, rotate the key")
+	// ErrNonceCounterExhausted 计数器已用尽，继续使用会导致 Nonce 重复
+	ErrNonceCounterExhausted = errors.New("nonce counter exhausted")
+)
+
+// NonceSource 为 AES-GCM 生成 Nonce
+// 实现必须保证在同一密钥下永不返回重复的 Nonce
+type NonceSource interface {
+	NextNonce(size int) ([]byte, error)
+}
+
+// RandomNonceSource 使用密码学安全的随机数生成 Nonce（默认策略）
+type RandomNonceSource struct{}
+
+// NextNonce 实现 NonceSource 接口
+func (RandomNonceSource) NextNonce(size int) ([]byte, error) {
+	nonce := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// CounterStore 持久化计数器状态，保证进程重启后不会复用已使用过的计数器值
+type CounterStore interface {
+	// Load 返回已保存的计数器值，从未保存过时返回 0
+	Load() (uint64, error)
+	// Save 保存计数器值
+	Save(value uint64) error
+}
+
+// MemoryCounterStore 内存计数器存储，仅适用于测试或密钥与进程生命周期一致的场景
+type MemoryCounterStore struct {
+	mu    sync.Mutex
+	value uint64
+}
+
+// Load 实现 CounterStore 接口
+func (s *MemoryCounterStore) Load() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value, nil
+}
+
+// Save 实现 CounterStore 接口
+func (s *MemoryCounterStore) Save(value uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value = value
+	return nil
+}
+
+// CounterNonceSource 基于计数器的 Nonce 生成器，格式为 前缀(4 字节) || 计数器(8 字节，大端序)
+// 计数器按块预留并持久化：每次预留 reserve 个值前先保存上限，
+// 进程崩溃后从已保存的上限继续，最多浪费一个块，但绝不会复用 Nonce。
+// 多个进程共享同一密钥时，必须为每个进程分配不同的前缀。
+type CounterNonceSource struct {
+	mu       sync.Mutex
+	prefix   [counterNoncePrefixSize]byte
+	store    CounterStore
+	next     uint64
+	limit    uint64
+	reserve  uint64
+	initDone bool
+}
+
+// NewCounterNonceSource 创建计数器 Nonce 生成器
+// prefix 为 nil 时随机生成；store 为 nil 时使用内存存储；reserve <= 0 时默认每次预留 1024 个值
+func NewCounterNonceSource(prefix []byte, store CounterStore, reserve int) (*CounterNonceSource, error) {
+	s := &CounterNonceSource{store: store, reserve: 1024}
+	if reserve > 0 {
+		s.reserve = uint64(reserve)
+	}
+	if s.store == nil {
+		s.store = &MemoryCounterStore{}
+	}
+
+	switch {
+	case prefix == nil:
+		if _, err := io.ReadFull(rand.Reader, s.prefix[:]); err != nil {
+			return nil, err
+		}
+	case len(prefix) == counterNoncePrefixSize:
+		copy(s.prefix[:], prefix)
+	default:
+		return nil, fmt.Errorf("nonce prefix must be %d bytes", counterNoncePrefixSize)
+	}
+	return s, nil
+}
+
+// NextNonce 实现 NonceSource 接口，仅支持 12 字节的标准 GCM Nonce
+func (s *CounterNonceSource) NextNonce(size int) ([]byte, error) {
+	if size != counterNoncePrefixSize+8 {
+		return nil, fmt.Errorf("counter nonce requires a %d-byte nonce, got %d", counterNoncePrefixSize+8, size)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.initDone {
+		start, err := s.store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load nonce counter: %w", err)
+		}
+		s.next, s.limit, s.initDone = start, start, true
+	}
+
+	if s.next >= s.limit {
+		if s.limit > ^uint64(0)-s.reserve {
+			return nil, ErrNonceCounterExhausted
+		}
+		// 先持久化新的上限，再使用块内的值
+		if err := s.store.Save(s.limit + s.reserve); err != nil {
+			return nil, fmt.Errorf("failed to persist nonce counter: %w", err)
+		}
+		s.limit += s.reserve
+	}
+
+	nonce := make([]byte, size)
+	copy(nonce, s.prefix[:])
+	binary.BigEndian.PutUint64(nonce[counterNoncePrefixSize:], s.next)
+	s.next++
+	return nonce, nil
+}
+
+// Counter 返回下一个将要使用的计数器值
+func (s *CounterNonceSource) Counter() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// NonceLimit 单个密钥的加密次数限制
+type NonceLimit struct {
+	// MaxMessages 最大加密次数，达到后 Encrypt 返回 ErrNonceLimitExceeded；0 表示不限制
+	MaxMessages uint64
+	// WarnThreshold 达到该次数时调用 OnApproach，0 表示 MaxMessages 的 90%
+	WarnThreshold uint64
+	// OnApproach 接近上限时调用一次，通常用于记录告警或触发密钥轮换
+	// 在加密调用中同步执行，不应阻塞
+	OnApproach func(count uint64)
+}
+
+// warnAt 返回告警阈值
+func (l *NonceLimit) warnAt() uint64 {
+	if l.WarnThreshold > 0 {
+		return l.WarnThreshold
+	}
+	return l.MaxMessages / 10 * 9
+}
+
+// SetNonceSource 设置 Nonce 生成策略，传入 nil 恢复随机 Nonce
+func (e *AESEncryptor) SetNonceSource(source NonceSource) {
+	e.nonceMutex.Lock()
+	defer e.nonceMutex.Unlock()
+	e.nonceSource = source
+}
+
+// SetNonceLimit 设置当前密钥的加密次数限制，传入 nil 取消限制
+func (e *AESEncryptor) SetNonceLimit(limit *NonceLimit) {
+	e.nonceMutex.Lock()
+	defer e.nonceMutex.Unlock()
+	e.nonceLimit = limit
+	e.limitWarned.Store(false)
+}
+
+// MessageCount 返回当前加密器已执行的加密次数
+func (e *AESEncryptor) MessageCount() uint64 {
+	return e.messages.Load()
+}
+
+// nextNonce 检查加密次数限制并生成 Nonce
+func (e *AESEncryptor) nextNonce(size int) ([]byte, error) {
+	e.nonceMutex.RLock()
+	source, limit := e.nonceSource, e.nonceLimit
+	e.nonceMutex.RUnlock()
+
+	count := e.messages.Add(1)
+	if limit != nil {
+		if limit.MaxMessages > 0 && count > limit.MaxMessages {
+			e.messages.Add(^uint64(0))
+			return nil, ErrNonceLimitExceeded
+		}
+		if warnAt := limit.warnAt(); warnAt > 0 && count >= warnAt && limit.OnApproach != nil {
+			if e.limitWarned.CompareAndSwap(false, true) {
+				limit.OnApproach(count)
+			}
+		}
+	}
+
+	if source == nil {
+		source = RandomNonceSource{}
+	}
+	return source.NextNonce(size)
+}
@@ -0,0 +1,58 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileCounterStore 将计数器以十进制文本保存到文件，写入时先写临时文件再重命名
+type FileCounterStore string
+
+// Load 实现 CounterStore 接口
+func (f FileCounterStore) Load() (uint64, error) {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid counter file %s: %w", string(f), err)
+	}
+	return value, nil
+}
+
+// Save 实现 CounterStore 接口
+func (f FileCounterStore) Save(value uint64) error {
+	return writeFileAtomic(string(f), strconv.FormatUint(value, 10))
+}
+
+// writeFileAtomic 先写同目录下的临时文件并同步到磁盘，再重命名覆盖目标文件
+func writeFileAtomic(path, content string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
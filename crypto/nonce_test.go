@@ -0,0 +1,120 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+)
+
+func TestCounterNonceSource(t *testing.T) {
+	store := &MemoryCounterStore{}
+	src, err := NewCounterNonceSource([]byte{1, 2, 3, 4}, store, 10)
+	if err != nil {
+		t.Fatalf("NewCounterNonceSource() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 25; i++ {
+		nonce, err := src.NextNonce(12)
+		if err != nil {
+			t.Fatalf("NextNonce() error = %v", err)
+		}
+		if !bytes.Equal(nonce[:4], []byte{1, 2, 3, 4}) {
+			t.Fatalf("unexpected prefix %x", nonce[:4])
+		}
+		if got := binary.BigEndian.Uint64(nonce[4:]); got != uint64(i) {
+			t.Fatalf("counter = %d, want %d", got, i)
+		}
+		seen[string(nonce)] = true
+	}
+	if len(seen) != 25 {
+		t.Errorf("expected 25 unique nonces, got %d", len(seen))
+	}
+
+	// 预留按块持久化：已使用 25 个，保存的上限应为 30
+	if saved, _ := store.Load(); saved != 30 {
+		t.Errorf("persisted counter = %d, want 30", saved)
+	}
+
+	if _, err := src.NextNonce(16); err == nil {
+		t.Error("expected error for non-standard nonce size")
+	}
+	if _, err := NewCounterNonceSource([]byte{1}, nil, 0); err == nil {
+		t.Error("expected error for invalid prefix length")
+	}
+}
+
+func TestCounterNonceSource_ResumeFromStore(t *testing.T) {
+	store := FileCounterStore(filepath.Join(t.TempDir(), "nonce.counter"))
+
+	first, _ := NewCounterNonceSource([]byte{0, 0, 0, 1}, store, 4)
+	for i := 0; i < 3; i++ {
+		if _, err := first.NextNonce(12); err != nil {
+			t.Fatalf("NextNonce() error = %v", err)
+		}
+	}
+
+	// 模拟重启：新实例从持久化的上限继续，不会复用 0-3
+	second, _ := NewCounterNonceSource([]byte{0, 0, 0, 1}, store, 4)
+	nonce, err := second.NextNonce(12)
+	if err != nil {
+		t.Fatalf("NextNonce() error = %v", err)
+	}
+	if got := binary.BigEndian.Uint64(nonce[4:]); got != 4 {
+		t.Errorf("resumed counter = %d, want 4", got)
+	}
+}
+
+func TestAESEncryptor_CounterNonce(t *testing.T) {
+	encryptor, err := NewAESEncryptor(testMasterKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _ := NewCounterNonceSource(nil, nil, 0)
+	encryptor.SetNonceSource(src)
+
+	ciphertext, err := encryptor.Encrypt([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	plaintext, err := encryptor.Decrypt(ciphertext)
+	if err != nil || string(plaintext) != "hello" {
+		t.Errorf("Decrypt() = %q, %v", plaintext, err)
+	}
+	if src.Counter() != 1 {
+		t.Errorf("Counter() = %d, want 1", src.Counter())
+	}
+}
+
+func TestAESEncryptor_NonceLimit(t *testing.T) {
+	encryptor, err := NewAESEncryptor(testMasterKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warned []uint64
+	encryptor.SetNonceLimit(&NonceLimit{
+		MaxMessages:   5,
+		WarnThreshold: 3,
+		OnApproach:    func(count uint64) { warned = append(warned, count) },
+	})
+
+	for i := 0; i < 5; i++ {
+		if _, err := encryptor.Encrypt([]byte("data")); err != nil {
+			t.Fatalf("Encrypt() #%d error = %v", i, err)
+		}
+	}
+	if len(warned) != 1 || warned[0] != 3 {
+		t.Errorf("OnApproach calls = %v, want [3]", warned)
+	}
+
+	if _, err := encryptor.Encrypt([]byte("data")); err != ErrNonceLimitExceeded {
+		t.Errorf("expected ErrNonceLimitExceeded, got %v", err)
+	}
+	if encryptor.MessageCount() != 5 {
+		t.Errorf("MessageCount() = %d, want 5", encryptor.MessageCount())
+	}
+}
@@ -1,3 +1,5 @@
+//go:build !cryptolite && !tinygo
+
 package crypto
 
 import (
@@ -8,6 +10,9 @@ import (
 	"unicode"
 )
 
+// nonAlphanumericRegex 预编译正则表达式
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
 // PasswordPolicy 密码策略结构体
 type PasswordPolicy struct {
 	MinLength      int
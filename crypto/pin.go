@@ -0,0 +1,246 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PIN 相关错误
+var (
+	ErrInvalidPIN = errors.New("PIN must consist of digits only and have a supported length")
+	ErrPINLocked  = errors.New("too many failed PIN attempts, try again later")
+)
+
+// PINArgon2Params 返回适用于短 PIN 的 Argon2 参数
+// 4–8 位数字的 PIN 只有 10^4–10^8 种可能，必须让每次计算足够昂贵，才能让泄露哈希后的离线穷举不可行
+func PINArgon2Params() *Argon2Params {
+	return &Argon2Params{
+		Memory:      256 * 1024, // 256MB
+		Iterations:  4,
+		Parallelism: 4,
+		SaltLength:  16,
+		KeyLength:   32,
+		Type:        Argon2id,
+	}
+}
+
+// PINAttemptGuard 将 PIN 验证与尝试次数绑定，用于限制在线猜测
+// 实现通常基于 Redis 或数据库计数器，以便多实例共享
+type PINAttemptGuard interface {
+	// Allow 在验证前调用，必须原子地检查并预占一次尝试（先按失败计数），
+	// 否则并发请求会在哈希计算期间同时通过检查，绕过次数限制；
+	// 返回错误（通常包装 ErrPINLocked）时拒绝本次验证，且不需要调用 Record
+	Allow(ctx context.Context, userID string) error
+	// Record 在 Allow 通过后调用，确认本次尝试的结果并释放预占
+	Record(ctx context.Context, userID string, success bool) error
+}
+
+// PINHasherOptions PIN 哈希器配置
+type PINHasherOptions struct {
+	// Params Argon2 参数，默认 PINArgon2Params()
+	Params *Argon2Params
+	// Pepper 服务端密钥，不与哈希存放在一起；只泄露数据库时无法离线穷举
+	Pepper []byte
+	// Guard 尝试次数限制，为 nil 时不限制（不推荐）
+	Guard PINAttemptGuard
+	// MinLength PIN 最小位数，默认 4
+	MinLength int
+	// MaxLength PIN 最大位数，默认 8
+	MaxLength int
+}
+
+// PINHasher 针对 4–8 位数字 PIN 的哈希器
+// 哈希输入为 HMAC-SHA256(pepper, userID || 0x00 || PIN)，再使用高强度 Argon2 参数和随机 salt 计算，
+// 哈希因此绑定到具体用户，不能在用户之间互换
+type PINHasher struct {
+	params    *Argon2Params
+	pepper    []byte
+	guard     PINAttemptGuard
+	minLength int
+	maxLength int
+}
+
+// NewPINHasher 创建 PIN 哈希器
+func NewPINHasher(opts PINHasherOptions) (*PINHasher, error) {
+	if opts.Params == nil {
+		opts.Params = PINArgon2Params()
+	}
+	if opts.MinLength <= 0 {
+		opts.MinLength = 4
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = 8
+	}
+	if opts.MinLength > opts.MaxLength {
+		return nil, fmt.Errorf("PIN min length %d exceeds max length %d", opts.MinLength, opts.MaxLength)
+	}
+	return &PINHasher{
+		params:    opts.Params,
+		pepper:    append([]byte(nil), opts.Pepper...),
+		guard:     opts.Guard,
+		minLength: opts.MinLength,
+		maxLength: opts.MaxLength,
+	}, nil
+}
+
+// ValidatePIN 检查 PIN 是否只包含数字且长度在允许范围内
+func (h *PINHasher) ValidatePIN(pin string) error {
+	if len(pin) < h.minLength || len(pin) > h.maxLength {
+		return ErrInvalidPIN
+	}
+	for i := 0; i < len(pin); i++ {
+		if pin[i] < '0' || pin[i] > '9' {
+			return ErrInvalidPIN
+		}
+	}
+	return nil
+}
+
+// Hash 计算用户 PIN 的哈希，返回标准 Argon2 编码格式
+func (h *PINHasher) Hash(userID, pin string) (string, error) {
+	if err := h.ValidatePIN(pin); err != nil {
+		return "", err
+	}
+	return HashWithArgon2(h.input(userID, pin), h.params)
+}
+
+// Verify 验证用户 PIN
+// 配置了 Guard 时先检查是否允许尝试，被拒绝时直接返回其错误而不计算哈希；
+// 格式不合法的 PIN 与错误的 PIN 一样返回 false 并计为一次失败，存储的哈希无法解析时同样计为失败
+func (h *PINHasher) Verify(ctx context.Context, userID, pin, hash string) (bool, error) {
+	if h.guard != nil {
+		if err := h.guard.Allow(ctx, userID); err != nil {
+			return false, err
+		}
+	}
+
+	ok := false
+	var verifyErr error
+	if h.ValidatePIN(pin) == nil {
+		ok, verifyErr = VerifyArgon2Hash([]byte(hash), h.input(userID, pin))
+		ok = ok && verifyErr == nil
+	}
+
+	if h.guard != nil {
+		if err := h.guard.Record(ctx, userID, ok); err != nil {
+			return false, fmt.Errorf("failed to record PIN attempt: %w", err)
+		}
+	}
+	if verifyErr != nil {
+		return false, verifyErr
+	}
+	return ok, nil
+}
+
+// input 计算绑定用户与 pepper 的 Argon2 输入
+func (h *PINHasher) input(userID, pin string) []byte {
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(userID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(pin))
+	return mac.Sum(nil)
+}
+
+// MemoryPINAttemptGuard 进程内的 PIN 尝试次数限制
+// 连续失败 MaxFailures 次后锁定 Lockout，此后每次锁定结束后再失败，锁定时间加倍（上限 24 小时）；验证成功后清零。
+// 进行中的验证计入失败次数，失败次数与进行中的验证之和达到 MaxFailures 时拒绝新的尝试
+type MemoryPINAttemptGuard struct {
+	MaxFailures int
+	Lockout     time.Duration
+
+	mu    sync.Mutex
+	state map[string]*pinAttemptState
+	now   func() time.Time
+}
+
+// pinAttemptState 单个用户的尝试状态
+type pinAttemptState struct {
+	failures    int
+	pending     int // Allow 已通过、尚未 Record 的尝试
+	lockouts    int
+	lockedUntil time.Time
+}
+
+// maxPINLockout 锁定时间上限
+const maxPINLockout = 24 * time.Hour
+
+// NewMemoryPINAttemptGuard 创建进程内尝试次数限制，maxFailures 默认 5，lockout 默认 15 分钟
+func NewMemoryPINAttemptGuard(maxFailures int, lockout time.Duration) *MemoryPINAttemptGuard {
+	if maxFailures <= 0 {
+		maxFailures = 5
+	}
+	if lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+	return &MemoryPINAttemptGuard{
+		MaxFailures: maxFailures,
+		Lockout:     lockout,
+		state:       make(map[string]*pinAttemptState),
+		now:         time.Now,
+	}
+}
+
+// Allow 实现 PINAttemptGuard 接口
+func (g *MemoryPINAttemptGuard) Allow(_ context.Context, userID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.state[userID]
+	if !ok {
+		s = &pinAttemptState{}
+		g.state[userID] = s
+	}
+	if wait := s.lockedUntil.Sub(g.now()); wait > 0 {
+		return fmt.Errorf("%w (retry after %s)", ErrPINLocked, wait.Round(time.Second))
+	}
+	if s.failures+s.pending >= g.MaxFailures {
+		return fmt.Errorf("%w (too many attempts in progress)", ErrPINLocked)
+	}
+	s.pending++
+	return nil
+}
+
+// Record 实现 PINAttemptGuard 接口
+func (g *MemoryPINAttemptGuard) Record(_ context.Context, userID string, success bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.state[userID]
+	if !ok {
+		s = &pinAttemptState{}
+		g.state[userID] = s
+	}
+	if s.pending > 0 {
+		s.pending--
+	}
+
+	if success {
+		if s.pending == 0 {
+			delete(g.state, userID)
+		} else {
+			*s = pinAttemptState{pending: s.pending}
+		}
+		return nil
+	}
+
+	s.failures++
+	if s.failures >= g.MaxFailures {
+		lockout := g.Lockout
+		for i := 0; i < s.lockouts && lockout < maxPINLockout; i++ {
+			lockout *= 2
+		}
+		lockout = min(lockout, maxPINLockout)
+		s.lockedUntil = g.now().Add(lockout)
+		s.lockouts++
+		// 锁定结束后只允许再尝试一次，失败立即再次锁定
+		s.failures = g.MaxFailures - 1
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testPINParams 测试使用的低成本参数
+func testPINParams() *Argon2Params {
+	return &Argon2Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32, Type: Argon2id}
+}
+
+func TestPINHasher_HashAndVerify(t *testing.T) {
+	ctx := context.Background()
+	h, err := NewPINHasher(PINHasherOptions{Params: testPINParams(), Pepper: []byte("server-side-pepper")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := h.Hash("user-1", "4821")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if ok, err := h.Verify(ctx, "user-1", "4821", hash); err != nil || !ok {
+		t.Errorf("Verify(correct) = %v, %v", ok, err)
+	}
+	if ok, _ := h.Verify(ctx, "user-1", "4822", hash); ok {
+		t.Error("wrong PIN verified")
+	}
+	// 哈希绑定用户，不能挪给其他用户使用
+	if ok, _ := h.Verify(ctx, "user-2", "4821", hash); ok {
+		t.Error("hash verified for a different user")
+	}
+
+	// pepper 不同时无法验证
+	other, _ := NewPINHasher(PINHasherOptions{Params: testPINParams(), Pepper: []byte("another-pepper")})
+	if ok, _ := other.Verify(ctx, "user-1", "4821", hash); ok {
+		t.Error("hash verified without the original pepper")
+	}
+	// 直接对 PIN 做 Argon2 验证也无法命中
+	if ok, _ := VerifyArgon2Hash([]byte(hash), []byte("4821")); ok {
+		t.Error("PIN hash should not verify as a plain Argon2 hash")
+	}
+}
+
+func TestPINHasher_ValidatePIN(t *testing.T) {
+	h, _ := NewPINHasher(PINHasherOptions{Params: testPINParams()})
+	for _, pin := range []string{"123", "123456789", "12a4", "", "１２３４"} {
+		if _, err := h.Hash("u", pin); !errors.Is(err, ErrInvalidPIN) {
+			t.Errorf("Hash(%q) error = %v, want ErrInvalidPIN", pin, err)
+		}
+	}
+	for _, pin := range []string{"0000", "12345678"} {
+		if err := h.ValidatePIN(pin); err != nil {
+			t.Errorf("ValidatePIN(%q) = %v", pin, err)
+		}
+	}
+	if _, err := NewPINHasher(PINHasherOptions{MinLength: 8, MaxLength: 6}); err == nil {
+		t.Error("expected error for min > max")
+	}
+}
+
+func TestPINHasher_Guard(t *testing.T) {
+	ctx := context.Background()
+	guard := NewMemoryPINAttemptGuard(3, time.Minute)
+	now := time.Now()
+	guard.now = func() time.Time { return now }
+
+	h, _ := NewPINHasher(PINHasherOptions{Params: testPINParams(), Guard: guard})
+	hash, _ := h.Hash("user-1", "2580")
+
+	for i := 0; i < 3; i++ {
+		if ok, err := h.Verify(ctx, "user-1", "0000", hash); ok || err != nil {
+			t.Fatalf("attempt %d: Verify() = %v, %v", i, ok, err)
+		}
+	}
+	// 锁定期间即使 PIN 正确也拒绝
+	if _, err := h.Verify(ctx, "user-1", "2580", hash); !errors.Is(err, ErrPINLocked) {
+		t.Fatalf("expected ErrPINLocked, got %v", err)
+	}
+	// 其他用户不受影响
+	if err := guard.Allow(ctx, "user-2"); err != nil {
+		t.Errorf("unexpected lock for other user: %v", err)
+	}
+
+	// 锁定结束后再次失败，锁定时间加倍
+	now = now.Add(time.Minute + time.Second)
+	if ok, err := h.Verify(ctx, "user-1", "1111", hash); ok || err != nil {
+		t.Fatalf("Verify() after lockout = %v, %v", ok, err)
+	}
+	now = now.Add(90 * time.Second)
+	if err := guard.Allow(ctx, "user-1"); !errors.Is(err, ErrPINLocked) {
+		t.Errorf("expected doubled lockout, got %v", err)
+	}
+
+	// 成功后清零
+	now = now.Add(time.Hour)
+	if ok, err := h.Verify(ctx, "user-1", "2580", hash); !ok || err != nil {
+		t.Fatalf("Verify(correct) = %v, %v", ok, err)
+	}
+	if _, ok := guard.state["user-1"]; ok {
+		t.Error("state should be cleared after success")
+	}
+}
+
+func TestMemoryPINAttemptGuard_ReservesAttempts(t *testing.T) {
+	ctx := context.Background()
+	guard := NewMemoryPINAttemptGuard(3, time.Minute)
+
+	// 并发验证在哈希计算期间占用名额，超出次数限制的请求直接拒绝
+	for i := 0; i < 3; i++ {
+		if err := guard.Allow(ctx, "user-1"); err != nil {
+			t.Fatalf("Allow() %d error = %v", i, err)
+		}
+	}
+	if err := guard.Allow(ctx, "user-1"); !errors.Is(err, ErrPINLocked) {
+		t.Fatalf("expected ErrPINLocked while attempts are in progress, got %v", err)
+	}
+
+	// 成功释放名额并清零
+	_ = guard.Record(ctx, "user-1", true)
+	if err := guard.Allow(ctx, "user-1"); err != nil {
+		t.Errorf("Allow() after success error = %v", err)
+	}
+}
+
+func TestPINHasher_MalformedHashCountsAsFailure(t *testing.T) {
+	ctx := context.Background()
+	guard := NewMemoryPINAttemptGuard(2, time.Minute)
+	h, _ := NewPINHasher(PINHasherOptions{Params: testPINParams(), Guard: guard})
+
+	for i := 0; i < 2; i++ {
+		if ok, err := h.Verify(ctx, "user-1", "1234", "not-a-hash"); ok || err == nil {
+			t.Fatalf("Verify(malformed) = %v, %v", ok, err)
+		}
+	}
+	if _, err := h.Verify(ctx, "user-1", "1234", "not-a-hash"); !errors.Is(err, ErrPINLocked) {
+		t.Errorf("expected ErrPINLocked, got %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package crypto
+
+import "crypto/rand"
+
+// GenerateRandomBytes 生成指定长度的随机字节
+func GenerateRandomBytes(length int) ([]byte, error) {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
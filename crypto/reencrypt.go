@@ -0,0 +1,374 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrRoundTripMismatch 重新加密后解密得到的明文与原明文不一致
+var ErrRoundTripMismatch = errors.New("re-encrypted data does not decrypt to the original plaintext")
+
+// Decrypter 旧加密方案的解密接口，AESEncryptor 与 KeyRing 均满足
+type Decrypter interface {
+	Decrypt(ciphertext string) ([]byte, error)
+}
+
+// Encrypter 新加密方案的加密接口，AESEncryptor 与 KeyRing 均满足
+type Encrypter interface {
+	Encrypt(plaintext []byte) (string, error)
+}
+
+// Record 待迁移的一条加密数据
+type Record struct {
+	ID         string // 记录标识，用作检查点，必须在遍历顺序中唯一且稳定
+	Ciphertext string
+}
+
+// RecordSource 按稳定顺序遍历待迁移记录
+// after 为上次检查点的记录 ID（首次运行为空），实现应从该记录之后开始；
+// 对每条记录调用 fn，fn 返回错误时应停止遍历并返回该错误
+type RecordSource interface {
+	Each(ctx context.Context, after string, fn func(Record) error) error
+}
+
+// RecordSourceFunc 函数适配器
+type RecordSourceFunc func(ctx context.Context, after string, fn func(Record) error) error
+
+// Each 实现 RecordSource 接口
+func (f RecordSourceFunc) Each(ctx context.Context, after string, fn func(Record) error) error {
+	return f(ctx, after, fn)
+}
+
+// RecordWriter 保存重新加密后的密文
+type RecordWriter func(ctx context.Context, id, ciphertext string) error
+
+// CheckpointStore 持久化迁移进度（最后一条已处理记录的 ID）
+type CheckpointStore interface {
+	// Load 返回已保存的检查点，从未保存过时返回空字符串
+	Load() (string, error)
+	// Save 保存检查点
+	Save(id string) error
+}
+
+// MemoryCheckpointStore 内存检查点存储，仅适用于测试或单次运行
+type MemoryCheckpointStore struct {
+	mu sync.Mutex
+	id string
+}
+
+// Load 实现 CheckpointStore 接口
+func (s *MemoryCheckpointStore) Load() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id, nil
+}
+
+// Save 实现 CheckpointStore 接口
+func (s *MemoryCheckpointStore) Save(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id = id
+	return nil
+}
+
+// FileCheckpointStore 将检查点保存到文件，写入时先写临时文件再重命名
+type FileCheckpointStore string
+
+// Load 实现 CheckpointStore 接口
+func (f FileCheckpointStore) Load() (string, error) {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save 实现 CheckpointStore 接口
+func (f FileCheckpointStore) Save(id string) error {
+	return writeFileAtomic(string(f), id)
+}
+
+// ReencryptionOptions 重新加密选项
+type ReencryptionOptions struct {
+	// Concurrency 并发处理的记录数，默认 4
+	Concurrency int
+	// Checkpoint 进度存储，为 nil 时不保存进度
+	Checkpoint CheckpointStore
+	// CheckpointEvery 每处理多少条记录保存一次检查点，默认 100
+	CheckpointEvery int
+	// Verify 为 true 时用新方案解密刚写出的密文并比对明文，新方案必须实现 Decrypter
+	Verify bool
+	// ShouldReencrypt 返回 false 的记录被跳过，例如 KeyRing.NeedsReencryption
+	ShouldReencrypt func(Record) bool
+	// OnFailure 单条记录失败时回调，可用于实时告警
+	OnFailure func(RecordFailure)
+}
+
+// RecordFailure 单条记录的失败信息
+type RecordFailure struct {
+	ID  string
+	Err error
+}
+
+// ReencryptionReport 迁移结果
+type ReencryptionReport struct {
+	Processed  int             // 已处理记录数（成功 + 失败 + 跳过）
+	Succeeded  int             // 成功重新加密并写回的记录数
+	Skipped    int             // 被 ShouldReencrypt 跳过或已是新方案密文的记录数
+	Failures   []RecordFailure // 失败的记录，不会阻塞检查点推进，需要单独重试
+	Checkpoint string          // 最后保存的检查点
+}
+
+// ReencryptionRunner 将旧方案加密的数据迁移到新方案（密钥或算法轮换）
+// 以有限并发处理调用方提供的记录，定期保存检查点，支持中断后从检查点继续
+type ReencryptionRunner struct {
+	from  Decrypter
+	to    Encrypter
+	check Decrypter
+	write RecordWriter
+	opts  ReencryptionOptions
+}
+
+// NewReencryptionRunner 创建重新加密执行器
+func NewReencryptionRunner(from Decrypter, to Encrypter, write RecordWriter, opts ReencryptionOptions) (*ReencryptionRunner, error) {
+	if from == nil || to == nil || write == nil {
+		return nil, errors.New("decrypter, encrypter and writer are required")
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.CheckpointEvery <= 0 {
+		opts.CheckpointEvery = 100
+	}
+
+	r := &ReencryptionRunner{from: from, to: to, write: write, opts: opts}
+	if opts.Verify {
+		check, ok := to.(Decrypter)
+		if !ok {
+			return nil, fmt.Errorf("verify requires the new scheme to implement Decrypter, got %T", to)
+		}
+		r.check = check
+	}
+	return r, nil
+}
+
+// reencryptJob 带顺序号的记录，顺序号用于计算可安全保存的检查点
+type reencryptJob struct {
+	seq    int
+	record Record
+}
+
+// Run 执行迁移，返回的错误仅表示遍历、检查点保存失败或上下文取消，单条记录的失败记录在报告中
+func (r *ReencryptionRunner) Run(ctx context.Context, source RecordSource) (ReencryptionReport, error) {
+	var report ReencryptionReport
+
+	after := ""
+	if r.opts.Checkpoint != nil {
+		var err error
+		if after, err = r.opts.Checkpoint.Load(); err != nil {
+			return report, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+	}
+	report.Checkpoint = after
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracker := &checkpointTracker{
+		done:      make(map[int]string),
+		store:     r.opts.Checkpoint,
+		every:     r.opts.CheckpointEvery,
+		current:   after,
+		lastSaved: after,
+	}
+
+	jobs := make(chan reencryptJob)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		fatalErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if fatalErr == nil {
+			fatalErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for i := 0; i < r.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				// 取消后不再处理剩余记录，也不推进检查点，下次运行会重新处理它们
+				if ctx.Err() != nil {
+					continue
+				}
+				skipped, err := r.process(ctx, job.record)
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+
+				mu.Lock()
+				report.Processed++
+				switch {
+				case err != nil:
+					report.Failures = append(report.Failures, RecordFailure{ID: job.record.ID, Err: err})
+				case skipped:
+					report.Skipped++
+				default:
+					report.Succeeded++
+				}
+				mu.Unlock()
+
+				if err != nil && r.opts.OnFailure != nil {
+					r.opts.OnFailure(RecordFailure{ID: job.record.ID, Err: err})
+				}
+				if cpErr := tracker.complete(job.seq, job.record.ID); cpErr != nil {
+					fail(fmt.Errorf("failed to save checkpoint: %w", cpErr))
+				}
+			}
+		}()
+	}
+
+	seq := 0
+	iterErr := source.Each(ctx, after, func(record Record) error {
+		select {
+		case jobs <- reencryptJob{seq: seq, record: record}:
+			seq++
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	if fatalErr == nil {
+		if cpErr := tracker.flush(); cpErr != nil {
+			fatalErr = fmt.Errorf("failed to save checkpoint: %w", cpErr)
+		}
+	}
+	report.Checkpoint = tracker.saved()
+
+	switch {
+	case fatalErr != nil:
+		return report, fatalErr
+	case iterErr != nil:
+		return report, iterErr
+	}
+	return report, ctx.Err()
+}
+
+// process 处理单条记录，返回是否被跳过
+func (r *ReencryptionRunner) process(ctx context.Context, record Record) (bool, error) {
+	if r.opts.ShouldReencrypt != nil && !r.opts.ShouldReencrypt(record) {
+		return true, nil
+	}
+
+	plaintext, err := r.from.Decrypt(record.Ciphertext)
+	if err != nil {
+		// 中断后恢复时，检查点之后可能有记录已经迁移过，能被新方案解密的视为已完成
+		if dec, ok := r.to.(Decrypter); ok {
+			if _, newErr := dec.Decrypt(record.Ciphertext); newErr == nil {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("decrypt: %w", err)
+	}
+	ciphertext, err := r.to.Encrypt(plaintext)
+	if err != nil {
+		return false, fmt.Errorf("encrypt: %w", err)
+	}
+	if r.check != nil {
+		roundTrip, err := r.check.Decrypt(ciphertext)
+		if err != nil {
+			return false, fmt.Errorf("verify: %w", err)
+		}
+		if !bytes.Equal(roundTrip, plaintext) {
+			return false, ErrRoundTripMismatch
+		}
+	}
+	if err := r.write(ctx, record.ID, ciphertext); err != nil {
+		return false, fmt.Errorf("write: %w", err)
+	}
+	return false, nil
+}
+
+// checkpointTracker 记录完成情况，只有某条记录之前的所有记录都已完成时才推进检查点
+type checkpointTracker struct {
+	mu        sync.Mutex
+	done      map[int]string
+	next      int // 下一个尚未完成的顺序号
+	current   string
+	lastSaved string
+	pending   int // 自上次保存以来推进的记录数
+	store     CheckpointStore
+	every     int
+}
+
+// complete 标记记录完成，达到保存间隔时持久化检查点
+func (t *checkpointTracker) complete(seq int, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[seq] = id
+	for {
+		id, ok := t.done[t.next]
+		if !ok {
+			break
+		}
+		delete(t.done, t.next)
+		t.current = id
+		t.next++
+		t.pending++
+	}
+	if t.pending >= t.every {
+		return t.saveLocked()
+	}
+	return nil
+}
+
+// flush 保存尚未持久化的检查点
+func (t *checkpointTracker) flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == 0 {
+		return nil
+	}
+	return t.saveLocked()
+}
+
+// saved 返回最后保存的检查点，未配置存储时返回已推进到的位置
+func (t *checkpointTracker) saved() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.store == nil {
+		return t.current
+	}
+	return t.lastSaved
+}
+
+func (t *checkpointTracker) saveLocked() error {
+	t.pending = 0
+	if t.store == nil {
+		return nil
+	}
+	if err := t.store.Save(t.current); err != nil {
+		return err
+	}
+	t.lastSaved = t.current
+	return nil
+}
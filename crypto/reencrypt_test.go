@@ -0,0 +1,195 @@
+//go:build !cryptolite && !tinygo
+
+package crypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// memoryRecords 测试用的有序记录集合
+type memoryRecords struct {
+	mu   sync.Mutex
+	ids  []string
+	data map[string]string
+}
+
+func newMemoryRecords(t *testing.T, enc Encrypter, n int) *memoryRecords {
+	t.Helper()
+	m := &memoryRecords{data: make(map[string]string)}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("rec-%04d", i)
+		ct, err := enc.Encrypt([]byte("secret-" + id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.ids = append(m.ids, id)
+		m.data[id] = ct
+	}
+	return m
+}
+
+func (m *memoryRecords) Each(ctx context.Context, after string, fn func(Record) error) error {
+	start := sort.SearchStrings(m.ids, after)
+	if start < len(m.ids) && m.ids[start] == after {
+		start++
+	}
+	for _, id := range m.ids[start:] {
+		m.mu.Lock()
+		ct := m.data[id]
+		m.mu.Unlock()
+		if err := fn(Record{ID: id, Ciphertext: ct}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryRecords) write(_ context.Context, id, ct string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[id] = ct
+	return nil
+}
+
+func TestReencryptionRunner_KeyRotation(t *testing.T) {
+	ring := NewKeyRing()
+	if err := ring.AddKey("v1", randomKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	records := newMemoryRecords(t, ring, 250)
+
+	if err := ring.AddKey("v2", randomKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.SetPrimary("v2"); err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint"))
+	runner, err := NewReencryptionRunner(ring, ring, records.write, ReencryptionOptions{
+		Concurrency:     8,
+		Checkpoint:      checkpoint,
+		CheckpointEvery: 50,
+		Verify:          true,
+		ShouldReencrypt: func(r Record) bool { return ring.NeedsReencryption(r.Ciphertext) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := runner.Run(context.Background(), records)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Succeeded != 250 || len(report.Failures) != 0 || report.Checkpoint != "rec-0249" {
+		t.Fatalf("report = %+v", report)
+	}
+	if saved, _ := checkpoint.Load(); saved != "rec-0249" {
+		t.Errorf("saved checkpoint = %q", saved)
+	}
+	for id, ct := range records.data {
+		if ring.NeedsReencryption(ct) {
+			t.Fatalf("record %s still uses the old key", id)
+		}
+		pt, err := ring.Decrypt(ct)
+		if err != nil || string(pt) != "secret-"+id {
+			t.Fatalf("record %s decrypts to %q, %v", id, pt, err)
+		}
+	}
+
+	// 从检查点继续时没有剩余记录
+	report, err = runner.Run(context.Background(), records)
+	if err != nil || report.Processed != 0 {
+		t.Errorf("second Run() = %+v, %v", report, err)
+	}
+}
+
+func TestReencryptionRunner_FailuresAndResume(t *testing.T) {
+	oldEnc, _ := NewAESEncryptor(randomKey(t))
+	newEnc, _ := NewAESEncryptor(randomKey(t))
+	records := newMemoryRecords(t, oldEnc, 20)
+	records.data["rec-0005"] = "corrupted"
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	store := &MemoryCheckpointStore{}
+	runner, err := NewReencryptionRunner(oldEnc, newEnc, records.write, ReencryptionOptions{
+		Concurrency:     3,
+		Checkpoint:      store,
+		CheckpointEvery: 1,
+		OnFailure: func(f RecordFailure) {
+			mu.Lock()
+			failed = append(failed, f.ID)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 处理 10 条后中断
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+	source := RecordSourceFunc(func(ctx context.Context, after string, fn func(Record) error) error {
+		return records.Each(ctx, after, func(r Record) error {
+			if count == 10 {
+				cancel()
+			}
+			count++
+			return fn(r)
+		})
+	})
+	report, err := runner.Run(ctx, source)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	if report.Checkpoint == "" || report.Checkpoint > "rec-0009" {
+		t.Fatalf("checkpoint after cancel = %q", report.Checkpoint)
+	}
+
+	// 继续运行，处理剩余记录
+	report2, err := runner.Run(context.Background(), records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report2.Checkpoint != "rec-0019" {
+		t.Errorf("final checkpoint = %q", report2.Checkpoint)
+	}
+	// 检查点未越过损坏记录时，恢复运行会再次报告它
+	for _, id := range failed {
+		if id != "rec-0005" {
+			t.Errorf("unexpected failure for %s", id)
+		}
+	}
+	if len(failed) == 0 || len(report2.Failures) > 1 {
+		t.Errorf("failures = %v, report = %+v", failed, report2.Failures)
+	}
+	for id, ct := range records.data {
+		if id == "rec-0005" {
+			continue
+		}
+		if pt, err := newEnc.Decrypt(ct); err != nil || string(pt) != "secret-"+id {
+			t.Fatalf("record %s not migrated: %v", id, err)
+		}
+	}
+}
+
+func TestNewReencryptionRunner_VerifyRequiresDecrypter(t *testing.T) {
+	oldEnc, _ := NewAESEncryptor(randomKey(t))
+	encryptOnly := struct{ Encrypter }{oldEnc}
+	noop := func(context.Context, string, string) error { return nil }
+	if _, err := NewReencryptionRunner(oldEnc, encryptOnly, noop, ReencryptionOptions{Verify: true}); err == nil {
+		t.Error("expected error when new scheme cannot decrypt")
+	}
+	if _, err := NewReencryptionRunner(oldEnc, nil, noop, ReencryptionOptions{}); err == nil {
+		t.Error("expected error for nil encrypter")
+	}
+}
@@ -0,0 +1,181 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// CodeBatchPartialFailure 批量操作部分或全部失败时 BatchResult.Err 使用的错误码
+const CodeBatchPartialFailure = "BATCH_PARTIAL_FAILURE"
+
+// BatchItemResult 批量操作中单个条目的结果
+type BatchItemResult struct {
+	Index int    `json:"index"`           // 条目在请求中的位置
+	Key   string `json:"key,omitempty"`   // 业务标识，如 ID 或导入行号
+	Error *Error `json:"error,omitempty"` // 失败原因，成功时为 nil
+}
+
+// Succeeded 判断条目是否成功
+func (r BatchItemResult) Succeeded() bool {
+	return r.Error == nil
+}
+
+// BatchSummary 批量操作结果汇总
+type BatchSummary struct {
+	Total     int            `json:"total"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	ByCode    map[string]int `json:"by_code,omitempty"` // 按错误码统计的失败数
+}
+
+// BatchResult 记录批量导入、批量删除等操作中每个条目的成败，可并发记录
+type BatchResult struct {
+	mu    sync.Mutex
+	items []BatchItemResult
+}
+
+// NewBatchResult 创建批量结果，capacity 为预计条目数
+func NewBatchResult(capacity int) *BatchResult {
+	return &BatchResult{items: make([]BatchItemResult, 0, capacity)}
+}
+
+// Success 记录成功的条目
+func (b *BatchResult) Success(index int, key string) {
+	b.Record(index, key, nil)
+}
+
+// Fail 记录失败的条目，非 *Error 的错误会包装为内部错误
+func (b *BatchResult) Fail(index int, key string, err error) {
+	if err == nil {
+		err = New(CodeInternal, InternalError.Message)
+	}
+	b.Record(index, key, err)
+}
+
+// Record 根据 err 记录条目结果，err 为 nil 表示成功
+func (b *BatchResult) Record(index int, key string, err error) {
+	item := BatchItemResult{Index: index, Key: key}
+	if err != nil {
+		item.Error = toBatchError(err)
+	}
+
+	b.mu.Lock()
+	b.items = append(b.items, item)
+	b.mu.Unlock()
+}
+
+// toBatchError 取错误链中的 *Error，没有时按 wrapPlainError 包装并触发上报
+func toBatchError(err error) *Error {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e
+	}
+	return reportCreated(wrapPlainError(err))
+}
+
+// Items 返回按 Index 排序的全部条目
+func (b *BatchResult) Items() []BatchItemResult {
+	b.mu.Lock()
+	items := make([]BatchItemResult, len(b.items))
+	copy(items, b.items)
+	b.mu.Unlock()
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Index < items[j].Index })
+	return items
+}
+
+// Failures 返回按 Index 排序的失败条目
+func (b *BatchResult) Failures() []BatchItemResult {
+	items := b.Items()
+	failures := items[:0]
+	for _, item := range items {
+		if !item.Succeeded() {
+			failures = append(failures, item)
+		}
+	}
+	return failures
+}
+
+// Summary 返回成功、失败数量以及按错误码统计的失败数
+func (b *BatchResult) Summary() BatchSummary {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	summary := BatchSummary{Total: len(b.items)}
+	for _, item := range b.items {
+		if item.Succeeded() {
+			summary.Succeeded++
+			continue
+		}
+		summary.Failed++
+		if summary.ByCode == nil {
+			summary.ByCode = make(map[string]int)
+		}
+		summary.ByCode[item.Error.Code]++
+	}
+	return summary
+}
+
+// HasFailures 判断是否存在失败条目
+func (b *BatchResult) HasFailures() bool {
+	return b.Summary().Failed > 0
+}
+
+// StatusCode 返回建议的 HTTP 状态码：
+// 全部成功 200，部分失败 207（Multi-Status），全部失败 422
+func (b *BatchResult) StatusCode() int {
+	summary := b.Summary()
+	switch {
+	case summary.Failed == 0:
+		return http.StatusOK
+	case summary.Succeeded == 0:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusMultiStatus
+	}
+}
+
+// Err 全部成功时返回 nil，否则返回汇总错误，上下文中包含失败数与按错误码的统计
+func (b *BatchResult) Err() error {
+	summary := b.Summary()
+	if summary.Failed == 0 {
+		return nil
+	}
+	return New(CodeBatchPartialFailure, "批量操作存在失败条目").
+		WithLocalizedMessage(LocaleEN, "Some items in the batch operation failed").
+		WithContext("total", summary.Total).
+		WithContext("failed", summary.Failed).
+		WithContext("failed_by_code", summary.ByCode)
+}
+
+// batchItemResponse 序列化输出的单个条目
+type batchItemResponse struct {
+	Index int            `json:"index"`
+	Key   string         `json:"key,omitempty"`
+	Error *ErrorResponse `json:"error,omitempty"`
+}
+
+// MarshalJSON 输出 {"summary": {...}, "items": [...]}，条目按 Index 排序
+// 失败条目与 WriteError 一样经过 NewErrorResponse 处理：上下文脱敏，5xx 条目不输出 details 与 context；
+// 需要完整错误信息（如写日志）时使用 Items
+func (b *BatchResult) MarshalJSON() ([]byte, error) {
+	items := b.Items()
+	body := struct {
+		Summary BatchSummary        `json:"summary"`
+		Items   []batchItemResponse `json:"items"`
+	}{
+		Summary: b.Summary(),
+		Items:   make([]batchItemResponse, len(items)),
+	}
+	for i, item := range items {
+		body.Items[i] = batchItemResponse{Index: item.Index, Key: item.Key}
+		if item.Error != nil {
+			_, resp := NewErrorResponse(item.Error, "", "")
+			body.Items[i].Error = &resp
+		}
+	}
+	return json.Marshal(body)
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBatchResult_Summary(t *testing.T) {
+	b := NewBatchResult(4)
+	b.Success(0, "u1")
+	b.Fail(1, "u2", FromType(InvalidInputError))
+	b.Record(2, "u3", fmt.Errorf("db: %w", New(CodeDatabaseError, "写入失败")))
+	b.Fail(3, "u4", errors.New("boom"))
+
+	summary := b.Summary()
+	if summary.Total != 4 || summary.Succeeded != 1 || summary.Failed != 3 {
+		t.Fatalf("Summary() = %+v", summary)
+	}
+	want := map[string]int{CodeInvalidInput: 1, CodeDatabaseError: 1, CodeInternal: 1}
+	for code, n := range want {
+		if summary.ByCode[code] != n {
+			t.Errorf("ByCode[%s] = %d, want %d", code, summary.ByCode[code], n)
+		}
+	}
+
+	failures := b.Failures()
+	if len(failures) != 3 || failures[0].Key != "u2" {
+		t.Errorf("Failures() = %+v", failures)
+	}
+	if b.StatusCode() != http.StatusMultiStatus {
+		t.Errorf("StatusCode() = %d, want 207", b.StatusCode())
+	}
+	if err := b.Err(); GetCode(err) != CodeBatchPartialFailure {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestBatchResult_StatusCode(t *testing.T) {
+	ok := NewBatchResult(1)
+	ok.Success(0, "")
+	if ok.StatusCode() != http.StatusOK || ok.Err() != nil || ok.HasFailures() {
+		t.Error("all-success batch should be 200 with nil Err")
+	}
+
+	failed := NewBatchResult(1)
+	failed.Fail(0, "", FromType(NotFoundError))
+	if failed.StatusCode() != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode() = %d, want 422", failed.StatusCode())
+	}
+}
+
+func TestBatchResult_ConcurrentAndJSON(t *testing.T) {
+	b := NewBatchResult(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%10 == 0 {
+				b.Fail(i, fmt.Sprint(i), FromType(InvalidInputError))
+				return
+			}
+			b.Success(i, fmt.Sprint(i))
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Summary BatchSummary `json:"summary"`
+		Items   []struct {
+			Index int             `json:"index"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Summary.Failed != 10 || len(decoded.Items) != 100 {
+		t.Fatalf("decoded summary = %+v, items = %d", decoded.Summary, len(decoded.Items))
+	}
+	for i, item := range decoded.Items {
+		if item.Index != i {
+			t.Fatalf("items not sorted: position %d has index %d", i, item.Index)
+		}
+		if (i%10 == 0) != (len(item.Error) > 0) {
+			t.Errorf("item %d error presence mismatch: %s", i, item.Error)
+		}
+	}
+}
+
+func TestBatchResult_MarshalJSONRedacts(t *testing.T) {
+	b := NewBatchResult(1)
+	b.Fail(0, "a", WrapWithType(errors.New("pq: timeout"), DatabaseError).WithDetails("host=10.0.0.1"))
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); strings.Contains(s, "pq:") || strings.Contains(s, "10.0.0.1") {
+		t.Errorf("MarshalJSON leaks 5xx item internals: %s", s)
+	}
+}
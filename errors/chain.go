@@ -0,0 +1,183 @@
+package errors
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxChainDepth 输出错误链的最大层数，防止自引用的错误导致无限递归
+const maxChainDepth = 32
+
+// DumpChain 以缩进树的形式输出错误链，用于调试多层包装的错误
+// 每层输出类型、错误码与消息，fmt.Errorf 包装层只输出自身添加的前缀，
+// 带堆栈的 RichError 额外输出创建位置；errors.Join 等多错误包装会产生分支
+//
+//	*errors.Error: [DATABASE_ERROR] 数据库操作失败
+//	└── *fmt.wrapError: query users
+//	    └── *errors.RichError: code=500001 msg=系统繁忙，请稍后重试
+//	        at repo.(*UserRepo).Find (user_repo.go:42)
+func DumpChain(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	writeChainNode(&b, err, "", "", 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeChainNode 输出一个节点及其子节点
+// linePrefix 为节点所在行的前缀，childPrefix 为其下各行的前缀
+func writeChainNode(b *strings.Builder, err error, linePrefix, childPrefix string, depth int) {
+	fmt.Fprintf(b, "%s%T: %s\n", linePrefix, err, chainMessage(err))
+	if loc := chainLocation(err); loc != "" {
+		fmt.Fprintf(b, "%sat %s\n", childPrefix, loc)
+	}
+
+	children := unwrapAll(err)
+	if depth+1 >= maxChainDepth && len(children) > 0 {
+		fmt.Fprintf(b, "%s└── ...\n", childPrefix)
+		return
+	}
+	for i, child := range children {
+		if i == len(children)-1 {
+			writeChainNode(b, child, childPrefix+"└── ", childPrefix+"    ", depth+1)
+		} else {
+			writeChainNode(b, child, childPrefix+"├── ", childPrefix+"│   ", depth+1)
+		}
+	}
+}
+
+// DumpChainCompact 以单行形式输出错误链，适合写入日志
+// 各层消息由外到内以 " -> " 连接，多错误包装的分支放在 {} 中以 "; " 分隔：
+//
+//	[DATABASE_ERROR] 数据库操作失败 -> query users -> code=500001 msg=系统繁忙，请稍后重试
+func DumpChainCompact(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	writeChainCompact(&b, err, 0)
+	return b.String()
+}
+
+// writeChainCompact 输出单行格式的节点及其子节点
+func writeChainCompact(b *strings.Builder, err error, depth int) {
+	b.WriteString(chainMessage(err))
+
+	children := unwrapAll(err)
+	switch {
+	case len(children) == 0:
+		return
+	case depth+1 >= maxChainDepth:
+		b.WriteString(" -> ...")
+		return
+	case len(children) == 1:
+		b.WriteString(" -> ")
+		writeChainCompact(b, children[0], depth+1)
+		return
+	}
+
+	b.WriteString(" -> {")
+	for i, child := range children {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		writeChainCompact(b, child, depth+1)
+	}
+	b.WriteString("}")
+}
+
+// unwrapAll 返回错误直接包装的所有错误，忽略 nil
+func unwrapAll(err error) []error {
+	var children []error
+	switch e := err.(type) {
+	case *Error:
+		children = []error{e.Original}
+	case interface{ Unwrap() []error }:
+		children = e.Unwrap()
+	case interface{ Unwrap() error }:
+		children = []error{e.Unwrap()}
+	}
+
+	result := make([]error, 0, len(children))
+	for _, child := range children {
+		if child != nil {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// chainMessage 返回错误自身的消息
+// *Error 与 RichError 的 Error() 不包含被包装的错误，直接使用；
+// 其他包装错误的消息通常以 ": " + 内层消息结尾，去掉这部分只保留本层添加的内容
+func chainMessage(err error) string {
+	switch err.(type) {
+	case *Error, *RichError:
+		return err.Error()
+	}
+
+	msg := err.Error()
+	children := unwrapAll(err)
+	if len(children) == 1 {
+		// %w 按 %v 格式化内层错误，实现了 fmt.Formatter 的错误（如 RichError）与 Error() 不同
+		for _, inner := range []string{children[0].Error(), fmt.Sprint(children[0])} {
+			if trimmed, ok := strings.CutSuffix(msg, ": "+inner); ok {
+				return trimmed
+			}
+			if msg == inner {
+				return "(wrapped)"
+			}
+		}
+	}
+	if len(children) > 1 {
+		// errors.Join 的消息由各子错误按行拼接而成，子节点会单独输出
+		inner := make([]string, len(children))
+		for i, child := range children {
+			inner[i] = child.Error()
+		}
+		if msg == strings.Join(inner, "\n") {
+			return fmt.Sprintf("(%d errors)", len(children))
+		}
+	}
+	return msg
+}
+
+// chainLocation 返回带堆栈的错误的创建位置，跳过本包内 RichDBError 等构造函数的栈帧，
+// 没有堆栈时返回空字符串
+func chainLocation(err error) string {
+	e, ok := err.(*RichError)
+	if !ok || e.stack == nil {
+		return ""
+	}
+	frames := runtime.CallersFrames(*e.stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !isPackageFrame(frame) {
+			return formatFrame(frame)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packagePrefix 本包函数名的前缀
+var packagePrefix = reflect.TypeOf(Error{}).PkgPath() + "."
+
+// isPackageFrame 判断栈帧是否属于本包的非测试代码
+func isPackageFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, packagePrefix) && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// formatFrame 输出 "pkg.Func (file.go:42)"
+func formatFrame(frame runtime.Frame) string {
+	name := frame.Function
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return fmt.Sprintf("%s (%s:%d)", name, filepath.Base(frame.File), frame.Line)
+}
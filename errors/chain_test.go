@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDumpChain(t *testing.T) {
+	root := errors.New("connection refused")
+	rich := RichDBError(root)
+	wrapped := fmt.Errorf("query users: %w", rich)
+	err := WrapWithType(wrapped, DatabaseError)
+
+	got := DumpChain(err)
+	lines := strings.Split(got, "\n")
+	want := []string{
+		"*errors.Error: [DATABASE_ERROR] 数据库操作失败",
+		"└── *fmt.wrapError: query users",
+		"    └── *errors.RichError: code=500001 msg=系统繁忙，请稍后重试",
+	}
+	if len(lines) != 5 {
+		t.Fatalf("DumpChain() =\n%s", got)
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	if !strings.HasPrefix(lines[3], "        at errors.TestDumpChain (chain_test.go:") {
+		t.Errorf("location line = %q", lines[3])
+	}
+	if lines[4] != "        └── *errors.errorString: connection refused" {
+		t.Errorf("root line = %q", lines[4])
+	}
+}
+
+func TestDumpChain_Join(t *testing.T) {
+	err := fmt.Errorf("save: %w", errors.Join(New(CodeInvalidInput, "a"), errors.New("b")))
+
+	want := strings.Join([]string{
+		"*fmt.wrapError: save",
+		"└── *errors.joinError: (2 errors)",
+		"    ├── *errors.Error: [INVALID_INPUT] a",
+		"    └── *errors.errorString: b",
+	}, "\n")
+	if got := DumpChain(err); got != want {
+		t.Errorf("DumpChain() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDumpChainCompact(t *testing.T) {
+	err := WrapWithType(fmt.Errorf("query users: %w", errors.New("connection refused")), DatabaseError)
+	want := "[DATABASE_ERROR] 数据库操作失败 -> query users -> connection refused"
+	if got := DumpChainCompact(err); got != want {
+		t.Errorf("DumpChainCompact() = %q, want %q", got, want)
+	}
+
+	joined := errors.Join(errors.New("a"), fmt.Errorf("b: %w", errors.New("c")))
+	if got := DumpChainCompact(joined); got != "(2 errors) -> {a; b -> c}" {
+		t.Errorf("DumpChainCompact(join) = %q", got)
+	}
+
+	if DumpChain(nil) != "<nil>" || DumpChainCompact(nil) != "<nil>" {
+		t.Error("nil error should dump as <nil>")
+	}
+}
@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// collectorKey context 中 Collector 的键
+type collectorKey struct{}
+
+// Collector 收集单个请求处理过程中的非致命错误
+// 替代分散的"记录日志后继续"写法，让这些错误与请求结果一起上报
+type Collector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewCollector 创建错误收集器
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add 追加一个非致命错误，nil 会被忽略
+func (c *Collector) Add(err error) {
+	if c == nil || err == nil {
+		return
+	}
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+// Errors 返回已收集错误的副本
+func (c *Collector) Errors() []error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]error(nil), c.errs...)
+}
+
+// Len 返回已收集的错误数量
+func (c *Collector) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.errs)
+}
+
+// WithCollector 在 context 中挂载新的收集器
+func WithCollector(ctx context.Context) (context.Context, *Collector) {
+	c := NewCollector()
+	return context.WithValue(ctx, collectorKey{}, c), c
+}
+
+// CollectorFromContext 获取 context 中的收集器，不存在时返回 nil
+// 返回的 nil 收集器可安全调用 Add/Errors/Len
+func CollectorFromContext(ctx context.Context) *Collector {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(collectorKey{}).(*Collector)
+	return c
+}
+
+// Collect 将非致命错误追加到 context 中的收集器
+// context 中没有收集器时返回 false，调用方可自行降级为直接记录日志
+func Collect(ctx context.Context, err error) bool {
+	c := CollectorFromContext(ctx)
+	if c == nil || err == nil {
+		return false
+	}
+	c.Add(err)
+	return true
+}
+
+// RequestOutcome 请求结束时的汇总信息
+type RequestOutcome struct {
+	Method   string
+	Path     string
+	Status   int
+	Duration time.Duration
+	Errors   []error
+}
+
+// OutcomeReporter 请求结束时调用的上报函数
+type OutcomeReporter func(ctx context.Context, outcome RequestOutcome)
+
+// SlogOutcomeReporter 返回使用 slog 记录请求结果的上报函数，只在收集到错误时输出
+// logger 为 nil 时使用 slog.Default()
+func SlogOutcomeReporter(logger *slog.Logger) OutcomeReporter {
+	return func(ctx context.Context, outcome RequestOutcome) {
+		if len(outcome.Errors) == 0 {
+			return
+		}
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		codes := make([]string, len(outcome.Errors))
+		messages := make([]string, len(outcome.Errors))
+		for i, err := range outcome.Errors {
+			codes[i] = GetCode(err)
+			messages[i] = err.Error()
+		}
+		l.WarnContext(ctx, "request completed with non-fatal errors",
+			slog.String("method", outcome.Method),
+			slog.String("path", outcome.Path),
+			slog.Int("status", outcome.Status),
+			slog.Duration("duration", outcome.Duration),
+			slog.Int("error_count", len(outcome.Errors)),
+			slog.Any("error_codes", codes),
+			slog.Any("errors", messages),
+		)
+	}
+}
+
+// CollectorMiddleware 为每个请求挂载收集器，并在请求结束后调用 report 汇总上报
+// 处理函数 panic 时与 HTTPMiddleware 一样恢复并以内部错误响应返回，panic 作为最后一个错误一并上报；
+// report 为 nil 时使用 SlogOutcomeReporter(nil)
+func CollectorMiddleware(report OutcomeReporter) func(http.Handler) http.Handler {
+	if report == nil {
+		report = SlogOutcomeReporter(nil)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, collector := WithCollector(r.Context())
+			r = r.WithContext(ctx)
+			rec := newStatusRecorder(w)
+
+			defer func() {
+				v := recover()
+				errs := collector.Errors()
+				if v != nil && v != http.ErrAbortHandler {
+					errs = append(errs, handlePanic(rec, r, v))
+				}
+				report(ctx, RequestOutcome{
+					Method:   r.Method,
+					Path:     r.URL.Path,
+					Status:   rec.status,
+					Duration: time.Since(start),
+					Errors:   errs,
+				})
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// statusRecorder 记录响应状态码的 ResponseWriter 包装
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// newStatusRecorder 包装 ResponseWriter，未显式写入状态码时视为 200
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+// WriteHeader 记录状态码
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write 写入响应体
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap 允许 http.ResponseController 访问底层 ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCollect_WithoutCollector(t *testing.T) {
+	if Collect(context.Background(), errors.New("x")) {
+		t.Error("Collect should report false when no collector is attached")
+	}
+
+	var c *Collector
+	c.Add(errors.New("x"))
+	if c.Len() != 0 || c.Errors() != nil {
+		t.Error("nil collector should be a no-op")
+	}
+}
+
+func TestCollectorMiddleware(t *testing.T) {
+	var got RequestOutcome
+	mw := CollectorMiddleware(func(ctx context.Context, outcome RequestOutcome) {
+		got = outcome
+	})
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Collect(r.Context(), New(CodeExternalService, "推荐服务不可用"))
+		Collect(r.Context(), nil)
+		Collect(r.Context(), errors.New("cache miss"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.Status != http.StatusAccepted {
+		t.Errorf("Status = %d, want %d", got.Status, http.StatusAccepted)
+	}
+	if got.Path != "/orders" || got.Method != http.MethodGet {
+		t.Errorf("unexpected request info: %s %s", got.Method, got.Path)
+	}
+	if len(got.Errors) != 2 {
+		t.Fatalf("collected %d errors, want 2", len(got.Errors))
+	}
+	if GetCode(got.Errors[0]) != CodeExternalService {
+		t.Errorf("first error code = %s", GetCode(got.Errors[0]))
+	}
+}
+
+func TestCollectorMiddleware_Panic(t *testing.T) {
+	var got RequestOutcome
+	handler := CollectorMiddleware(func(ctx context.Context, outcome RequestOutcome) {
+		got = outcome
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Collect(r.Context(), New(CodeExternalService, "推荐服务不可用"))
+		panic("nil map")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	if rec.Code != http.StatusInternalServerError || got.Status != http.StatusInternalServerError {
+		t.Errorf("response status = %d, outcome status = %d, want 500", rec.Code, got.Status)
+	}
+	if len(got.Errors) != 2 || GetCode(got.Errors[0]) != CodeExternalService || GetCode(got.Errors[1]) != CodeInternal {
+		t.Fatalf("outcome errors = %v, want collected error followed by panic", got.Errors)
+	}
+	if !strings.Contains(errors.Unwrap(got.Errors[1]).Error(), "nil map") {
+		t.Errorf("panic error should carry the panic value: %v", errors.Unwrap(got.Errors[1]))
+	}
+}
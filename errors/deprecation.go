@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Deprecation 一条错误码废弃规则
+// 过渡期内响应仍输出旧错误码，同时通过 ErrorResponse.ReplacedBy 给出新错误码，
+// 便于 API 调用方逐步迁移；Sunset 之后响应只输出新错误码
+type Deprecation struct {
+	Code        string    `json:"code"`
+	Replacement string    `json:"replacement"`
+	Since       time.Time `json:"since"`
+	Sunset      time.Time `json:"sunset,omitempty"` // 过渡期结束时间，零值表示不限期
+	Hits        int64     `json:"hits"`             // 标记废弃以来旧错误码仍被输出的次数
+	LastSeen    time.Time `json:"last_seen,omitempty"`
+}
+
+// InTransition 判断给定时间是否仍处于过渡期
+func (d Deprecation) InTransition(now time.Time) bool {
+	return d.Sunset.IsZero() || now.Before(d.Sunset)
+}
+
+var (
+	deprecationsMu sync.RWMutex
+	deprecations   = make(map[string]*Deprecation)
+)
+
+// MarkDeprecated 将错误码标记为废弃，由 replacement 取代，过渡期不限期
+// 代码中仍可继续使用旧错误码，响应会同时输出新旧两个错误码，直到调用方完成迁移
+func MarkDeprecated(code, replacement string) {
+	MarkDeprecatedUntil(code, replacement, time.Time{})
+}
+
+// MarkDeprecatedUntil 同 MarkDeprecated，过渡期在 sunset 结束，之后响应只输出新错误码
+// 错误码为空或与替换码相同时 panic；对同一错误码重复调用会覆盖原有规则并重置统计
+func MarkDeprecatedUntil(code, replacement string, sunset time.Time) {
+	if code == "" || replacement == "" || code == replacement {
+		panic("errors: invalid deprecation " + code + " -> " + replacement)
+	}
+
+	deprecationsMu.Lock()
+	defer deprecationsMu.Unlock()
+	deprecations[code] = &Deprecation{
+		Code:        code,
+		Replacement: replacement,
+		Since:       time.Now(),
+		Sunset:      sunset,
+	}
+}
+
+// Undeprecate 取消错误码的废弃标记
+func Undeprecate(code string) {
+	deprecationsMu.Lock()
+	delete(deprecations, code)
+	deprecationsMu.Unlock()
+}
+
+// LookupDeprecation 返回错误码的废弃规则
+func LookupDeprecation(code string) (Deprecation, bool) {
+	deprecationsMu.RLock()
+	defer deprecationsMu.RUnlock()
+	d, ok := deprecations[code]
+	if !ok {
+		return Deprecation{}, false
+	}
+	return *d, true
+}
+
+// DeprecationReport 返回所有废弃规则及旧错误码的输出次数（按次数从高到低排序）
+// Hits 不为零说明仍有代码路径在产生旧错误码，需要在过渡期结束前迁移
+func DeprecationReport() []Deprecation {
+	deprecationsMu.RLock()
+	defer deprecationsMu.RUnlock()
+
+	result := make([]Deprecation, 0, len(deprecations))
+	for _, d := range deprecations {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Hits != result[j].Hits {
+			return result[i].Hits > result[j].Hits
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// emitCode 返回响应中输出的错误码与替换码，旧错误码被输出时累计次数
+// 过渡期内输出旧错误码并给出替换码，过渡期结束后只输出替换码
+func emitCode(code string) (emitted, replacedBy string) {
+	deprecationsMu.Lock()
+	defer deprecationsMu.Unlock()
+
+	d, ok := deprecations[code]
+	if !ok {
+		return code, ""
+	}
+	now := time.Now()
+	d.Hits++
+	d.LastSeen = now
+	if d.InTransition(now) {
+		return code, d.Replacement
+	}
+	return d.Replacement, ""
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMarkDeprecated(t *testing.T) {
+	defer Undeprecate("USER_MISSING")
+	RegisterHTTPStatus("USER_NOT_FOUND", http.StatusNotFound)
+	MarkDeprecated("USER_MISSING", "USER_NOT_FOUND")
+
+	err := New("USER_MISSING", "用户不存在")
+	if got := HTTPStatus(err); got != http.StatusNotFound {
+		t.Errorf("HTTPStatus() = %d, want status of the replacement", got)
+	}
+
+	// 过渡期内同时输出新旧错误码
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, err)
+	resp := decodeErrorResponse(t, rec)
+	if resp.Code != "USER_MISSING" || resp.ReplacedBy != "USER_NOT_FOUND" {
+		t.Errorf("response codes = %q, %q", resp.Code, resp.ReplacedBy)
+	}
+
+	// 未废弃的错误码不输出 replaced_by
+	if _, resp := NewErrorResponse(New(CodeNotFound, "x"), "", ""); resp.ReplacedBy != "" {
+		t.Errorf("ReplacedBy = %q, want empty", resp.ReplacedBy)
+	}
+
+	d, ok := LookupDeprecation("USER_MISSING")
+	if !ok || d.Hits != 1 || d.LastSeen.IsZero() {
+		t.Errorf("LookupDeprecation() = %+v, %v", d, ok)
+	}
+}
+
+func TestMarkDeprecatedUntil(t *testing.T) {
+	defer Undeprecate("ORDER_GONE")
+	MarkDeprecatedUntil("ORDER_GONE", CodeNotFound, time.Now().Add(-time.Minute))
+
+	// 过渡期结束后只输出新错误码
+	_, resp := NewErrorResponse(New("ORDER_GONE", "订单不存在"), "", "")
+	if resp.Code != CodeNotFound || resp.ReplacedBy != "" {
+		t.Errorf("response codes after sunset = %q, %q", resp.Code, resp.ReplacedBy)
+	}
+
+	Undeprecate("ORDER_GONE")
+	if _, ok := LookupDeprecation("ORDER_GONE"); ok {
+		t.Error("Undeprecate should remove the rule")
+	}
+}
+
+func TestDeprecationReport(t *testing.T) {
+	defer Undeprecate("OLD_A")
+	defer Undeprecate("OLD_B")
+	MarkDeprecated("OLD_A", "NEW_A")
+	MarkDeprecated("OLD_B", "NEW_B")
+
+	for i := 0; i < 3; i++ {
+		NewErrorResponse(New("OLD_B", "b"), "", "")
+	}
+
+	report := DeprecationReport()
+	if len(report) != 2 || report[0].Code != "OLD_B" || report[0].Hits != 3 || report[1].Hits != 0 {
+		t.Errorf("DeprecationReport() = %+v", report)
+	}
+}
+
+func TestMarkDeprecated_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when replacement equals code")
+		}
+	}()
+	MarkDeprecated("SAME", "SAME")
+}
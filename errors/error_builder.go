@@ -7,50 +7,60 @@ import (
 
 // New 使用给定的错误码和消息创建新的错误
 func New(code, message string) *Error {
-	return &Error{
+	checkStrict(code, message, "")
+	return reportCreated(&Error{
 		Code:      code,
 		Message:   message,
 		Timestamp: time.Now(),
 		Context:   make(map[string]interface{}),
-	}
+	})
 }
 
 // NewWithDetails 使用错误码、消息和详情创建新的错误
 func NewWithDetails(code, message, details string) *Error {
-	return &Error{
+	checkStrict(code, message, "")
+	return reportCreated(&Error{
 		Code:      code,
 		Message:   message,
 		Details:   details,
 		Timestamp: time.Now(),
 		Context:   make(map[string]interface{}),
-	}
+	})
 }
 
 // Wrap 包装现有错误并添加上下文
 func Wrap(err error, code, message string) *Error {
-	return &Error{
+	checkStrict(code, message, "")
+	return reportCreated(&Error{
 		Code:      code,
 		Message:   message,
 		Timestamp: time.Now(),
 		Context:   make(map[string]interface{}),
 		Original:  err,
-	}
+	})
 }
 
 // WrapWithDetails 包装现有错误并添加错误码、消息和详情
 func WrapWithDetails(err error, code, message, details string) *Error {
-	return &Error{
+	checkStrict(code, message, "")
+	return reportCreated(&Error{
 		Code:      code,
 		Message:   message,
 		Details:   details,
 		Timestamp: time.Now(),
 		Context:   make(map[string]interface{}),
 		Original:  err,
-	}
+	})
 }
 
 // FromType 从预定义的ErrorType创建新的错误
 func FromType(errorType ErrorType) *Error {
+	return reportCreated(fromType(errorType))
+}
+
+// fromType 从预定义的ErrorType创建错误，不触发上报
+func fromType(errorType ErrorType) *Error {
+	checkStrict(errorType.Code, errorType.Message, errorType.Severity)
 	err := &Error{
 		Code:      errorType.Code,
 		Message:   errorType.Message,
@@ -61,19 +71,26 @@ func FromType(errorType ErrorType) *Error {
 	// 将严重级别和类别添加到上下文
 	err.Context["severity"] = errorType.Severity
 	err.Context["category"] = errorType.Category
+	applyTypeMessages(err, errorType)
 	
 	return err
 }
 
 // FromTypeWithDetails 从预定义的ErrorType创建带额外详情的错误
 func FromTypeWithDetails(errorType ErrorType, details string) *Error {
-	err := FromType(errorType)
+	err := fromType(errorType)
 	err.Details = details
-	return err
+	return reportCreated(err)
 }
 
 // WrapWithType 使用预定义的ErrorType包装现有错误
 func WrapWithType(err error, errorType ErrorType) *Error {
+	return reportCreated(wrapWithType(err, errorType))
+}
+
+// wrapWithType 使用预定义的ErrorType包装错误，不触发上报
+func wrapWithType(err error, errorType ErrorType) *Error {
+	checkStrict(errorType.Code, errorType.Message, errorType.Severity)
 	wrappedErr := &Error{
 		Code:      errorType.Code,
 		Message:   errorType.Message,
@@ -85,10 +102,19 @@ func WrapWithType(err error, errorType ErrorType) *Error {
 	// 将严重级别和类别添加到上下文
 	wrappedErr.Context["severity"] = errorType.Severity
 	wrappedErr.Context["category"] = errorType.Category
+	applyTypeMessages(wrappedErr, errorType)
 	
 	return wrappedErr
 }
 
+// applyTypeMessages 将预定义类型的中英文消息写入错误
+func applyTypeMessages(err *Error, errorType ErrorType) {
+	if errorType.MessageEN != "" {
+		err.WithLocalizedMessage(LocaleZH, errorType.Message)
+		err.WithLocalizedMessage(LocaleEN, errorType.MessageEN)
+	}
+}
+
 // Builder 提供用于构建错误的流式接口
 type Builder struct {
 	err *Error
@@ -128,6 +154,12 @@ func (b *Builder) Wrap(err error) *Builder {
 	return b
 }
 
+// LocalizedMessage 设置指定语言的错误消息
+func (b *Builder) LocalizedMessage(locale, message string) *Builder {
+	b.err.WithLocalizedMessage(locale, message)
+	return b
+}
+
 // Context 添加上下文信息
 func (b *Builder) Context(key string, value interface{}) *Builder {
 	b.err.Context[key] = value
@@ -166,63 +198,65 @@ func (b *Builder) Operation(operation string) *Builder {
 
 // Build 返回构造的错误
 func (b *Builder) Build() *Error {
-	return b.err
+	severity, _ := b.err.Context["severity"].(Severity)
+	checkStrict(b.err.Code, b.err.Message, severity)
+	return reportCreated(b.err)
 }
 
 // 常见错误类型的便捷函数
 
 // Internal 创建一个内部服务器错误
 func Internal(message string) *Error {
-	return FromType(InternalError).WithMessage(message)
+	return reportCreated(fromType(InternalError).WithMessage(message))
 }
 
 // NotFound 创建一个未找到错误
 func NotFound(resource string) *Error {
-	return FromType(NotFoundError).WithDetails(fmt.Sprintf("资源 '%s' 未找到", resource))
+	return reportCreated(fromType(NotFoundError).WithDetails(fmt.Sprintf("资源 '%s' 未找到", resource)))
 }
 
 // Unauthorized 创建一个未授权错误
 func Unauthorized(message string) *Error {
-	return FromType(UnauthorizedError).WithMessage(message)
+	return reportCreated(fromType(UnauthorizedError).WithMessage(message))
 }
 
 // Forbidden 创建一个禁止访问错误
 func Forbidden(message string) *Error {
-	return FromType(ForbiddenError).WithMessage(message)
+	return reportCreated(fromType(ForbiddenError).WithMessage(message))
 }
 
 // InvalidInput 创建一个无效输入错误
 func InvalidInput(field, reason string) *Error {
-	return FromType(InvalidInputError).
+	return reportCreated(fromType(InvalidInputError).
 		WithDetails(fmt.Sprintf("字段 '%s': %s", field, reason)).
-		WithContext("field", field)
+		WithContext("field", field))
 }
 
 // MissingField 创建一个缺少字段错误
 func MissingField(field string) *Error {
-	return FromType(MissingFieldError).
+	return reportCreated(fromType(MissingFieldError).
 		WithDetails(fmt.Sprintf("必填字段 '%s' 缺失", field)).
-		WithContext("field", field)
+		WithContext("field", field))
 }
 
 // Timeout 创建一个超时错误
 func Timeout(operation string, duration time.Duration) *Error {
-	return FromType(TimeoutError).
+	return reportCreated(fromType(TimeoutError).
 		WithDetails(fmt.Sprintf("操作 '%s' 在 %v 后超时", operation, duration)).
 		WithContext("operation", operation).
-		WithContext("timeout_duration", duration)
+		WithContext("timeout_duration", duration))
 }
 
 // Database 创建一个数据库错误
 func Database(operation string, err error) *Error {
-	return WrapWithType(err, DatabaseError).
+	return reportCreated(wrapWithType(err, DatabaseError).
 		WithDetails(fmt.Sprintf("数据库操作 '%s' 失败", operation)).
-		WithContext("operation", operation)
+		WithContext("operation", operation))
 }
 
 // Network 创建一个网络错误
 func Network(operation string, err error) *Error {
-	return WrapWithType(err, NetworkError).
+	return reportCreated(wrapWithType(err, NetworkError).
 		WithDetails(fmt.Sprintf("网络操作 '%s' 失败", operation)).
-		WithContext("operation", operation)
+		WithContext("operation", operation))
 }
@@ -5,6 +5,7 @@ const (
 	// 系统错误
 	CodeInternal      = "INTERNAL_ERROR"
 	CodeTimeout       = "TIMEOUT_ERROR"
+	CodeCanceled      = "REQUEST_CANCELED"
 	CodeUnavailable   = "SERVICE_UNAVAILABLE"
 	CodeNotFound      = "NOT_FOUND"
 	CodeAlreadyExists = "ALREADY_EXISTS"
@@ -63,79 +64,98 @@ const (
 
 // ErrorType 表示带有默认值的预定义错误类型
 type ErrorType struct {
-	Code     string
-	Message  string
-	Severity Severity
-	Category Category
+	Code      string
+	Message   string
+	MessageEN string // 英文消息，为空时不设置英文文本
+	Severity  Severity
+	Category  Category
 }
 
 // 预定义的错误类型
 var (
 	// 系统错误类型
 	InternalError = ErrorType{
-		Code:     CodeInternal,
-		Message:  "发生内部服务器错误",
-		Severity: SeverityCritical,
-		Category: CategorySystem,
+		Code:      CodeInternal,
+		Message:   "发生内部服务器错误",
+		MessageEN: "An internal server error occurred",
+		Severity:  SeverityCritical,
+		Category:  CategorySystem,
 	}
 	
 	TimeoutError = ErrorType{
-		Code:     CodeTimeout,
-		Message:  "操作超时",
-		Severity: SeverityHigh,
-		Category: CategorySystem,
+		Code:      CodeTimeout,
+		Message:   "操作超时",
+		MessageEN: "The operation timed out",
+		Severity:  SeverityHigh,
+		Category:  CategorySystem,
+	}
+	
+	// CanceledError 调用方取消请求（如客户端断开连接），不是服务端故障
+	CanceledError = ErrorType{
+		Code:      CodeCanceled,
+		Message:   "请求已取消",
+		MessageEN: "The request was canceled",
+		Severity:  SeverityLow,
+		Category:  CategorySystem,
 	}
 	
 	NotFoundError = ErrorType{
-		Code:     CodeNotFound,
-		Message:  "资源未找到",
-		Severity: SeverityMedium,
-		Category: CategorySystem,
+		Code:      CodeNotFound,
+		Message:   "资源未找到",
+		MessageEN: "Resource not found",
+		Severity:  SeverityMedium,
+		Category:  CategorySystem,
 	}
 	
 	// 认证错误类型
 	UnauthorizedError = ErrorType{
-		Code:     CodeUnauthorized,
-		Message:  "需要认证",
-		Severity: SeverityHigh,
-		Category: CategoryAuth,
+		Code:      CodeUnauthorized,
+		Message:   "需要认证",
+		MessageEN: "Authentication required",
+		Severity:  SeverityHigh,
+		Category:  CategoryAuth,
 	}
 	
 	ForbiddenError = ErrorType{
-		Code:     CodeForbidden,
-		Message:  "访问被拒绝",
-		Severity: SeverityHigh,
-		Category: CategoryAuth,
+		Code:      CodeForbidden,
+		Message:   "访问被拒绝",
+		MessageEN: "Access denied",
+		Severity:  SeverityHigh,
+		Category:  CategoryAuth,
 	}
 	
 	// 验证错误类型
 	InvalidInputError = ErrorType{
-		Code:     CodeInvalidInput,
-		Message:  "提供了无效的输入",
-		Severity: SeverityMedium,
-		Category: CategoryValidation,
+		Code:      CodeInvalidInput,
+		Message:   "提供了无效的输入",
+		MessageEN: "Invalid input provided",
+		Severity:  SeverityMedium,
+		Category:  CategoryValidation,
 	}
 	
 	MissingFieldError = ErrorType{
-		Code:     CodeMissingField,
-		Message:  "缺少必填字段",
-		Severity: SeverityMedium,
-		Category: CategoryValidation,
+		Code:      CodeMissingField,
+		Message:   "缺少必填字段",
+		MessageEN: "A required field is missing",
+		Severity:  SeverityMedium,
+		Category:  CategoryValidation,
 	}
 	
 	// 网络错误类型
 	NetworkError = ErrorType{
-		Code:     CodeNetworkError,
-		Message:  "网络通信失败",
-		Severity: SeverityHigh,
-		Category: CategoryNetwork,
+		Code:      CodeNetworkError,
+		Message:   "网络通信失败",
+		MessageEN: "Network communication failed",
+		Severity:  SeverityHigh,
+		Category:  CategoryNetwork,
 	}
 	
 	// 数据库错误类型
 	DatabaseError = ErrorType{
-		Code:     CodeDatabaseError,
-		Message:  "数据库操作失败",
-		Severity: SeverityHigh,
-		Category: CategoryDatabase,
+		Code:      CodeDatabaseError,
+		Message:   "数据库操作失败",
+		MessageEN: "Database operation failed",
+		Severity:  SeverityHigh,
+		Category:  CategoryDatabase,
 	}
 )
\ No newline at end of file
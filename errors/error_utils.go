@@ -201,7 +201,7 @@ func Format(err error, format string) string {
 		}
 		parts = append(parts, fmt.Sprintf("Timestamp: %s", customErr.Timestamp.Format("2006-01-02 15:04:05")))
 		if len(customErr.Context) > 0 {
-			contextStr, _ := json.Marshal(customErr.Context)
+			contextStr, _ := json.Marshal(SanitizeContext(customErr.Context))
 			parts = append(parts, fmt.Sprintf("Context: %s", string(contextStr)))
 		}
 		if customErr.Original != nil {
@@ -337,6 +337,13 @@ func Clone(err error) *Error {
 		cloned.Context[k] = deepCopyValue(v)
 	}
 
+	if customErr.Localized != nil {
+		cloned.Localized = make(map[string]string, len(customErr.Localized))
+		for k, v := range customErr.Localized {
+			cloned.Localized[k] = v
+		}
+	}
+
 	return cloned
 }
 
@@ -17,6 +17,8 @@ type ValidationError struct {
 	Value  interface{} `json:"value"`
 	Rule   string      `json:"rule"`
 	Params interface{} `json:"params,omitempty"`
+	// Position 字段在请求体中的位置，由 ValidateJSON 或 WithPositions 设置
+	Position *Position `json:"position,omitempty"`
 }
 
 // NewValidationError creates a new validation error
@@ -39,6 +41,13 @@ func (ve *ValidationError) WithParams(params interface{}) *ValidationError {
 	return ve
 }
 
+// WithPosition adds the location of the field in the request body
+func (ve *ValidationError) WithPosition(pos Position) *ValidationError {
+	ve.Position = &pos
+	ve.Error.WithContext("position", pos)
+	return ve
+}
+
 // Validator provides validation methods
 type Validator struct {
 	errors []*ValidationError
@@ -84,12 +93,16 @@ func (v *Validator) GetError() *Error {
 	for i, err := range v.errors {
 		messages = append(messages, err.Message)
 		fields = append(fields, err.Field)
-		mainErr.WithContext(fmt.Sprintf("error_%d", i), map[string]interface{}{
+		entry := map[string]interface{}{
 			"field":   err.Field,
 			"rule":    err.Rule,
 			"value":   err.Value,
 			"message": err.Message,
-		})
+		}
+		if err.Position != nil {
+			entry["position"] = *err.Position
+		}
+		mainErr.WithContext(fmt.Sprintf("error_%d", i), entry)
 	}
 
 	mainErr.WithDetails(strings.Join(messages, "; "))
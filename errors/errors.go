@@ -41,6 +41,7 @@ package errors
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -48,12 +49,16 @@ import (
 //
 // Error结构体实现了标准error接口，并为应用程序中的结构化错误处理提供了额外功能。
 type Error struct {
-	Code      string                 `json:"code"`      // 错误码 - 用于程序化处理错误
-	Message   string                 `json:"message"`   // 错误消息 - 人类可读的错误描述
-	Details   string                 `json:"details"`   // 详细错误信息 - 额外的错误详情
-	Timestamp time.Time              `json:"timestamp"` // 错误发生时间 - 用于日志和调试
-	Context   map[string]interface{} `json:"context"`   // 上下文信息 - 相关的元数据
-	Original  error                  `json:"original"`  // 原始错误 - 支持错误链
+	Code      string                 `json:"code"`                // 错误码 - 用于程序化处理错误
+	Message   string                 `json:"message"`             // 错误消息 - 人类可读的错误描述
+	Details   string                 `json:"details"`             // 详细错误信息 - 额外的错误详情
+	Timestamp time.Time              `json:"timestamp"`           // 错误发生时间 - 用于日志和调试
+	Context   map[string]interface{} `json:"context"`             // 上下文信息 - 相关的元数据
+	Original  error                  `json:"original"`            // 原始错误 - 支持错误链
+	Localized map[string]string      `json:"localized,omitempty"` // 多语言消息 - 语言标识到消息的映射
+
+	reportedBy     atomic.Pointer[ReportHooks] // 已上报该错误的钩子链，用于避免重复上报
+	suppressionHit atomic.Bool                 // 是否已计入抑制规则的命中次数
 }
 
 // Error 实现 error 接口
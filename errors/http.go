@@ -0,0 +1,338 @@
+package errors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// TraceIDHeader 读取与回写追踪 ID 的请求/响应头
+var TraceIDHeader = "X-Request-ID"
+
+// statusClientClosedRequest 客户端关闭连接时的状态码，沿用 nginx 的约定
+const statusClientClosedRequest = 499
+
+// ErrorResponse 错误响应的统一 JSON 结构
+type ErrorResponse struct {
+	Code       string                 `json:"code"`
+	ReplacedBy string                 `json:"replaced_by,omitempty"` // Code 已废弃时的新错误码，见 MarkDeprecated
+	Message    string                 `json:"message"`
+	Details    string                 `json:"details,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+}
+
+var (
+	httpStatusMu sync.RWMutex
+	httpStatuses = map[string]int{
+		CodeInternal:            http.StatusInternalServerError,
+		CodeTimeout:             http.StatusGatewayTimeout,
+		CodeCanceled:            statusClientClosedRequest,
+		CodeUnavailable:         http.StatusServiceUnavailable,
+		CodeNotFound:            http.StatusNotFound,
+		CodeAlreadyExists:       http.StatusConflict,
+		CodeUnauthorized:        http.StatusUnauthorized,
+		CodeForbidden:           http.StatusForbidden,
+		CodeInvalidToken:        http.StatusUnauthorized,
+		CodeExpiredToken:        http.StatusUnauthorized,
+		CodeInvalidInput:        http.StatusBadRequest,
+		CodeMissingField:        http.StatusBadRequest,
+		CodeInvalidFormat:       http.StatusBadRequest,
+		CodeOutOfRange:          http.StatusBadRequest,
+		CodeInvalidLength:       http.StatusBadRequest,
+		CodeNetworkError:        http.StatusBadGateway,
+		CodeConnectionError:     http.StatusBadGateway,
+		CodeExternalService:     http.StatusBadGateway,
+		CodeDatabaseError:       http.StatusInternalServerError,
+		CodeQueryError:          http.StatusInternalServerError,
+		CodeTransactionError:    http.StatusInternalServerError,
+		CodeBusinessRule:        http.StatusUnprocessableEntity,
+		CodeInsufficientFunds:   http.StatusUnprocessableEntity,
+		CodeQuotaExceeded:       http.StatusTooManyRequests,
+		CodeBatchPartialFailure: http.StatusUnprocessableEntity,
+	}
+
+	// categoryStatuses 错误码未注册时按类别推导的状态码
+	categoryStatuses = map[Category]int{
+		CategorySystem:     http.StatusInternalServerError,
+		CategoryAuth:       http.StatusUnauthorized,
+		CategoryValidation: http.StatusBadRequest,
+		CategoryNetwork:    http.StatusBadGateway,
+		CategoryDatabase:   http.StatusInternalServerError,
+		CategoryBusiness:   http.StatusUnprocessableEntity,
+		CategoryExternal:   http.StatusBadGateway,
+	}
+)
+
+// RegisterHTTPStatus 注册错误码对应的 HTTP 状态码，覆盖内置映射
+func RegisterHTTPStatus(code string, status int) {
+	httpStatusMu.Lock()
+	httpStatuses[code] = status
+	httpStatusMu.Unlock()
+}
+
+// HTTPStatus 返回错误对应的 HTTP 状态码
+// 查找顺序：已注册的错误码 → 废弃错误码的替换码 → 上下文中的类别 → 严重级别（低、中为 400，高、严重为 500）；
+// RichError 使用 HTTPStatus 方法，其他错误以及超时、取消分别返回 500、504、499，nil 返回 200
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var rich *RichError
+	if stderrors.As(err, &rich) {
+		return rich.HTTPStatus()
+	}
+
+	var e *Error
+	if !stderrors.As(err, &e) {
+		switch {
+		case stderrors.Is(err, context.DeadlineExceeded):
+			return http.StatusGatewayTimeout
+		case stderrors.Is(err, context.Canceled):
+			return statusClientClosedRequest
+		}
+		return http.StatusInternalServerError
+	}
+
+	httpStatusMu.RLock()
+	status, ok := httpStatuses[e.Code]
+	httpStatusMu.RUnlock()
+	if ok {
+		return status
+	}
+
+	// 已废弃的错误码未单独注册时沿用替换码的状态码
+	if d, ok := LookupDeprecation(e.Code); ok {
+		httpStatusMu.RLock()
+		status, ok := httpStatuses[d.Replacement]
+		httpStatusMu.RUnlock()
+		if ok {
+			return status
+		}
+	}
+
+	if category, ok := e.Context["category"].(Category); ok {
+		if status, ok := categoryStatuses[category]; ok {
+			return status
+		}
+	}
+	switch e.Context["severity"] {
+	case SeverityLow, SeverityMedium:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// NewErrorResponse 构建错误响应，返回状态码与响应体，可用于 Hertz、Gin 等非 net/http 框架
+// 消息按 acceptLanguage 选择语言；5xx 响应不输出 details 与 context，避免泄露内部信息；
+// 非 *Error 错误按 wrapPlainError 输出取消、超时或内部错误，原始错误信息只应出现在日志中；
+// 已废弃的错误码按 MarkDeprecated 的规则输出并计入 DeprecationReport
+func NewErrorResponse(err error, acceptLanguage, traceID string) (int, ErrorResponse) {
+	status := HTTPStatus(err)
+	resp := ErrorResponse{TraceID: traceID}
+
+	var rich *RichError
+	var e *Error
+	switch {
+	case stderrors.As(err, &rich):
+		resp.Code = strconv.Itoa(rich.Code)
+		resp.Message = rich.Msg
+	case stderrors.As(err, &e):
+		resp.Code = e.Code
+		resp.Message = e.MessageFor(acceptLanguage)
+		if status < http.StatusInternalServerError {
+			resp.Details = e.Details
+			resp.Context = publicContext(e.Context)
+		}
+	default:
+		fallback := wrapPlainError(err)
+		resp.Code = fallback.Code
+		resp.Message = fallback.MessageFor(acceptLanguage)
+	}
+	resp.Code, resp.ReplacedBy = emitCode(resp.Code)
+	return status, resp
+}
+
+// publicContext 返回脱敏后的上下文，去掉仅用于内部分类的 severity、category
+func publicContext(ctx map[string]interface{}) map[string]interface{} {
+	sanitized := SanitizeContext(ctx)
+	delete(sanitized, "severity")
+	delete(sanitized, "category")
+	if len(sanitized) == 0 {
+		return nil
+	}
+	return sanitized
+}
+
+// WriteError 将错误以统一 JSON 结构写入响应，err 为 nil 时不做任何事
+// 追踪 ID 取自 HTTPMiddleware 放入 context 的值，没有时读取 TraceIDHeader 请求头；
+// 设置了全局上报钩子时按请求上下文上报，非 *Error 错误按 wrapPlainError 包装后上报并保留原始错误
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	traceID := TraceIDFromContext(r.Context())
+	if traceID == "" {
+		traceID = r.Header.Get(TraceIDHeader)
+	}
+	status, resp := NewErrorResponse(err, r.Header.Get("Accept-Language"), traceID)
+	writeJSON(w, status, resp)
+
+	if hooks := globalReportHooks.Load(); hooks != nil {
+		hooks.Report(r.Context(), reportableError(err))
+	}
+	if opts, ok := r.Context().Value(httpOptionsKey{}).(HTTPMiddlewareOptions); ok && opts.EmitMetrics {
+		EmitMetric(err)
+	}
+}
+
+// reportableError 返回用于上报的错误：非 *Error、非 RichError 的错误经 wrapPlainError 包装，原始错误作为原因
+func reportableError(err error) error {
+	var rich *RichError
+	var e *Error
+	if stderrors.As(err, &rich) || stderrors.As(err, &e) {
+		return err
+	}
+	return wrapPlainError(err)
+}
+
+// wrapPlainError 包装非 *Error 错误：context 取消映射为 CanceledError，超时映射为 TimeoutError，其他为 InternalError
+// 包装本身不触发上报，由调用方决定是否上报
+func wrapPlainError(err error) *Error {
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return wrapWithType(err, CanceledError)
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return wrapWithType(err, TimeoutError)
+	default:
+		return wrapWithType(err, InternalError)
+	}
+}
+
+// WriteBatch 将批量操作结果写入响应，状态码为 BatchResult.StatusCode
+// 响应体为 BatchResult.MarshalJSON 的输出，失败条目与 WriteError 一样脱敏
+func WriteBatch(w http.ResponseWriter, b *BatchResult) {
+	writeJSON(w, b.StatusCode(), b)
+}
+
+// writeJSON 写入 JSON 响应体
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Default().Warn("failed to write error response", slog.String("error", err.Error()))
+	}
+}
+
+// traceIDKey context 中追踪 ID 的键
+type traceIDKey struct{}
+
+// ContextWithTraceID 在 context 中保存追踪 ID
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext 获取 context 中的追踪 ID，不存在时返回空字符串
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// newTraceID 生成 32 位十六进制追踪 ID
+func newTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HTTPMiddlewareOptions HTTP 中间件选项，零值与 HTTPMiddleware 行为一致
+type HTTPMiddlewareOptions struct {
+	// EmitMetrics 为 true 时，请求中经 WriteError 写入的每个错误（包括恢复的 panic）都调用 EmitMetric
+	EmitMetrics bool
+}
+
+// httpOptionsKey context 中中间件选项的键，WriteError 据此决定是否计入指标
+type httpOptionsKey struct{}
+
+// HTTPMiddleware 为每个请求确定追踪 ID（优先使用 TraceIDHeader 请求头，没有时生成）并写入响应头，
+// 同时恢复处理函数中的 panic，以内部错误响应返回
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return NewHTTPMiddleware(HTTPMiddlewareOptions{})(next)
+}
+
+// NewHTTPMiddleware 按选项创建 HTTPMiddleware
+//
+//	mux.Handle("/users", errors.NewHTTPMiddleware(errors.HTTPMiddlewareOptions{EmitMetrics: true})(handler))
+func NewHTTPMiddleware(opts HTTPMiddlewareOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return httpMiddleware(next, opts)
+	}
+}
+
+// httpMiddleware HTTPMiddleware 的实现
+func httpMiddleware(next http.Handler, opts HTTPMiddlewareOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := r.Header.Get(TraceIDHeader)
+		if traceID == "" {
+			traceID = newTraceID()
+		}
+		w.Header().Set(TraceIDHeader, traceID)
+		ctx := ContextWithTraceID(r.Context(), traceID)
+		if opts != (HTTPMiddlewareOptions{}) {
+			ctx = context.WithValue(ctx, httpOptionsKey{}, opts)
+		}
+		r = r.WithContext(ctx)
+		rec := newStatusRecorder(w)
+
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				handlePanic(rec, r, v)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// handlePanic 记录处理函数的 panic 及堆栈，尚未开始写响应时以内部错误响应返回
+// 返回表示该 panic 的内部错误，panic 值作为原始错误
+func handlePanic(rec *statusRecorder, r *http.Request, v interface{}) *Error {
+	slog.Default().ErrorContext(r.Context(), "panic recovered in http handler",
+		slog.Any("panic", v),
+		slog.String("trace_id", TraceIDFromContext(r.Context())),
+		slog.String("stack", string(debug.Stack())),
+	)
+	err := wrapWithType(fmt.Errorf("panic: %v", v), InternalError)
+	// 已经开始写响应时无法再改写状态码
+	if !rec.wroteHeader {
+		WriteError(rec, r, err)
+	}
+	return err
+}
+
+// HandlerFunc 返回错误的处理函数，错误通过 WriteError 写入响应
+//
+//	mux.Handle("/users", errors.HTTPMiddleware(errors.HandlerFunc(getUser)))
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP 实现 http.Handler 接口
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"registered code", New(CodeNotFound, "用户不存在"), http.StatusNotFound},
+		{"wrapped", fmt.Errorf("load user: %w", New(CodeForbidden, "无权限")), http.StatusForbidden},
+		{"category", New("ORDER_LOCKED", "订单已锁定").WithContext("category", CategoryBusiness), http.StatusUnprocessableEntity},
+		{"low severity", New("CUSTOM", "x").WithContext("severity", SeverityLow), http.StatusBadRequest},
+		{"unknown code", New("CUSTOM", "x"), http.StatusInternalServerError},
+		{"rich", NewRich(RichCodeNotFound, "不存在"), http.StatusNotFound},
+		{"plain", errors.New("boom"), http.StatusInternalServerError},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("%s: HTTPStatus() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewErrorResponse_ContextErrors(t *testing.T) {
+	status, resp := NewErrorResponse(fmt.Errorf("query: %w", context.Canceled), "", "")
+	if status != 499 || resp.Code != CodeCanceled {
+		t.Errorf("canceled: %d %s, want 499 %s", status, resp.Code, CodeCanceled)
+	}
+	status, resp = NewErrorResponse(context.DeadlineExceeded, "", "")
+	if status != http.StatusGatewayTimeout || resp.Code != CodeTimeout {
+		t.Errorf("deadline: %d %s, want 504 %s", status, resp.Code, CodeTimeout)
+	}
+}
+
+func TestRegisterHTTPStatus(t *testing.T) {
+	RegisterHTTPStatus("PAYMENT_REQUIRED", http.StatusPaymentRequired)
+	if got := HTTPStatus(New("PAYMENT_REQUIRED", "需要付费")); got != http.StatusPaymentRequired {
+		t.Errorf("HTTPStatus() = %d, want %d", got, http.StatusPaymentRequired)
+	}
+}
+
+func decodeErrorResponse(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestWriteError(t *testing.T) {
+	err := FromTypeWithDetails(InvalidInputError, "邮箱格式不正确").
+		WithContext("field", "email").
+		WithContext("password", "hunter2")
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set(TraceIDHeader, "trace-1")
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, err)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	resp := decodeErrorResponse(t, rec)
+	if resp.Code != CodeInvalidInput || resp.Message != InvalidInputError.MessageEN {
+		t.Errorf("unexpected code/message: %+v", resp)
+	}
+	if resp.Details != "邮箱格式不正确" || resp.TraceID != "trace-1" {
+		t.Errorf("unexpected details/trace: %+v", resp)
+	}
+	if resp.Context["field"] != "email" || resp.Context["password"] != GetSanitizeConfig().Mask {
+		t.Errorf("context not sanitized: %v", resp.Context)
+	}
+	if _, ok := resp.Context["severity"]; ok {
+		t.Error("internal severity label should not be exposed")
+	}
+}
+
+func TestWriteError_HidesInternalDetails(t *testing.T) {
+	for _, err := range []error{
+		errors.New("pq: connection refused"),
+		WrapWithType(errors.New("pq: connection refused"), DatabaseError).
+			WithDetails("host=10.0.0.1").WithContext("query", "SELECT 1"),
+	} {
+		rec := httptest.NewRecorder()
+		WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want 500", rec.Code)
+		}
+		resp := decodeErrorResponse(t, rec)
+		if resp.Details != "" || resp.Context != nil {
+			t.Errorf("5xx response leaks internals: %+v", resp)
+		}
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	handler := HTTPMiddleware(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if TraceIDFromContext(r.Context()) == "" {
+			t.Error("trace id missing from context")
+		}
+		return New(CodeNotFound, "资源未找到")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	traceID := rec.Header().Get(TraceIDHeader)
+	if len(traceID) != 32 {
+		t.Errorf("generated trace id = %q", traceID)
+	}
+	if resp := decodeErrorResponse(t, rec); resp.TraceID != traceID {
+		t.Errorf("body trace id = %q, header = %q", resp.TraceID, traceID)
+	}
+}
+
+func TestNewHTTPMiddleware_EmitMetrics(t *testing.T) {
+	sink := NewCounterSink()
+	SetMetricsSink(sink)
+	defer SetMetricsSink(nil)
+
+	failing := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(CodeNotFound, "资源未找到")
+	})
+	labels := LabelsOf(New(CodeNotFound, "资源未找到"))
+
+	// 默认不计入指标
+	HTTPMiddleware(failing).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if n := sink.Count(labels); n != 0 {
+		t.Fatalf("HTTPMiddleware emitted %d metrics, want 0", n)
+	}
+
+	handler := NewHTTPMiddleware(HTTPMiddlewareOptions{EmitMetrics: true})(failing)
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	if n := sink.Count(labels); n != 2 {
+		t.Errorf("metric count = %d, want 2", n)
+	}
+}
+
+func TestHTTPMiddleware_RecoversPanic(t *testing.T) {
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceIDHeader, "abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	resp := decodeErrorResponse(t, rec)
+	if resp.Code != CodeInternal || resp.TraceID != "abc" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestWriteBatch(t *testing.T) {
+	b := NewBatchResult(2)
+	b.Success(0, "a")
+	b.Fail(1, "b", New(CodeInvalidInput, "无效"))
+
+	rec := httptest.NewRecorder()
+	WriteBatch(rec, b)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207", rec.Code)
+	}
+	var body struct {
+		Summary BatchSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Summary.Failed != 1 || body.Summary.Succeeded != 1 {
+		t.Errorf("unexpected summary: %+v", body.Summary)
+	}
+}
+
+func TestWriteBatch_HidesInternalDetails(t *testing.T) {
+	b := NewBatchResult(2)
+	b.Fail(0, "a", errors.New("pq: connection refused"))
+	b.Fail(1, "b", WrapWithType(errors.New("pq: timeout"), DatabaseError).
+		WithDetails("host=10.0.0.1").WithContext("query", "SELECT 1"))
+
+	rec := httptest.NewRecorder()
+	WriteBatch(rec, b)
+	if body := rec.Body.String(); strings.Contains(body, "pq:") || strings.Contains(body, "10.0.0.1") || strings.Contains(body, "SELECT") {
+		t.Errorf("5xx batch items leak internals: %s", body)
+	}
+	var body struct {
+		Items []struct {
+			Error ErrorResponse `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 || body.Items[0].Error.Code != CodeInternal || body.Items[1].Error.Code != CodeDatabaseError {
+		t.Errorf("unexpected items: %+v", body.Items)
+	}
+}
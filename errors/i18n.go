@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 常用语言标识
+const (
+	LocaleZH = "zh"
+	LocaleEN = "en"
+)
+
+// DefaultLocale Message 字段对应的默认语言
+var DefaultLocale = LocaleZH
+
+// WithLocalizedMessage 为指定语言设置错误消息，语言标识不区分大小写
+func (e *Error) WithLocalizedMessage(locale, message string) *Error {
+	if e.Localized == nil {
+		e.Localized = make(map[string]string)
+	}
+	e.Localized[normalizeLocale(locale)] = message
+	return e
+}
+
+// WithMessages 同时设置中英文消息，Message 使用 DefaultLocale 对应的文本
+func (e *Error) WithMessages(zh, en string) *Error {
+	e.WithLocalizedMessage(LocaleZH, zh).WithLocalizedMessage(LocaleEN, en)
+	if DefaultLocale == LocaleEN {
+		e.Message = en
+	} else {
+		e.Message = zh
+	}
+	return e
+}
+
+// LocalizedMessage 返回指定语言的消息
+// 查找顺序：完整标识（zh-cn）→ 主语言（zh）→ Message
+func (e *Error) LocalizedMessage(locale string) string {
+	if msg, ok := e.lookupLocale(locale); ok {
+		return msg
+	}
+	return e.Message
+}
+
+// MessageFor 根据 Accept-Language 请求头选择最合适的消息
+func (e *Error) MessageFor(acceptLanguage string) string {
+	for _, locale := range ParseAcceptLanguage(acceptLanguage) {
+		if msg, ok := e.lookupLocale(locale); ok {
+			return msg
+		}
+		// Message 本身就是默认语言的文本
+		if primaryLanguage(locale) == DefaultLocale {
+			return e.Message
+		}
+	}
+	return e.Message
+}
+
+// lookupLocale 在 Localized 中查找语言对应的消息
+func (e *Error) lookupLocale(locale string) (string, bool) {
+	if len(e.Localized) == 0 || locale == "" {
+		return "", false
+	}
+	locale = normalizeLocale(locale)
+	if msg, ok := e.Localized[locale]; ok && msg != "" {
+		return msg, true
+	}
+	if msg, ok := e.Localized[primaryLanguage(locale)]; ok && msg != "" {
+		return msg, true
+	}
+	return "", false
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，按权重从高到低返回语言标识
+// 例如 "en-US,en;q=0.9,zh;q=0.8" 返回 ["en-us", "en", "zh"]
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		locale, q := part, 1.0
+		if idx := strings.Index(part, ";"); idx >= 0 {
+			locale = strings.TrimSpace(part[:idx])
+			param := strings.TrimSpace(part[idx+1:])
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if locale == "" || locale == "*" || q <= 0 {
+			continue
+		}
+		items = append(items, weighted{normalizeLocale(locale), q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	locales := make([]string, len(items))
+	for i, item := range items {
+		locales[i] = item.locale
+	}
+	return locales
+}
+
+// NegotiateLocale 从 Accept-Language 中选出 supported 支持的语言，没有匹配时返回 DefaultLocale
+func NegotiateLocale(acceptLanguage string, supported ...string) string {
+	for _, locale := range ParseAcceptLanguage(acceptLanguage) {
+		for _, s := range supported {
+			s = normalizeLocale(s)
+			if s == locale || s == primaryLanguage(locale) {
+				return s
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// normalizeLocale 统一语言标识格式：小写并使用 "-" 分隔
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// primaryLanguage 返回主语言部分，如 zh-cn 返回 zh
+func primaryLanguage(locale string) string {
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		return locale[:idx]
+	}
+	return locale
+}
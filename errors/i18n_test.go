@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"zh-CN", []string{"zh-cn"}},
+		{"en-US,en;q=0.9,zh;q=0.8", []string{"en-us", "en", "zh"}},
+		{"zh;q=0.5, en;q=0.9, *;q=0.1", []string{"en", "zh"}},
+		{"fr;q=0, en", []string{"en"}},
+	}
+	for _, tt := range tests {
+		got := ParseAcceptLanguage(tt.header)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestError_MessageFor(t *testing.T) {
+	err := New("USER001", "用户不存在").WithLocalizedMessage("en", "User not found")
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"en-US,en;q=0.9", "User not found"},
+		{"zh-CN,zh;q=0.9,en;q=0.8", "用户不存在"},
+		{"fr-FR", "用户不存在"},
+		{"", "用户不存在"},
+	}
+	for _, tt := range tests {
+		if got := err.MessageFor(tt.header); got != tt.want {
+			t.Errorf("MessageFor(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+
+	if got := err.LocalizedMessage("EN_gb"); got != "User not found" {
+		t.Errorf("LocalizedMessage(EN_gb) = %q", got)
+	}
+}
+
+func TestFromType_Localized(t *testing.T) {
+	err := FromType(NotFoundError)
+	if got := err.LocalizedMessage(LocaleEN); got != NotFoundError.MessageEN {
+		t.Errorf("LocalizedMessage(en) = %q, want %q", got, NotFoundError.MessageEN)
+	}
+	if got := err.LocalizedMessage(LocaleZH); got != NotFoundError.Message {
+		t.Errorf("LocalizedMessage(zh) = %q, want %q", got, NotFoundError.Message)
+	}
+
+	cloned := Clone(err)
+	cloned.WithLocalizedMessage(LocaleEN, "changed")
+	if err.LocalizedMessage(LocaleEN) == "changed" {
+		t.Error("Clone should deep copy Localized")
+	}
+
+	data, _ := json.Marshal(err)
+	if !strings.Contains(string(data), `"localized"`) {
+		t.Errorf("expected localized messages in JSON: %s", data)
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	if got := NegotiateLocale("en-US,zh;q=0.5", LocaleZH, LocaleEN); got != LocaleEN {
+		t.Errorf("NegotiateLocale = %q, want en", got)
+	}
+	if got := NegotiateLocale("ja-JP", LocaleZH, LocaleEN); got != DefaultLocale {
+		t.Errorf("NegotiateLocale = %q, want default", got)
+	}
+}
+
+func TestBuilder_WithMessages(t *testing.T) {
+	err := NewBuilder().Code("ORDER001").LocalizedMessage("en", "Order closed").Build()
+	if err.LocalizedMessage("en") != "Order closed" {
+		t.Errorf("builder LocalizedMessage not applied")
+	}
+
+	err = New("ORDER002", "").WithMessages("库存不足", "Insufficient stock")
+	if err.Message != "库存不足" || err.MessageFor("en") != "Insufficient stock" {
+		t.Errorf("WithMessages = %q / %q", err.Message, err.MessageFor("en"))
+	}
+}
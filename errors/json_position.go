@@ -0,0 +1,176 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Position 值在 JSON 文本中的位置
+type Position struct {
+	// Offset 值起始处的字节偏移（从 0 开始）
+	Offset int64 `json:"offset"`
+	// Line 行号（从 1 开始）
+	Line int `json:"line"`
+	// Column 列号（从 1 开始，按字符计算，便于编辑器定位）
+	Column int `json:"column"`
+}
+
+// String 返回 "line:column"
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// JSONPositions JSON 文本中各字段值的位置，键与结构体校验的字段名格式一致，如 name、address.city、items[0].sku
+type JSONPositions map[string]Position
+
+// IndexJSONPositions 扫描 JSON 文本，记录每个对象字段与数组元素的值的起始位置
+func IndexJSONPositions(data []byte) (JSONPositions, error) {
+	idx := &positionIndexer{
+		data:      data,
+		dec:       json.NewDecoder(bytes.NewReader(data)),
+		positions: make(JSONPositions),
+	}
+	if err := idx.value(""); err != nil {
+		return nil, err
+	}
+	return idx.positions, nil
+}
+
+// positionIndexer 基于 json.Decoder 的令牌流记录位置
+type positionIndexer struct {
+	data      []byte
+	dec       *json.Decoder
+	positions JSONPositions
+}
+
+// value 读取一个值并记录其位置，对象与数组递归处理
+func (idx *positionIndexer) value(path string) error {
+	if path != "" {
+		idx.positions[path] = positionAt(idx.data, idx.valueStart())
+	}
+
+	tok, err := idx.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for idx.dec.More() {
+			key, err := idx.dec.Token()
+			if err != nil {
+				return err
+			}
+			name := key.(string)
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := idx.value(name); err != nil {
+				return err
+			}
+		}
+		_, err = idx.dec.Token()
+	case json.Delim('['):
+		for i := 0; idx.dec.More(); i++ {
+			if err := idx.value(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		_, err = idx.dec.Token()
+	}
+	return err
+}
+
+// valueStart 返回下一个值的起始偏移：跳过上一个令牌之后的空白、冒号与逗号
+func (idx *positionIndexer) valueStart() int64 {
+	offset := idx.dec.InputOffset()
+	for ; offset < int64(len(idx.data)); offset++ {
+		switch idx.data[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// positionAt 把字节偏移换算为行列号
+func positionAt(data []byte, offset int64) Position {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return Position{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
+
+// WithPositions 为已有的校验错误补充位置，字段名在 positions 中不存在时保持不变
+func (v *Validator) WithPositions(positions JSONPositions) *Validator {
+	for _, err := range v.errors {
+		if err.Position != nil {
+			continue
+		}
+		if pos, ok := positions[err.Field]; ok {
+			err.WithPosition(pos)
+		}
+	}
+	return v
+}
+
+// ValidateJSON 把 JSON 请求体解码到 dst 并按 `validate` 标签校验，每个错误都带有字段在请求体中的位置
+//
+//	var req CreateUserRequest
+//	if v := errors.ValidateJSON(body, &req); v.HasErrors() {
+//		return v.GetError() // error_0.position = {offset: 12, line: 2, column: 11}
+//	}
+//
+// JSON 语法错误返回规则为 json 的错误，类型不匹配返回规则为 type 的错误，两者都只有一个错误且不再执行标签校验。
+// 嵌套字段的位置按 json 键名匹配，键名大小写与 json 标签不一致时该字段没有位置信息。
+func ValidateJSON(data []byte, dst interface{}) *Validator {
+	v := NewValidator()
+	if err := json.Unmarshal(data, dst); err != nil {
+		v.AddError(jsonDecodeError(data, err))
+		return v
+	}
+
+	v.Struct(dst)
+	if !v.HasErrors() {
+		return v
+	}
+	positions, err := IndexJSONPositions(data)
+	if err != nil {
+		// 能被 Unmarshal 解码的文本不会走到这里
+		return v
+	}
+	return v.WithPositions(positions)
+}
+
+// jsonDecodeError 把解码错误转换为带位置的校验错误
+func jsonDecodeError(data []byte, err error) *ValidationError {
+	var syntaxErr *json.SyntaxError
+	if stderrors.As(err, &syntaxErr) {
+		return NewValidationError("", "json", "Request body is not valid JSON: "+syntaxErr.Error(), nil).
+			WithPosition(positionAt(data, syntaxErr.Offset))
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) {
+		ve := NewValidationError(typeErr.Field, "type",
+			fmt.Sprintf("Field '%s' must be of type %s", typeErr.Field, typeErr.Type), typeErr.Value).
+			WithParams(map[string]interface{}{"type": typeErr.Type.String()})
+		// Offset 指向出错值的末尾，能定位到字段时使用值的起始位置
+		if positions, indexErr := IndexJSONPositions(data); indexErr == nil {
+			if pos, ok := positions[typeErr.Field]; ok {
+				return ve.WithPosition(pos)
+			}
+		}
+		return ve.WithPosition(positionAt(data, typeErr.Offset))
+	}
+
+	return NewValidationError("", "json", "Request body is not valid JSON: "+err.Error(), nil)
+}
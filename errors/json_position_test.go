@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"testing"
+)
+
+func TestIndexJSONPositions(t *testing.T) {
+	body := []byte("{\n  \"name\": \"张三\",\n  \"address\": {\"city\": \"\"},\n  \"items\": [\n    {\"sku\": \"a\"},\n    {\"sku\" : 1}\n  ]\n}")
+
+	positions, err := IndexJSONPositions(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		pos   string
+		first byte
+	}{
+		"name":         {"2:11", '"'},
+		"address":      {"3:14", '{'},
+		"address.city": {"3:23", '"'},
+		"items":        {"4:12", '['},
+		"items[0].sku": {"5:13", '"'},
+		"items[1].sku": {"6:14", '1'},
+	}
+	for path, want := range tests {
+		got, ok := positions[path]
+		if !ok || got.String() != want.pos {
+			t.Errorf("positions[%q] = %s, want %s", path, got, want.pos)
+			continue
+		}
+		if body[got.Offset] != want.first {
+			t.Errorf("offset of %s points at %q, want %q", path, body[got.Offset], want.first)
+		}
+	}
+
+	if _, err := IndexJSONPositions([]byte(`{"a":`)); err == nil {
+		t.Error("expected error for truncated JSON")
+	}
+}
+
+func TestValidateJSON_Positions(t *testing.T) {
+	body := []byte(`{
+  "name": "ab",
+  "email": "alice@example.com",
+  "role": "admin",
+  "address": {"city": ""},
+  "items": [{"sku": "x", "quantity": 0}]
+}`)
+
+	var req testCreateUser
+	v := ValidateJSON(body, &req)
+	if !v.HasErrors() {
+		t.Fatal("expected validation errors")
+	}
+
+	want := map[string]string{
+		"name":              "2:11",
+		"address.city":      "5:23",
+		"items[0].quantity": "6:38",
+	}
+	for _, err := range v.GetErrors() {
+		pos, ok := want[err.Field]
+		if !ok {
+			t.Errorf("unexpected error on %s: %s", err.Field, err.Message)
+			continue
+		}
+		if err.Position == nil {
+			t.Errorf("%s has no position", err.Field)
+			continue
+		}
+		if err.Position.String() != pos {
+			t.Errorf("%s position = %s, want %s", err.Field, err.Position, pos)
+		}
+		if _, ok := err.Context["position"]; !ok {
+			t.Errorf("%s position missing from context", err.Field)
+		}
+		delete(want, err.Field)
+	}
+	for field := range want {
+		t.Errorf("missing error on %s", field)
+	}
+
+	combined := v.GetError()
+	entry, _ := combined.Context["error_0"].(map[string]interface{})
+	if _, ok := entry["position"]; !ok {
+		t.Errorf("combined error entry has no position: %v", entry)
+	}
+}
+
+func TestValidateJSON_DecodeErrors(t *testing.T) {
+	var req testCreateUser
+
+	v := ValidateJSON([]byte("{\n  \"name\": \"abc\",\n  oops\n}"), &req)
+	errs := v.GetErrors()
+	if len(errs) != 1 || errs[0].Rule != "json" {
+		t.Fatalf("syntax error = %+v", errs)
+	}
+	if errs[0].Position == nil || errs[0].Position.Line != 3 {
+		t.Errorf("syntax error position = %+v, want line 3", errs[0].Position)
+	}
+
+	v = ValidateJSON([]byte("{\n  \"name\": \"abc\",\n  \"age\": \"old\"\n}"), &req)
+	errs = v.GetErrors()
+	if len(errs) != 1 || errs[0].Rule != "type" || errs[0].Field != "age" {
+		t.Fatalf("type error = %+v", errs)
+	}
+	if errs[0].Position == nil || errs[0].Position.String() != "3:10" {
+		t.Errorf("type error position = %+v, want 3:10", errs[0].Position)
+	}
+}
+
+func TestValidator_WithPositions(t *testing.T) {
+	v := NewValidator().Required("name", "").Required("missing", "")
+	v.WithPositions(JSONPositions{"name": {Offset: 9, Line: 1, Column: 10}})
+
+	errs := v.GetErrors()
+	if errs[0].Position == nil || errs[0].Position.Offset != 9 {
+		t.Errorf("name position = %+v", errs[0].Position)
+	}
+	if errs[1].Position != nil {
+		t.Errorf("missing field should have no position, got %+v", errs[1].Position)
+	}
+}
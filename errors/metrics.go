@@ -0,0 +1,137 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strconv"
+	"sync"
+)
+
+// MetricLabels 错误指标的标签集合
+type MetricLabels struct {
+	Code     string   // 错误码
+	Category Category // 错误类别
+	Severity Severity // 严重级别
+}
+
+// MetricLabelNames 指标标签名，顺序与 MetricLabels.Values 一致
+// 可直接用于创建 Prometheus CounterVec:
+//
+//	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "app_errors_total"}, errors.MetricLabelNames)
+var MetricLabelNames = []string{"code", "category", "severity"}
+
+// Values 按 MetricLabelNames 的顺序返回标签值
+func (l MetricLabels) Values() []string {
+	return []string{l.Code, string(l.Category), string(l.Severity)}
+}
+
+// MetricsSink 错误指标接收器，每次 EmitMetric 调用时计数加一
+type MetricsSink interface {
+	IncError(labels MetricLabels)
+}
+
+// MetricsSinkFunc 函数适配器，用于对接 Prometheus 等计数器实现
+//
+//	errors.SetMetricsSink(errors.MetricsSinkFunc(func(l errors.MetricLabels) {
+//		vec.WithLabelValues(l.Values()...).Inc()
+//	}))
+type MetricsSinkFunc func(labels MetricLabels)
+
+// IncError 实现 MetricsSink 接口
+func (f MetricsSinkFunc) IncError(labels MetricLabels) {
+	f(labels)
+}
+
+var (
+	metricsSinkMu sync.RWMutex
+	metricsSink   MetricsSink
+)
+
+// SetMetricsSink 设置全局指标接收器，传入 nil 关闭指标上报
+func SetMetricsSink(sink MetricsSink) {
+	metricsSinkMu.Lock()
+	metricsSink = sink
+	metricsSinkMu.Unlock()
+}
+
+// EmitMetric 将错误按错误码、类别、严重级别计入全局指标接收器
+// 未设置接收器、err 为 nil 或错误处于抑制窗口内时不做任何事
+func EmitMetric(err error) {
+	if err == nil || IsSuppressed(err) {
+		return
+	}
+
+	metricsSinkMu.RLock()
+	sink := metricsSink
+	metricsSinkMu.RUnlock()
+
+	if sink != nil {
+		sink.IncError(LabelsOf(err))
+	}
+}
+
+// LabelsOf 提取错误的指标标签，沿错误链查找 *Error 或 RichError，两者都存在时取最外层的一个
+// RichError 使用数字业务码作为 code，并按 4xx/5xx 推导严重级别
+func LabelsOf(err error) MetricLabels {
+	var rich *RichError
+	var appErr *Error
+	hasRich := stderrors.As(err, &rich)
+	hasErr := stderrors.As(err, &appErr)
+
+	if hasRich && (!hasErr || stderrors.Is(rich, appErr)) {
+		severity := SeverityMedium
+		if IsServerError(rich) {
+			severity = SeverityHigh
+		}
+		return MetricLabels{
+			Code:     strconv.Itoa(rich.Code),
+			Category: CategorySystem,
+			Severity: severity,
+		}
+	}
+	if hasErr {
+		err = appErr
+	}
+
+	return MetricLabels{
+		Code:     GetCode(err),
+		Category: GetCategory(err),
+		Severity: GetSeverity(err),
+	}
+}
+
+// CounterSink 基于内存的指标接收器，适用于测试或简单的进程内统计
+type CounterSink struct {
+	mu     sync.Mutex
+	counts map[MetricLabels]int64
+}
+
+// NewCounterSink 创建内存计数接收器
+func NewCounterSink() *CounterSink {
+	return &CounterSink{counts: make(map[MetricLabels]int64)}
+}
+
+// IncError 实现 MetricsSink 接口
+func (s *CounterSink) IncError(labels MetricLabels) {
+	s.mu.Lock()
+	s.counts[labels]++
+	s.mu.Unlock()
+}
+
+// Count 返回指定标签组合的计数
+func (s *CounterSink) Count(labels MetricLabels) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[labels]
+}
+
+// Snapshot 返回当前所有计数的副本
+func (s *CounterSink) Snapshot() map[MetricLabels]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[MetricLabels]int64, len(s.counts))
+	for k, v := range s.counts {
+		result[k] = v
+	}
+	return result
+}
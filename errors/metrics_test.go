@@ -0,0 +1,72 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEmitMetric(t *testing.T) {
+	sink := NewCounterSink()
+	SetMetricsSink(sink)
+	defer SetMetricsSink(nil)
+
+	EmitMetric(FromType(DatabaseError))
+	EmitMetric(FromType(DatabaseError))
+	EmitMetric(NewRich(RichCodeNotFound, "用户不存在"))
+	EmitMetric(errors.New("plain"))
+	EmitMetric(nil)
+
+	dbLabels := MetricLabels{Code: CodeDatabaseError, Category: CategoryDatabase, Severity: SeverityHigh}
+	if got := sink.Count(dbLabels); got != 2 {
+		t.Errorf("database error count = %d, want 2", got)
+	}
+
+	richLabels := MetricLabels{Code: "404000", Category: CategorySystem, Severity: SeverityMedium}
+	if got := sink.Count(richLabels); got != 1 {
+		t.Errorf("rich error count = %d, want 1", got)
+	}
+
+	if len(sink.Snapshot()) != 3 {
+		t.Errorf("expected 3 label combinations, got %d", len(sink.Snapshot()))
+	}
+}
+
+func TestMetricsSinkFunc(t *testing.T) {
+	var got []string
+	SetMetricsSink(MetricsSinkFunc(func(l MetricLabels) {
+		got = l.Values()
+	}))
+	defer SetMetricsSink(nil)
+
+	EmitMetric(Internal("boom"))
+
+	if len(got) != len(MetricLabelNames) {
+		t.Fatalf("expected %d label values, got %v", len(MetricLabelNames), got)
+	}
+	if got[0] != CodeInternal || got[1] != string(CategorySystem) || got[2] != string(SeverityCritical) {
+		t.Errorf("unexpected label values: %v", got)
+	}
+}
+
+func TestLabelsOf_Wrapped(t *testing.T) {
+	dbLabels := MetricLabels{Code: CodeDatabaseError, Category: CategoryDatabase, Severity: SeverityHigh}
+	if got := LabelsOf(fmt.Errorf("load user: %w", FromType(DatabaseError))); got != dbLabels {
+		t.Errorf("LabelsOf(wrapped *Error) = %+v, want %+v", got, dbLabels)
+	}
+
+	richLabels := MetricLabels{Code: "404000", Category: CategorySystem, Severity: SeverityMedium}
+	if got := LabelsOf(fmt.Errorf("handler: %w", NewRich(RichCodeNotFound, "不存在"))); got != richLabels {
+		t.Errorf("LabelsOf(wrapped RichError) = %+v, want %+v", got, richLabels)
+	}
+
+	// 两者都存在时取最外层
+	outer := Wrap(NewRich(RichCodeNotFound, "不存在"), "USER_LOAD_FAILED", "加载用户失败")
+	if got := LabelsOf(outer).Code; got != "USER_LOAD_FAILED" {
+		t.Errorf("LabelsOf(*Error wrapping RichError).Code = %s", got)
+	}
+	inner := WrapRich(FromType(DatabaseError), RichCodeInternal, "db")
+	if got := LabelsOf(inner).Code; got != fmt.Sprint(RichCodeInternal) {
+		t.Errorf("LabelsOf(RichError wrapping *Error).Code = %s", got)
+	}
+}
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+)
+
+// DefaultSlowOperationThreshold 默认的慢操作阈值
+const DefaultSlowOperationThreshold = time.Second
+
+// OperationStats 一次 Do 调用的结果
+type OperationStats struct {
+	Name     string        // 操作名，如 "user.load"
+	Duration time.Duration // 耗时
+	Err      error         // 包装后的错误，成功时为 nil
+	Slow     bool          // 耗时是否超过慢操作阈值
+}
+
+// OperationObserver 操作结果观察者，每次 Do 调用结束后调用一次，用于记录耗时直方图等指标
+type OperationObserver interface {
+	ObserveOperation(ctx context.Context, stats OperationStats)
+}
+
+// OperationObserverFunc 函数适配器，用于对接 Prometheus 等指标实现
+//
+//	errors.SetOperationObserver(errors.OperationObserverFunc(func(ctx context.Context, s errors.OperationStats) {
+//		histogram.WithLabelValues(s.Name, strconv.FormatBool(s.Err == nil)).Observe(s.Duration.Seconds())
+//	}))
+type OperationObserverFunc func(ctx context.Context, stats OperationStats)
+
+// ObserveOperation 实现 OperationObserver 接口
+func (f OperationObserverFunc) ObserveOperation(ctx context.Context, stats OperationStats) {
+	f(ctx, stats)
+}
+
+var (
+	operationMu       sync.RWMutex
+	operationObserver OperationObserver
+	slowOperationAt   = DefaultSlowOperationThreshold
+	operationLogger   *slog.Logger
+)
+
+// SetOperationObserver 设置全局操作观察者，传入 nil 关闭
+func SetOperationObserver(observer OperationObserver) {
+	operationMu.Lock()
+	operationObserver = observer
+	operationMu.Unlock()
+}
+
+// SetSlowOperationThreshold 设置慢操作阈值，耗时超过阈值的操作以 Warn 级别记录日志；0 表示不记录
+func SetSlowOperationThreshold(threshold time.Duration) {
+	operationMu.Lock()
+	slowOperationAt = threshold
+	operationMu.Unlock()
+}
+
+// SetOperationLogger 设置记录慢操作的 logger，传入 nil 时使用 slog.Default()
+func SetOperationLogger(logger *slog.Logger) {
+	operationMu.Lock()
+	operationLogger = logger
+	operationMu.Unlock()
+}
+
+// Do 执行一次具名操作：计时、包装错误、上报指标并记录慢操作
+//
+// fn 返回的错误被包装为 *Error，错误链中保留原始错误，上下文中带有 "operation" 与 "duration_ms"。
+// 原始错误是 *Error 时沿用其错误码、消息、严重级别与类别；context 取消映射为 CodeCanceled，超时映射为 CodeTimeout，其他错误为 CodeInternal。
+// 失败时调用 EmitMetric 计数，无论成功与否都会通知 OperationObserver。
+//
+//	err := errors.Do(ctx, "user.load", func(ctx context.Context) error {
+//		return repo.Load(ctx, id)
+//	})
+func Do(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	return finishOperation(ctx, name, time.Since(start), err)
+}
+
+// DoValue 与 Do 相同，用于有返回值的操作；失败时返回 T 的零值
+func DoValue[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	value, err := fn(ctx)
+	if err = finishOperation(ctx, name, time.Since(start), err); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// finishOperation 包装错误并上报结果
+func finishOperation(ctx context.Context, name string, duration time.Duration, err error) error {
+	if err != nil {
+		err = wrapOperationError(name, duration, err)
+		EmitMetric(err)
+	}
+
+	operationMu.RLock()
+	observer, threshold, logger := operationObserver, slowOperationAt, operationLogger
+	operationMu.RUnlock()
+
+	stats := OperationStats{Name: name, Duration: duration, Err: err, Slow: threshold > 0 && duration > threshold}
+	if stats.Slow {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		attrs := []slog.Attr{
+			slog.String("operation", name),
+			slog.Duration("duration", duration),
+			slog.Duration("threshold", threshold),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error_code", GetCode(err)), slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(ctx, slog.LevelWarn, "slow operation", attrs...)
+	}
+	if observer != nil {
+		observer.ObserveOperation(ctx, stats)
+	}
+	return err
+}
+
+// wrapOperationError 以操作名和耗时包装错误
+func wrapOperationError(name string, duration time.Duration, err error) *Error {
+	var wrapped *Error
+	var appErr *Error
+	switch {
+	case stderrors.As(err, &appErr):
+		wrapped = &Error{
+			Code:      appErr.Code,
+			Message:   appErr.Message,
+			Timestamp: time.Now(),
+			Context:   make(map[string]interface{}, len(appErr.Context)+2),
+			Original:  err,
+			Localized: maps.Clone(appErr.Localized),
+		}
+		maps.Copy(wrapped.Context, appErr.Context)
+	default:
+		wrapped = wrapPlainError(err)
+	}
+
+	wrapped.Details = name + ": " + err.Error()
+	wrapped.Context["operation"] = name
+	wrapped.Context["duration_ms"] = duration.Milliseconds()
+	return reportCreated(wrapped)
+}
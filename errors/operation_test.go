@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDo_WrapsErrors(t *testing.T) {
+	sink := NewCounterSink()
+	SetMetricsSink(sink)
+	defer SetMetricsSink(nil)
+
+	var observed []OperationStats
+	SetOperationObserver(OperationObserverFunc(func(_ context.Context, s OperationStats) {
+		observed = append(observed, s)
+	}))
+	defer SetOperationObserver(nil)
+
+	ctx := context.Background()
+	if err := Do(ctx, "user.ping", func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+
+	cause := FromType(DatabaseError).WithContext("table", "users")
+	err := Do(ctx, "user.load", func(context.Context) error { return cause })
+
+	var appErr *Error
+	if !errors.As(err, &appErr) || appErr == cause {
+		t.Fatalf("expected a new *Error wrapping the cause, got %T", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("wrapped error should keep the original in its chain")
+	}
+	if appErr.Code != CodeDatabaseError || GetCategory(err) != CategoryDatabase || GetSeverity(err) != SeverityHigh {
+		t.Errorf("code/category/severity not inherited: %s %s %s", appErr.Code, GetCategory(err), GetSeverity(err))
+	}
+	if op, _ := GetContext(err, "operation"); op != "user.load" {
+		t.Errorf("operation = %v", op)
+	}
+	if _, ok := GetContext(err, "duration_ms"); !ok {
+		t.Error("missing duration_ms")
+	}
+	if table, _ := GetContext(err, "table"); table != "users" {
+		t.Errorf("original context lost: table = %v", table)
+	}
+	if _, ok := cause.Context["operation"]; ok {
+		t.Error("Do must not modify the original error")
+	}
+	if !strings.Contains(err.Error(), "user.load") {
+		t.Errorf("error text should contain the operation name: %s", err)
+	}
+
+	dbLabels := MetricLabels{Code: CodeDatabaseError, Category: CategoryDatabase, Severity: SeverityHigh}
+	if got := sink.Count(dbLabels); got != 1 {
+		t.Errorf("metric count = %d, want 1", got)
+	}
+	if len(observed) != 2 || observed[0].Err != nil || observed[1].Err != err || observed[1].Name != "user.load" {
+		t.Errorf("observed = %+v", observed)
+	}
+}
+
+func TestDo_PlainErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := Do(ctx, "remote.call", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if GetCode(err) != CodeTimeout || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout: code = %s, err = %v", GetCode(err), err)
+	}
+
+	// 调用方取消不是服务端故障
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	err = Do(canceled, "remote.call", func(ctx context.Context) error { return ctx.Err() })
+	if GetCode(err) != CodeCanceled || GetSeverity(err) != SeverityLow || HTTPStatus(err) != 499 {
+		t.Errorf("canceled: code = %s, severity = %s, status = %d", GetCode(err), GetSeverity(err), HTTPStatus(err))
+	}
+	if GetSeverity(Do(ctx, "remote.call", func(ctx context.Context) error { return ctx.Err() })) == SeverityCritical {
+		t.Error("timeout should not be critical")
+	}
+
+	plain := errors.New("boom")
+	err = Do(context.Background(), "job.run", func(context.Context) error { return plain })
+	if GetCode(err) != CodeInternal || !errors.Is(err, plain) {
+		t.Errorf("plain: code = %s, err = %v", GetCode(err), err)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	got, err := DoValue(context.Background(), "calc", func(context.Context) (int, error) { return 42, nil })
+	if got != 42 || err != nil {
+		t.Errorf("DoValue() = %d, %v", got, err)
+	}
+
+	got, err = DoValue(context.Background(), "calc", func(context.Context) (int, error) {
+		return 7, errors.New("partial")
+	})
+	if got != 0 || GetCode(err) != CodeInternal {
+		t.Errorf("DoValue() on error = %d, %v; want zero value", got, err)
+	}
+}
+
+func TestDo_SlowOperationLog(t *testing.T) {
+	var buf bytes.Buffer
+	SetOperationLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer SetOperationLogger(nil)
+	SetSlowOperationThreshold(5 * time.Millisecond)
+	defer SetSlowOperationThreshold(DefaultSlowOperationThreshold)
+
+	var slow bool
+	SetOperationObserver(OperationObserverFunc(func(_ context.Context, s OperationStats) { slow = s.Slow }))
+	defer SetOperationObserver(nil)
+
+	_ = Do(context.Background(), "fast.op", func(context.Context) error { return nil })
+	if buf.Len() != 0 || slow {
+		t.Errorf("fast operation should not be logged: %s", buf.String())
+	}
+
+	_ = Do(context.Background(), "slow.op", func(context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if !slow || !strings.Contains(buf.String(), "slow operation") || !strings.Contains(buf.String(), "operation=slow.op") {
+		t.Errorf("slow operation not logged: %s", buf.String())
+	}
+
+	buf.Reset()
+	SetSlowOperationThreshold(0)
+	_ = Do(context.Background(), "slow.op", func(context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if buf.Len() != 0 {
+		t.Errorf("threshold 0 should disable logging: %s", buf.String())
+	}
+}
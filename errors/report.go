@@ -0,0 +1,265 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultReportBufferSize AsyncReporter 默认的缓冲区大小
+const DefaultReportBufferSize = 1024
+
+// Reporter 错误上报接口，用于对接 Sentry 等错误追踪服务
+type Reporter interface {
+	Report(ctx context.Context, err *Error)
+}
+
+// ReporterFunc 函数适配器
+type ReporterFunc func(ctx context.Context, err *Error)
+
+// Report 实现 Reporter 接口
+func (f ReporterFunc) Report(ctx context.Context, err *Error) {
+	f(ctx, err)
+}
+
+// severityRanks 严重级别的排序，未知级别为 0
+var severityRanks = map[Severity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// ReportHooks 上报钩子链：严重级别不低于阈值的错误依次交给各个 Reporter
+// 可以单独创建实例显式调用 Report，也可以通过 SetReportHooks 设为全局钩子。
+// 全局钩子在错误被创建或包装时（New、Wrap、FromType、WrapWithType、Builder.Build 等）自动触发，
+// WriteError 与 Report 会再检查一次，构造后才设置严重级别的错误在此时上报；同一错误链只上报一次。
+type ReportHooks struct {
+	mu          sync.RWMutex
+	reporters   []Reporter
+	minSeverity Severity
+}
+
+// NewReportHooks 创建上报钩子链，minSeverity 为空时使用 SeverityHigh
+func NewReportHooks(minSeverity Severity, reporters ...Reporter) *ReportHooks {
+	if minSeverity == "" {
+		minSeverity = SeverityHigh
+	}
+	return &ReportHooks{reporters: reporters, minSeverity: minSeverity}
+}
+
+// Add 追加 Reporter，按添加顺序调用
+func (h *ReportHooks) Add(reporters ...Reporter) {
+	h.mu.Lock()
+	h.reporters = append(h.reporters, reporters...)
+	h.mu.Unlock()
+}
+
+// SetMinSeverity 设置触发上报的最低严重级别
+func (h *ReportHooks) SetMinSeverity(severity Severity) {
+	h.mu.Lock()
+	h.minSeverity = severity
+	h.mu.Unlock()
+}
+
+// Report 上报错误：取错误链中最外层严重级别达到阈值的 *Error，不在抑制窗口内时调用各个 Reporter
+// 错误链中已有错误被本钩子链上报过时跳过，避免包装或写入响应时重复上报；
+// Reporter 的 panic 会被恢复并记录日志，不会影响调用方
+func (h *ReportHooks) Report(ctx context.Context, err error) {
+	if h == nil || err == nil {
+		return
+	}
+
+	h.mu.RLock()
+	reporters, minSeverity := h.reporters, h.minSeverity
+	h.mu.RUnlock()
+
+	if len(reporters) == 0 {
+		return
+	}
+	target := h.reportTarget(err, minSeverity)
+	if target == nil || IsSuppressed(target) || !target.reportedBy.CompareAndSwap(nil, h) {
+		return
+	}
+	for _, r := range reporters {
+		callReporter(ctx, r, target)
+	}
+}
+
+// reportTarget 沿错误链查找需要上报的 *Error，链中已有错误被 h 上报过时返回 nil
+func (h *ReportHooks) reportTarget(err error, minSeverity Severity) *Error {
+	var target *Error
+	for cur := err; cur != nil; cur = stderrors.Unwrap(cur) {
+		appErr, ok := cur.(*Error)
+		if !ok {
+			continue
+		}
+		if appErr.reportedBy.Load() == h {
+			return nil
+		}
+		if target == nil && severityRanks[reportSeverity(appErr)] >= severityRanks[minSeverity] {
+			target = appErr
+		}
+	}
+	return target
+}
+
+// reportSeverity 返回用于上报判断的严重级别，上下文中未设置时使用已注册错误类型的级别
+func reportSeverity(err *Error) Severity {
+	if severity, ok := err.Context["severity"].(Severity); ok {
+		return severity
+	}
+	if t, ok := LookupErrorType(err.Code); ok && t.Severity != "" {
+		return t.Severity
+	}
+	return SeverityLow
+}
+
+// callReporter 调用单个 Reporter 并恢复其 panic
+func callReporter(ctx context.Context, r Reporter, err *Error) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Default().ErrorContext(ctx, "error reporter panicked",
+				slog.String("code", err.Code), slog.Any("panic", p))
+		}
+	}()
+	r.Report(ctx, err)
+}
+
+// globalReportHooks 全局上报钩子，未设置时为 nil
+var globalReportHooks atomic.Pointer[ReportHooks]
+
+// SetReportHooks 设置全局上报钩子，传入 nil 关闭自动上报
+// 注意 Reporter 内部不要再创建达到阈值的错误，否则会递归触发上报
+func SetReportHooks(hooks *ReportHooks) {
+	globalReportHooks.Store(hooks)
+}
+
+// Report 通过全局钩子上报错误，用于携带请求上下文显式上报；未设置全局钩子时不做任何事
+func Report(ctx context.Context, err error) {
+	if hooks := globalReportHooks.Load(); hooks != nil {
+		hooks.Report(ctx, err)
+	}
+}
+
+// reportCreated 错误创建或包装时触发全局钩子，返回原错误
+func reportCreated(err *Error) *Error {
+	if hooks := globalReportHooks.Load(); hooks != nil {
+		hooks.Report(context.Background(), err)
+	}
+	return err
+}
+
+// SlogReporter 返回使用 slog 记录错误的 Reporter，上下文经过脱敏处理
+// 严重、高级别记为 Error，中级别记为 Warn，其余记为 Info；logger 为 nil 时使用 slog.Default()
+func SlogReporter(logger *slog.Logger) Reporter {
+	return ReporterFunc(func(ctx context.Context, err *Error) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		severity := reportSeverity(err)
+		level := slog.LevelInfo
+		switch severity {
+		case SeverityCritical, SeverityHigh:
+			level = slog.LevelError
+		case SeverityMedium:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("code", err.Code),
+			slog.String("severity", string(severity)),
+			slog.String("category", string(GetCategory(err))),
+		}
+		if err.Details != "" {
+			attrs = append(attrs, slog.String("details", err.Details))
+		}
+		if ctxValues := SanitizeContext(err.Context); len(ctxValues) > 0 {
+			attrs = append(attrs, slog.Any("context", ctxValues))
+		}
+		if err.Original != nil {
+			attrs = append(attrs, slog.String("cause", err.Original.Error()))
+		}
+		l.LogAttrs(ctx, level, err.Message, attrs...)
+	})
+}
+
+// asyncReport 等待异步上报的错误
+type asyncReport struct {
+	ctx context.Context
+	err *Error
+}
+
+// AsyncReporter 带缓冲的异步 Reporter，在后台协程中依次调用下游 Reporter，
+// 避免网络上报阻塞错误创建路径。缓冲区满或已关闭时丢弃错误并计数。
+type AsyncReporter struct {
+	next    Reporter
+	queue   chan asyncReport
+	mu      sync.RWMutex
+	closed  bool
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+// NewAsyncReporter 创建异步 Reporter，bufferSize 不大于 0 时使用 DefaultReportBufferSize
+func NewAsyncReporter(next Reporter, bufferSize int) *AsyncReporter {
+	if bufferSize <= 0 {
+		bufferSize = DefaultReportBufferSize
+	}
+	a := &AsyncReporter{
+		next:  next,
+		queue: make(chan asyncReport, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// run 后台消费队列，队列关闭并处理完毕后结束
+func (a *AsyncReporter) run() {
+	defer close(a.done)
+	for r := range a.queue {
+		callReporter(r.ctx, a.next, r.err)
+	}
+}
+
+// Report 实现 Reporter 接口
+// 错误会被深拷贝后入队，调用方之后继续修改错误不会产生数据竞争；上下文不再随请求取消
+func (a *AsyncReporter) Report(ctx context.Context, err *Error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.queue <- asyncReport{ctx: context.WithoutCancel(ctx), err: Clone(err)}:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped 返回因缓冲区满或已关闭而丢弃的错误数
+func (a *AsyncReporter) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Close 停止接收新的错误，并等待缓冲区中的错误上报完毕或 ctx 结束
+func (a *AsyncReporter) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingReporter 记录收到的错误码
+type recordingReporter struct {
+	mu    sync.Mutex
+	codes []string
+}
+
+func (r *recordingReporter) Report(_ context.Context, err *Error) {
+	r.mu.Lock()
+	r.codes = append(r.codes, err.Code)
+	r.mu.Unlock()
+}
+
+func (r *recordingReporter) Codes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.codes...)
+}
+
+func TestReportHooks_GlobalThreshold(t *testing.T) {
+	rec := &recordingReporter{}
+	SetReportHooks(NewReportHooks(SeverityHigh, rec))
+	defer SetReportHooks(nil)
+
+	_ = FromType(NotFoundError)                         // 中，低于阈值
+	_ = FromType(DatabaseError)                         // 高
+	_ = WrapWithType(errors.New("boom"), InternalError) // 严重
+	_ = New(CodeTimeout, "超时")                          // 上下文无级别，按已注册类型为高
+	_ = New("CUSTOM", "自定义")                            // 未注册，按低处理
+	_ = NewBuilder().Code("CUSTOM").Message("x").Severity(SeverityCritical).Build()
+
+	want := []string{CodeDatabaseError, CodeInternal, CodeTimeout, "CUSTOM"}
+	if got := rec.Codes(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("reported = %v, want %v", got, want)
+	}
+
+	// 抑制窗口内的错误不上报
+	Suppress(CodeDatabaseError, time.Minute)
+	defer Unsuppress(CodeDatabaseError)
+	_ = FromType(DatabaseError)
+	if got := rec.Codes(); len(got) != len(want) {
+		t.Errorf("suppressed error was reported: %v", got)
+	}
+}
+
+func TestReportHooks_DeferredAndDeduplicated(t *testing.T) {
+	rec := &recordingReporter{}
+	SetReportHooks(NewReportHooks(SeverityHigh, rec))
+	defer SetReportHooks(nil)
+
+	// 构造后才设置严重级别：构造时不上报，包装时上报内层错误
+	inner := New("ORDER_SYNC", "同步失败").WithContext("severity", SeverityCritical)
+	if got := rec.Codes(); len(got) != 0 {
+		t.Fatalf("below-threshold construction reported: %v", got)
+	}
+	outer := Wrap(inner, "ORDER_FAILED", "下单失败")
+	if got := rec.Codes(); strings.Join(got, ",") != "ORDER_SYNC" {
+		t.Fatalf("reported = %v, want [ORDER_SYNC]", got)
+	}
+
+	// 同一错误链不再重复上报
+	_ = WrapWithType(outer, InternalError)
+	Report(context.Background(), outer)
+	WriteError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), outer)
+	if got := rec.Codes(); len(got) != 1 {
+		t.Errorf("error chain reported more than once: %v", got)
+	}
+}
+
+func TestWriteError_Reports(t *testing.T) {
+	var reported []*Error
+	SetReportHooks(NewReportHooks(SeverityHigh, ReporterFunc(func(_ context.Context, err *Error) {
+		reported = append(reported, err)
+	})))
+	defer SetReportHooks(nil)
+
+	cause := errors.New("pq: connection refused")
+	for _, err := range []error{
+		cause, // 非 *Error 错误作为内部错误上报
+		New("ORDER_SYNC", "同步失败").WithContext("severity", SeverityHigh), // 构造后设置的级别生效
+		FromType(NotFoundError), // 低于阈值
+	} {
+		WriteError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), err)
+	}
+
+	if len(reported) != 2 {
+		t.Fatalf("reported %d errors, want 2", len(reported))
+	}
+	if reported[0].Code != CodeInternal || !errors.Is(reported[0], cause) {
+		t.Errorf("plain error reported as %v, want internal error with cause", reported[0])
+	}
+	if reported[1].Code != "ORDER_SYNC" {
+		t.Errorf("reported[1] = %s", reported[1].Code)
+	}
+}
+
+func TestReportHooks_Instance(t *testing.T) {
+	first, second := &recordingReporter{}, &recordingReporter{}
+	hooks := NewReportHooks(SeverityMedium, first)
+	hooks.Add(ReporterFunc(func(context.Context, *Error) { panic("reporter bug") }), second)
+
+	// 未设置全局钩子时创建错误不会上报
+	err := FromType(NotFoundError)
+	if len(first.Codes()) != 0 {
+		t.Fatal("instance hooks should not fire automatically")
+	}
+
+	// 显式上报支持包装后的错误，panic 的 Reporter 不影响后续 Reporter
+	hooks.Report(context.Background(), Wrap(err, CodeNotFound, "wrapped").WithContext("severity", SeverityMedium))
+	if len(first.Codes()) != 1 || len(second.Codes()) != 1 {
+		t.Errorf("first = %v, second = %v", first.Codes(), second.Codes())
+	}
+
+	hooks.SetMinSeverity(SeverityCritical)
+	hooks.Report(context.Background(), err)
+	hooks.Report(context.Background(), errors.New("plain"))
+	if len(first.Codes()) != 1 {
+		t.Errorf("below-threshold or plain errors should be skipped: %v", first.Codes())
+	}
+}
+
+func TestSlogReporter(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := SlogReporter(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	err := FromTypeWithDetails(DatabaseError, "insert failed").WithContext("password", "hunter2")
+	reporter.Report(context.Background(), err)
+
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "code=DATABASE_ERROR", "details=\"insert failed\""} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("context should be sanitized: %s", out)
+	}
+}
+
+func TestAsyncReporter(t *testing.T) {
+	rec := &recordingReporter{}
+	block := make(chan struct{})
+	async := NewAsyncReporter(ReporterFunc(func(ctx context.Context, err *Error) {
+		<-block
+		rec.Report(ctx, err)
+	}), 2)
+
+	err := FromType(DatabaseError)
+	for i := 0; i < 4; i++ {
+		async.Report(context.Background(), err)
+	}
+	// 入队的是副本，之后修改原错误不影响上报内容
+	err.WithCode("CHANGED")
+
+	close(block)
+	if closeErr := async.Close(context.Background()); closeErr != nil {
+		t.Fatal(closeErr)
+	}
+	got := rec.Codes()
+	if len(got)+int(async.Dropped()) != 4 || len(got) < 2 {
+		t.Errorf("reported %d, dropped %d", len(got), async.Dropped())
+	}
+	for _, code := range got {
+		if code != CodeDatabaseError {
+			t.Errorf("reported code = %s, want a snapshot of the original", code)
+		}
+	}
+
+	async.Report(context.Background(), err)
+	if async.Dropped() == 0 {
+		t.Error("reports after Close should be dropped")
+	}
+}
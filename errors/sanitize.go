@@ -0,0 +1,164 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// SanitizeConfig 控制错误上下文在序列化/格式化时的截断与脱敏规则
+type SanitizeConfig struct {
+	// MaxValueLength 字符串值的默认最大长度（字节），<= 0 表示不截断
+	MaxValueLength int
+	// KeyLimits 按键名单独设置的最大长度，优先于 MaxValueLength
+	KeyLimits map[string]int
+	// SensitiveKeys 敏感键名片段（不区分大小写，包含即匹配），命中的值会被替换为 Mask
+	SensitiveKeys []string
+	// Mask 敏感值的替换文本
+	Mask string
+}
+
+// DefaultSanitizeConfig 返回默认的脱敏配置
+func DefaultSanitizeConfig() *SanitizeConfig {
+	return &SanitizeConfig{
+		MaxValueLength: 1024,
+		KeyLimits:      map[string]int{},
+		SensitiveKeys: []string{
+			"password", "passwd", "secret", "token", "authorization",
+			"api_key", "apikey", "cookie", "credential", "private_key",
+		},
+		Mask: "******",
+	}
+}
+
+var sanitizeConfig atomic.Pointer[SanitizeConfig]
+
+func init() {
+	sanitizeConfig.Store(DefaultSanitizeConfig())
+}
+
+// SetSanitizeConfig 设置全局脱敏配置，传入 nil 恢复默认配置
+func SetSanitizeConfig(cfg *SanitizeConfig) {
+	if cfg == nil {
+		cfg = DefaultSanitizeConfig()
+	}
+	sanitizeConfig.Store(cfg)
+}
+
+// GetSanitizeConfig 返回当前全局脱敏配置
+func GetSanitizeConfig() *SanitizeConfig {
+	return sanitizeConfig.Load()
+}
+
+// SanitizeContext 按全局配置返回脱敏、截断后的上下文副本，原始上下文不会被修改
+func SanitizeContext(ctx map[string]interface{}) map[string]interface{} {
+	return GetSanitizeConfig().Sanitize(ctx)
+}
+
+// Sanitize 按当前配置返回脱敏、截断后的上下文副本
+func (c *SanitizeConfig) Sanitize(ctx map[string]interface{}) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(ctx))
+	for k, v := range ctx {
+		result[k] = c.sanitizeValue(k, v)
+	}
+	return result
+}
+
+// IsSensitiveKey 判断键名是否命中敏感键列表
+func (c *SanitizeConfig) IsSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, s := range c.SensitiveKeys {
+		if s != "" && strings.Contains(lower, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitFor 返回键对应的长度限制
+func (c *SanitizeConfig) limitFor(key string) int {
+	if limit, ok := c.KeyLimits[key]; ok {
+		return limit
+	}
+	return c.MaxValueLength
+}
+
+// sanitizeValue 处理单个上下文值，嵌套 map 和切片会递归处理
+func (c *SanitizeConfig) sanitizeValue(key string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if c.IsSensitiveKey(key) {
+		return c.Mask
+	}
+
+	switch val := v.(type) {
+	case string:
+		return c.truncate(key, val)
+	case []byte:
+		return c.truncate(key, string(val))
+	case *Error, *RichError:
+		// 自身的序列化逻辑会处理上下文
+		return val
+	case error:
+		return c.truncate(key, val.Error())
+	case map[string]interface{}:
+		return c.Sanitize(val)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			result[k] = c.sanitizeValue(k, iter.Value().Interface())
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result[i] = c.sanitizeValue(key, rv.Index(i).Interface())
+		}
+		return result
+	}
+	return v
+}
+
+// truncate 按限制截断字符串，保证不截断多字节字符
+func (c *SanitizeConfig) truncate(key, s string) string {
+	limit := c.limitFor(key)
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...(truncated %d bytes)", s[:cut], len(s)-cut)
+}
+
+// MarshalJSON 序列化时对上下文执行脱敏和截断
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	type errorAlias Error
+	return json.Marshal(&struct {
+		*errorAlias
+		Context map[string]interface{} `json:"context"`
+	}{
+		errorAlias: (*errorAlias)(e),
+		Context:    SanitizeContext(e.Context),
+	})
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeContext_MasksSensitiveKeys(t *testing.T) {
+	ctx := map[string]interface{}{
+		"user_id":       "u1",
+		"Authorization": "Bearer abc",
+		"db_password":   "hunter2",
+		"request": map[string]interface{}{
+			"access_token": "xyz",
+			"page":         2,
+		},
+	}
+
+	got := SanitizeContext(ctx)
+
+	if got["user_id"] != "u1" {
+		t.Errorf("user_id = %v", got["user_id"])
+	}
+	mask := GetSanitizeConfig().Mask
+	if got["Authorization"] != mask || got["db_password"] != mask {
+		t.Errorf("sensitive values not masked: %v", got)
+	}
+	nested := got["request"].(map[string]interface{})
+	if nested["access_token"] != mask || nested["page"] != 2 {
+		t.Errorf("nested values not sanitized: %v", nested)
+	}
+	if ctx["db_password"] != "hunter2" {
+		t.Error("original context must not be modified")
+	}
+}
+
+func TestSanitizeContext_Truncation(t *testing.T) {
+	SetSanitizeConfig(&SanitizeConfig{
+		MaxValueLength: 10,
+		KeyLimits:      map[string]int{"sql": 4, "body": 0},
+	})
+	defer SetSanitizeConfig(nil)
+
+	long := strings.Repeat("a", 50)
+	got := SanitizeContext(map[string]interface{}{
+		"note": long,
+		"sql":  "SELECT * FROM users",
+		"body": long,
+		"zh":   "错误错误错误错误",
+	})
+
+	if got["note"] != "aaaaaaaaaa...(truncated 40 bytes)" {
+		t.Errorf("note = %v", got["note"])
+	}
+	if got["sql"] != "SELE...(truncated 15 bytes)" {
+		t.Errorf("sql = %v", got["sql"])
+	}
+	if got["body"] != long {
+		t.Error("per-key limit of 0 should disable truncation")
+	}
+	if zh := got["zh"].(string); !strings.HasPrefix(zh, "错误错") || strings.ContainsRune(zh, '�') {
+		t.Errorf("multi-byte truncation broke a rune: %q", zh)
+	}
+}
+
+func TestError_MarshalJSON_Sanitized(t *testing.T) {
+	err := New(CodeInvalidInput, "bad").
+		WithContext("token", "secret-token").
+		WithContext("field", "email")
+
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("Marshal error = %v", e)
+	}
+	s := string(data)
+	if strings.Contains(s, "secret-token") {
+		t.Errorf("token leaked into JSON: %s", s)
+	}
+	if !strings.Contains(s, `"field":"email"`) || !strings.Contains(s, `"code":"INVALID_INPUT"`) {
+		t.Errorf("unexpected JSON: %s", s)
+	}
+	if err.Context["token"] != "secret-token" {
+		t.Error("MarshalJSON must not modify the error")
+	}
+
+	if detailed := Format(err, "detailed"); strings.Contains(detailed, "secret-token") {
+		t.Errorf("token leaked into detailed format: %s", detailed)
+	}
+}
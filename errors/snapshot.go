@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UpdateSnapshots 为 true 时 AssertSnapshot 用当前输出覆盖快照文件
+// 可在测试中绑定到命令行参数：flag.BoolVar(&errors.UpdateSnapshots, "update", false, "update error snapshots")
+// 也可以设置环境变量 UPDATE_ERROR_SNAPSHOTS=1
+var UpdateSnapshots bool
+
+// pointerPattern 匹配内存地址，快照中统一替换以保证输出稳定
+var pointerPattern = regexp.MustCompile(`0x[0-9a-fA-F]{6,}`)
+
+// SnapshotString 生成错误的规范化多行文本，用于 golden 文件测试
+// 按错误链逐层输出，map 键排序，时间戳、堆栈和内存地址被剔除，上下文按全局脱敏配置处理
+func SnapshotString(err error) string {
+	if err == nil {
+		return "<nil>\n"
+	}
+
+	var b strings.Builder
+	for depth := 0; err != nil; depth++ {
+		fmt.Fprintf(&b, "[%d] %T\n", depth, err)
+
+		switch e := err.(type) {
+		case *Error:
+			writeSnapshotField(&b, "code", e.Code)
+			writeSnapshotField(&b, "message", e.Message)
+			writeSnapshotField(&b, "details", e.Details)
+			writeSnapshotMap(&b, "context", SanitizeContext(e.Context))
+			if len(e.Localized) > 0 {
+				localized := make(map[string]interface{}, len(e.Localized))
+				for k, v := range e.Localized {
+					localized[k] = v
+				}
+				writeSnapshotMap(&b, "localized", localized)
+			}
+			err = e.Original
+			continue
+		case *RichError:
+			writeSnapshotField(&b, "code", fmt.Sprint(e.Code))
+			writeSnapshotField(&b, "msg", e.Msg)
+		default:
+			writeSnapshotField(&b, "error", err.Error())
+		}
+
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return b.String()
+}
+
+// writeSnapshotField 输出单个字段，空值省略
+func writeSnapshotField(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "  %s: %s\n", name, normalizeSnapshotValue(value))
+}
+
+// writeSnapshotMap 按键排序输出 map
+func writeSnapshotMap(b *strings.Builder, name string, m map[string]interface{}) {
+	if len(m) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(b, "  %s:\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(b, "    %s: %s\n", k, snapshotValue(m[k]))
+	}
+}
+
+// snapshotValue 格式化上下文值，时间类值替换为占位符
+func snapshotValue(v interface{}) string {
+	switch val := v.(type) {
+	case time.Time, *time.Time:
+		return "<timestamp>"
+	case error:
+		return normalizeSnapshotValue(val.Error())
+	}
+	// fmt 对 map 按键排序输出，结果稳定
+	return normalizeSnapshotValue(fmt.Sprintf("%v", v))
+}
+
+// normalizeSnapshotValue 去除换行与内存地址
+func normalizeSnapshotValue(s string) string {
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return pointerPattern.ReplaceAllString(s, "0xADDR")
+}
+
+// SnapshotT 快照断言需要的测试接口，*testing.T 与 *testing.B 均满足
+type SnapshotT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// AssertSnapshot 将 SnapshotString(err) 与 testdata/{name}.golden 比较
+// 开启 UpdateSnapshots 时写入当前输出；快照文件不存在时测试失败，需先以更新模式生成
+func AssertSnapshot(t SnapshotT, name string, err error) {
+	t.Helper()
+
+	got := SnapshotString(err)
+	path := filepath.Join("testdata", name+".golden")
+
+	if UpdateSnapshots || os.Getenv("UPDATE_ERROR_SNAPSHOTS") == "1" {
+		writeSnapshotFile(t, path, got)
+		return
+	}
+
+	want, readErr := os.ReadFile(path)
+	if os.IsNotExist(readErr) {
+		t.Fatalf("error snapshot %s does not exist; rerun with UPDATE_ERROR_SNAPSHOTS=1 to create it\n--- got\n%s", path, got)
+		return
+	}
+	if readErr != nil {
+		t.Fatalf("failed to read snapshot %s: %v", path, readErr)
+		return
+	}
+	if string(want) != got {
+		t.Errorf("error snapshot %s mismatch\n--- want\n%s--- got\n%s", path, want, got)
+	}
+}
+
+// writeSnapshotFile 写入快照文件
+func writeSnapshotFile(t SnapshotT, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create snapshot dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write snapshot %s: %v", path, err)
+	}
+}
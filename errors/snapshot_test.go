@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func snapshotFixture() error {
+	root := fmt.Errorf("query users: %w", io.ErrUnexpectedEOF)
+	rich := WrapRichNoStack(root, 50001, "数据库读取失败")
+	return WrapWithDetails(rich, "USER_LOAD_FAILED", "加载用户失败", "retry later").
+		WithContext("user_id", 42).
+		WithContext("password", "hunter2").
+		WithContext("requested_at", time.Now()).
+		WithContext("filters", map[string]int{"b": 2, "a": 1}).
+		WithLocalizedMessage("en", "Failed to load user")
+}
+
+func TestSnapshotString(t *testing.T) {
+	want := `[0] *errors.Error
+  code: USER_LOAD_FAILED
+  message: 加载用户失败
+  details: retry later
+  context:
+    filters: map[a:1 b:2]
+    password: ******
+    requested_at: <timestamp>
+    user_id: 42
+  localized:
+    en: Failed to load user
+[1] *errors.RichError
+  code: 50001
+  msg: 数据库读取失败
+[2] *fmt.wrapError
+  error: query users: unexpected EOF
+[3] *errors.errorString
+  error: unexpected EOF
+`
+	if got := SnapshotString(snapshotFixture()); got != want {
+		t.Errorf("SnapshotString() =\n%s\nwant\n%s", got, want)
+	}
+
+	// 两次生成结果一致（时间戳不同也不影响）
+	if SnapshotString(snapshotFixture()) != SnapshotString(snapshotFixture()) {
+		t.Error("SnapshotString should be deterministic")
+	}
+	if SnapshotString(nil) != "<nil>\n" {
+		t.Errorf("SnapshotString(nil) = %q", SnapshotString(nil))
+	}
+}
+
+func TestAssertSnapshot(t *testing.T) {
+	AssertSnapshot(t, "user_load_failed", snapshotFixture())
+}
+
+// fakeSnapshotT 记录断言结果
+type fakeSnapshotT struct {
+	failed bool
+}
+
+func (f *fakeSnapshotT) Helper()                                   {}
+func (f *fakeSnapshotT) Errorf(format string, args ...interface{}) { f.failed = true }
+func (f *fakeSnapshotT) Fatalf(format string, args ...interface{}) { f.failed = true }
+
+func TestAssertSnapshot_MissingFile(t *testing.T) {
+	ft := &fakeSnapshotT{}
+	AssertSnapshot(ft, "does_not_exist", snapshotFixture())
+	if !ft.failed {
+		t.Error("missing snapshot should fail without the update flag")
+	}
+	if _, err := os.Stat(filepath.Join("testdata", "does_not_exist.golden")); !os.IsNotExist(err) {
+		t.Error("missing snapshot should not be created without the update flag")
+	}
+}
@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// StrictModeEnv 控制严格模式的环境变量，取值为 strconv.ParseBool 可识别的真值（如 1、true）时在包初始化时开启
+const StrictModeEnv = "ERRORS_STRICT"
+
+// strictMode 是否开启严格模式
+var strictMode atomic.Bool
+
+func init() {
+	enabled, _ := strconv.ParseBool(os.Getenv(StrictModeEnv))
+	strictMode.Store(enabled)
+}
+
+// SetStrictMode 开启或关闭严格模式
+//
+// 严格模式仅用于开发与测试：使用未注册的错误码、空消息，或严重级别与已注册错误类型冲突时
+// 构造函数会立即 panic，尽早暴露错误码体系的误用。生产环境不应开启。
+func SetStrictMode(enabled bool) {
+	strictMode.Store(enabled)
+}
+
+// StrictMode 返回严格模式是否开启
+func StrictMode() bool {
+	return strictMode.Load()
+}
+
+var (
+	errorTypesMu sync.RWMutex
+	errorTypes   = map[string]ErrorType{}
+
+	// internalCodes 包内部构造错误时使用的错误码
+	internalCodes = map[string]bool{
+		"WRAPPED_ERROR":   true,
+		"MULTIPLE_ERRORS": true,
+		"CLONED_ERROR":    true,
+	}
+)
+
+func init() {
+	for _, t := range []ErrorType{
+		InternalError, TimeoutError, CanceledError, NotFoundError,
+		UnauthorizedError, ForbiddenError,
+		InvalidInputError, MissingFieldError,
+		NetworkError, DatabaseError,
+	} {
+		errorTypes[t.Code] = t
+	}
+}
+
+// RegisterErrorType 注册自定义错误类型，注册后其错误码在严格模式下视为合法，
+// 其严重级别作为该错误码的标准严重级别；同一错误码以不同严重级别重复注册时，严格模式下 panic
+func RegisterErrorType(types ...ErrorType) {
+	errorTypesMu.Lock()
+	defer errorTypesMu.Unlock()
+	for _, t := range types {
+		strictCheck(t.Code != "", "registering an error type with an empty code")
+		if existing, ok := errorTypes[t.Code]; ok {
+			strictCheck(existing.Severity == t.Severity,
+				"error code %q registered with conflicting severities %q and %q", t.Code, existing.Severity, t.Severity)
+		}
+		errorTypes[t.Code] = t
+	}
+}
+
+// LookupErrorType 返回错误码对应的已注册错误类型
+func LookupErrorType(code string) (ErrorType, bool) {
+	errorTypesMu.RLock()
+	defer errorTypesMu.RUnlock()
+	t, ok := errorTypes[code]
+	return t, ok
+}
+
+// isRegisteredCode 判断错误码是否已通过 RegisterErrorType 或 RegisterHTTPStatus 注册
+func isRegisteredCode(code string) bool {
+	if internalCodes[code] {
+		return true
+	}
+	if _, ok := LookupErrorType(code); ok {
+		return true
+	}
+	httpStatusMu.RLock()
+	_, ok := httpStatuses[code]
+	httpStatusMu.RUnlock()
+	return ok
+}
+
+// checkStrict 严格模式下校验构造参数，severity 为空时不检查严重级别
+func checkStrict(code, message string, severity Severity) {
+	if !strictMode.Load() {
+		return
+	}
+	strictCheck(code != "", "error created with an empty code")
+	strictCheck(isRegisteredCode(code), "error created with unregistered code %q", code)
+	strictCheck(message != "", "error %q created with an empty message", code)
+	if severity == "" {
+		return
+	}
+	if t, ok := LookupErrorType(code); ok && t.Severity != "" {
+		strictCheck(t.Severity == severity,
+			"error %q created with severity %q, registered severity is %q", code, severity, t.Severity)
+	}
+}
+
+// strictCheck 严格模式下条件不成立时 panic
+func strictCheck(ok bool, format string, args ...interface{}) {
+	if !ok && strictMode.Load() {
+		panic("errors: strict mode: " + fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+// expectStrictPanic 断言 fn 以严格模式错误 panic，且消息包含 want
+func expectStrictPanic(t *testing.T, want string, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		msg, _ := r.(string)
+		if !strings.HasPrefix(msg, "errors: strict mode: ") || !strings.Contains(msg, want) {
+			t.Errorf("panic = %v, want strict mode panic containing %q", r, want)
+		}
+	}()
+	fn()
+}
+
+func TestStrictMode_Disabled(t *testing.T) {
+	SetStrictMode(false)
+
+	// 未开启时保持原有宽松行为
+	if err := New("ANY_CODE", ""); err.Code != "ANY_CODE" {
+		t.Errorf("New() = %v", err)
+	}
+	_ = NewBuilder().Code(CodeNotFound).Message("x").Severity(SeverityCritical).Build()
+}
+
+func TestStrictMode_Panics(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	expectStrictPanic(t, `unregistered code "UNKNOWN_CODE"`, func() { New("UNKNOWN_CODE", "未知") })
+	expectStrictPanic(t, "empty code", func() { Wrap(nil, "", "消息") })
+	expectStrictPanic(t, "empty message", func() { NewWithDetails(CodeNotFound, "", "details") })
+	expectStrictPanic(t, "conflicting", func() {
+		RegisterErrorType(ErrorType{Code: CodeNotFound, Message: "x", Severity: SeverityLow})
+	})
+	expectStrictPanic(t, "registered severity", func() {
+		NewBuilder().Code(CodeNotFound).Message("未找到").Severity(SeverityCritical).Build()
+	})
+	expectStrictPanic(t, "registered severity", func() {
+		FromType(ErrorType{Code: CodeDatabaseError, Message: "库错误", Severity: SeverityLow})
+	})
+}
+
+func TestStrictMode_RegisteredCodes(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	// 预定义类型、HTTP 状态映射中的错误码以及包内部错误码都视为已注册
+	_ = FromType(NotFoundError)
+	_ = New(CodeQuotaExceeded, "超出配额")
+	_ = Merge(New(CodeInvalidInput, "a"), New(CodeMissingField, "b"))
+	_ = NewBuilder().Code(CodeNotFound).Message("未找到").Severity(SeverityMedium).Build()
+
+	RegisterHTTPStatus("ORDER_LOCKED", 423)
+	_ = New("ORDER_LOCKED", "订单已锁定")
+
+	orderExpired := ErrorType{Code: "ORDER_EXPIRED", Message: "订单已过期", Severity: SeverityLow, Category: CategoryBusiness}
+	RegisterErrorType(orderExpired)
+	_ = FromType(orderExpired)
+	if got, ok := LookupErrorType("ORDER_EXPIRED"); !ok || got.Category != CategoryBusiness {
+		t.Errorf("LookupErrorType() = %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,324 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidateTag 结构体校验读取的标签名
+const ValidateTag = "validate"
+
+// ValidateStruct 按 `validate` 标签校验结构体，返回的 Validator 与逐字段调用 Required、MinLength 等方法的结果一致
+//
+//	type CreateUserRequest struct {
+//		Name  string   `json:"name" validate:"required,min=3,max=64"`
+//		Email string   `json:"email" validate:"required,email"`
+//		Age   int      `json:"age" validate:"omitempty,min=18,max=150"`
+//		Role  string   `json:"role" validate:"oneof=admin member"`
+//		Tags  []string `json:"tags" validate:"max=10"`
+//	}
+//
+//	if v := errors.ValidateStruct(req); v.HasErrors() {
+//		return v.GetError()
+//	}
+//
+// 支持的规则：required、omitempty、min=n、max=n、len=n、email、url、numeric、integer、oneof=a b c。
+// min/max/len 对字符串和切片、map 校验长度，对数字校验数值。
+// 字段名优先使用 json 标签，嵌套结构体（含指针与结构体切片）会递归校验，字段名形如 address.city、items[0].sku。
+// 每个字段遇到第一个失败的规则即停止。s 不是结构体或标签写错属于编程错误，会直接 panic。
+func ValidateStruct(s interface{}) *Validator {
+	return NewValidator().Struct(s)
+}
+
+// Struct 按 `validate` 标签校验结构体，错误追加到当前 Validator，规则说明见 ValidateStruct
+func (v *Validator) Struct(s interface{}) *Validator {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			panic("errors: ValidateStruct called with nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("errors: ValidateStruct expects a struct, got %T", s))
+	}
+	v.validateStruct(rv, "")
+	return v
+}
+
+// structRule 解析后的单条规则
+type structRule struct {
+	name    string
+	param   string
+	number  float64  // min/max/len 的参数
+	options []string // oneof 的候选值
+}
+
+// structField 解析后的字段校验信息
+type structField struct {
+	index     int
+	name      string
+	rules     []structRule
+	omitempty bool
+}
+
+// structFieldCache 按类型缓存解析结果
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// cachedStructFields 返回类型的字段校验信息，首次使用时解析标签
+func cachedStructFields(t reflect.Type) []structField {
+	if fields, ok := structFieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields := parseStructFields(t)
+	structFieldCache.Store(t, fields)
+	return fields
+}
+
+// parseStructFields 解析结构体的导出字段
+func parseStructFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get(ValidateTag)
+		if tag == "-" {
+			continue
+		}
+
+		field := structField{index: i, name: jsonFieldName(f)}
+		if tag != "" {
+			for _, part := range strings.Split(tag, ",") {
+				rule := parseStructRule(t, f, strings.TrimSpace(part))
+				if rule.name == "omitempty" {
+					field.omitempty = true
+					continue
+				}
+				field.rules = append(field.rules, rule)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// parseStructRule 解析单条规则并检查参数
+func parseStructRule(t reflect.Type, f reflect.StructField, part string) structRule {
+	name, param, _ := strings.Cut(part, "=")
+	rule := structRule{name: name, param: param}
+
+	switch name {
+	case "required", "omitempty", "email", "url", "numeric", "integer":
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			panic(fmt.Sprintf("errors: invalid %s parameter %q on %s.%s", name, param, t.Name(), f.Name))
+		}
+		rule.number = n
+	case "oneof":
+		rule.options = strings.Fields(param)
+		if len(rule.options) == 0 {
+			panic(fmt.Sprintf("errors: oneof requires values on %s.%s", t.Name(), f.Name))
+		}
+	default:
+		panic(fmt.Sprintf("errors: unknown validate rule %q on %s.%s", name, t.Name(), f.Name))
+	}
+	return rule
+}
+
+// jsonFieldName 返回字段在 JSON 中的名称，未设置时使用字段名
+func jsonFieldName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+// validateStruct 校验结构体的所有字段，prefix 为嵌套字段的路径前缀
+func (v *Validator) validateStruct(rv reflect.Value, prefix string) {
+	for _, field := range cachedStructFields(rv.Type()) {
+		name := field.name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fv := rv.Field(field.index)
+		if v.validateField(name, fv, field) {
+			v.validateNested(name, fv)
+		}
+	}
+}
+
+// validateNested 递归校验嵌套结构体、结构体指针以及结构体切片
+func (v *Validator) validateNested(name string, fv reflect.Value) {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return
+		}
+		fv = fv.Elem()
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		// time.Time 等没有导出字段的类型不会产生任何规则
+		v.validateStruct(fv, name)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			v.validateNested(fmt.Sprintf("%s[%d]", name, i), fv.Index(i))
+		}
+	}
+}
+
+// validateField 按顺序执行字段的规则，遇到第一个失败即停止，返回字段是否通过
+func (v *Validator) validateField(name string, fv reflect.Value, field structField) bool {
+	empty := isZeroValue(fv)
+	if empty && field.omitempty {
+		return true
+	}
+	value := fieldInterface(fv)
+
+	before := len(v.errors)
+	for _, rule := range field.rules {
+		switch {
+		case rule.name == "required":
+			// 非 nil 指针视为已提供，即使指向零值
+			if empty {
+				v.AddError(NewValidationError(name, "required", fmt.Sprintf("Field '%s' is required", name), value))
+			}
+		case fv.Kind() == reflect.Ptr && fv.IsNil():
+			// nil 指针只由 required 校验
+		default:
+			v.applyStructRule(name, fv, value, rule)
+		}
+		if len(v.errors) > before {
+			return false
+		}
+	}
+	return true
+}
+
+// applyStructRule 执行单条规则
+func (v *Validator) applyStructRule(name string, fv reflect.Value, value interface{}, rule structRule) {
+	for fv.Kind() == reflect.Ptr {
+		fv = fv.Elem()
+	}
+
+	switch rule.name {
+	case "email", "url", "numeric", "integer":
+		if fv.Kind() != reflect.String {
+			panic(fmt.Sprintf("errors: rule %s requires a string field, %s is %s", rule.name, name, fv.Kind()))
+		}
+	}
+
+	switch rule.name {
+	case "min", "max", "len":
+		v.applyBoundRule(name, fv, value, rule)
+	case "email":
+		v.Email(name, fv.String())
+	case "url":
+		v.URL(name, fv.String())
+	case "numeric":
+		v.Numeric(name, fv.String())
+	case "integer":
+		v.Integer(name, fv.String())
+	case "oneof":
+		s := fmt.Sprint(value)
+		for _, option := range rule.options {
+			if s == option {
+				return
+			}
+		}
+		allowed := make([]interface{}, len(rule.options))
+		for i, option := range rule.options {
+			allowed[i] = option
+		}
+		v.AddError(NewValidationError(name, "in",
+			fmt.Sprintf("Field '%s' must be one of the allowed values", name), value).
+			WithParams(map[string]interface{}{"allowed": allowed}))
+	}
+}
+
+// applyBoundRule 执行 min/max/len，字符串与集合校验长度，数字校验数值
+func (v *Validator) applyBoundRule(name string, fv reflect.Value, value interface{}, rule structRule) {
+	n := int(rule.number)
+	switch fv.Kind() {
+	case reflect.String:
+		switch rule.name {
+		case "min":
+			v.MinLength(name, fv.String(), n)
+		case "max":
+			v.MaxLength(name, fv.String(), n)
+		default:
+			v.Length(name, fv.String(), n)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		count := fv.Len()
+		switch {
+		case rule.name == "min" && count < n:
+			v.AddError(NewValidationError(name, "min_length",
+				fmt.Sprintf("Field '%s' must contain at least %d items", name, n), value).
+				WithParams(map[string]interface{}{"min": n}))
+		case rule.name == "max" && count > n:
+			v.AddError(NewValidationError(name, "max_length",
+				fmt.Sprintf("Field '%s' must contain at most %d items", name, n), value).
+				WithParams(map[string]interface{}{"max": n}))
+		case rule.name == "len" && count != n:
+			v.AddError(NewValidationError(name, "length",
+				fmt.Sprintf("Field '%s' must contain exactly %d items", name, n), value).
+				WithParams(map[string]interface{}{"length": n}))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.applyNumberRule(name, float64(fv.Int()), rule)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.applyNumberRule(name, float64(fv.Uint()), rule)
+	case reflect.Float32, reflect.Float64:
+		v.applyNumberRule(name, fv.Float(), rule)
+	default:
+		panic(fmt.Sprintf("errors: rule %s is not supported on field %s of kind %s", rule.name, name, fv.Kind()))
+	}
+}
+
+// applyNumberRule 对数值执行 min/max/len（len 视为等于）
+func (v *Validator) applyNumberRule(name string, value float64, rule structRule) {
+	switch rule.name {
+	case "min":
+		v.Min(name, value, rule.number)
+	case "max":
+		v.Max(name, value, rule.number)
+	default:
+		v.Range(name, value, rule.number, rule.number)
+	}
+}
+
+// isZeroValue 判断字段是否为空：零值、nil、空字符串（忽略空白）、空集合
+func isZeroValue(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return fv.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(fv.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fv.Len() == 0
+	}
+	return fv.IsZero()
+}
+
+// fieldInterface 返回用于错误信息的字段值，指针会被解引用
+func fieldInterface(fv reflect.Value) interface{} {
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if !fv.CanInterface() {
+		return nil
+	}
+	return fv.Interface()
+}
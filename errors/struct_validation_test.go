@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"omitempty,len=6,numeric"`
+}
+
+type testItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=99"`
+}
+
+type testCreateUser struct {
+	Name     string       `json:"name" validate:"required,min=3,max=64"`
+	Email    string       `json:"email" validate:"required,email"`
+	Age      int          `json:"age" validate:"omitempty,min=18,max=150"`
+	Role     string       `json:"role" validate:"oneof=admin member"`
+	Website  string       `validate:"url"`
+	Tags     []string     `json:"tags" validate:"max=2"`
+	Nickname *string      `json:"nickname" validate:"omitempty,min=2"`
+	Address  *testAddress `json:"address" validate:"required"`
+	Items    []testItem   `json:"items" validate:"min=1"`
+	Ignored  string       `validate:"-"`
+	internal string
+}
+
+func validationFields(v *Validator) map[string]string {
+	fields := make(map[string]string)
+	for _, err := range v.GetErrors() {
+		fields[err.Field] = err.Rule
+	}
+	return fields
+}
+
+func TestValidateStruct_Valid(t *testing.T) {
+	req := testCreateUser{
+		Name:    "alice",
+		Email:   "alice@example.com",
+		Role:    "admin",
+		Address: &testAddress{City: "Shanghai", Zip: "200000"},
+		Items:   []testItem{{SKU: "A-1", Quantity: 2}},
+	}
+	if v := ValidateStruct(&req); v.HasErrors() {
+		t.Fatalf("unexpected errors: %v", v.GetError())
+	}
+}
+
+func TestValidateStruct_Errors(t *testing.T) {
+	short := "x"
+	req := testCreateUser{
+		Name:     "al",
+		Email:    "not-an-email",
+		Age:      12,
+		Role:     "owner",
+		Website:  "example",
+		Tags:     []string{"a", "b", "c"},
+		Nickname: &short,
+		Address:  &testAddress{Zip: "12"},
+		Items:    []testItem{{SKU: "A-1", Quantity: 0}, {Quantity: 100}},
+	}
+
+	got := validationFields(ValidateStruct(req))
+	want := map[string]string{
+		"name":              "min_length",
+		"email":             "email",
+		"age":               "min",
+		"role":              "in",
+		"Website":           "url",
+		"tags":              "max_length",
+		"nickname":          "min_length",
+		"address.city":      "required",
+		"address.zip":       "length",
+		"items[0].quantity": "min",
+		"items[1].sku":      "required",
+		"items[1].quantity": "max",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d errors %v, want %d", len(got), got, len(want))
+	}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("%s: rule = %q, want %q", field, got[field], rule)
+		}
+	}
+}
+
+func TestValidateStruct_RequiredStopsField(t *testing.T) {
+	v := ValidateStruct(testCreateUser{})
+	got := validationFields(v)
+	for _, field := range []string{"name", "email", "address"} {
+		if got[field] != "required" {
+			t.Errorf("%s: rule = %q, want required", field, got[field])
+		}
+	}
+	// 每个字段只报告第一个失败的规则
+	count := 0
+	for _, err := range v.GetErrors() {
+		if err.Field == "name" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("name reported %d times", count)
+	}
+	if err := v.GetError(); err == nil || !strings.Contains(err.Details, "Field 'email' is required") {
+		t.Errorf("GetError() = %v", err)
+	}
+}
+
+func TestValidator_StructChaining(t *testing.T) {
+	v := NewValidator().Required("token", "")
+	v.Struct(&testAddress{})
+	if len(v.GetErrors()) != 2 {
+		t.Errorf("expected errors from both manual and struct validation, got %v", v.GetErrors())
+	}
+}
+
+func TestValidateStruct_Panics(t *testing.T) {
+	type badRule struct {
+		Name string `validate:"required,uuid"`
+	}
+	type badParam struct {
+		Name string `validate:"min=abc"`
+	}
+	tests := map[string]interface{}{
+		"not a struct": "x",
+		"nil pointer":  (*testAddress)(nil),
+		"unknown rule": badRule{},
+		"bad param":    badParam{Name: "x"},
+	}
+	for name, input := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", name)
+				}
+			}()
+			ValidateStruct(input)
+		}()
+	}
+}
@@ -0,0 +1,141 @@
+package errors
+
+import (
+	stderrors "errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Suppression 一条错误抑制规则
+// 在 [Start, Until) 时间窗口内，错误码为 Code 的错误被降级为警告，不计入指标、不触发告警
+type Suppression struct {
+	Code   string    `json:"code"`
+	Reason string    `json:"reason,omitempty"`
+	Start  time.Time `json:"start"`
+	Until  time.Time `json:"until"`
+	Hits   int64     `json:"hits"` // 窗口内被抑制的错误次数
+}
+
+// Active 判断抑制规则在给定时间是否生效
+func (s Suppression) Active(now time.Time) bool {
+	return !now.Before(s.Start) && now.Before(s.Until)
+}
+
+// suppressionEntry 抑制规则及其命中次数，命中次数用原子计数，检查时只需读锁
+type suppressionEntry struct {
+	rule Suppression
+	hits atomic.Int64
+}
+
+var (
+	suppressionsMu sync.RWMutex
+	suppressions   = make(map[string]*suppressionEntry)
+)
+
+// Suppress 在接下来的 window 时间内抑制指定错误码，到期后自动失效
+// 适用于已知不稳定的下游依赖（如维护窗口期间的第三方接口）
+func Suppress(code string, window time.Duration) {
+	SuppressWithReason(code, window, "")
+}
+
+// SuppressWithReason 同 Suppress，并记录抑制原因便于审计
+// 对同一错误码重复调用会覆盖原有规则并重置命中次数
+func SuppressWithReason(code string, window time.Duration, reason string) {
+	if code == "" || window <= 0 {
+		return
+	}
+	now := time.Now()
+
+	suppressionsMu.Lock()
+	defer suppressionsMu.Unlock()
+	suppressions[code] = &suppressionEntry{rule: Suppression{
+		Code:   code,
+		Reason: reason,
+		Start:  now,
+		Until:  now.Add(window),
+	}}
+}
+
+// Unsuppress 立即取消指定错误码的抑制
+func Unsuppress(code string) {
+	suppressionsMu.Lock()
+	delete(suppressions, code)
+	suppressionsMu.Unlock()
+}
+
+// IsSuppressed 判断错误当前是否处于抑制窗口内，命中时累计命中次数
+// 错误码的取法与指标标签一致（RichError 使用数字业务码）；
+// 同一个 *Error 经过指标、上报等多条路径时只计一次命中
+func IsSuppressed(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	suppressionsMu.RLock()
+	if len(suppressions) == 0 {
+		suppressionsMu.RUnlock()
+		return false
+	}
+	code := LabelsOf(err).Code
+	entry, ok := suppressions[code]
+	suppressionsMu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	if !entry.rule.Active(time.Now()) {
+		// 惰性清理已过期的规则，期间规则可能已被替换
+		suppressionsMu.Lock()
+		if suppressions[code] == entry {
+			delete(suppressions, code)
+		}
+		suppressionsMu.Unlock()
+		return false
+	}
+	if firstSuppressionHit(err) {
+		entry.hits.Add(1)
+	}
+	return true
+}
+
+// firstSuppressionHit 标记错误链中的 *Error 已计入命中次数，首次标记时返回 true；没有 *Error 时总是返回 true
+func firstSuppressionHit(err error) bool {
+	var appErr *Error
+	if !stderrors.As(err, &appErr) {
+		return true
+	}
+	return appErr.suppressionHit.CompareAndSwap(false, true)
+}
+
+// EffectiveSeverity 返回错误的实际严重级别，处于抑制窗口内的错误降级为 SeverityLow
+func EffectiveSeverity(err error) Severity {
+	if IsSuppressed(err) {
+		return SeverityLow
+	}
+	return LabelsOf(err).Severity
+}
+
+// ActiveSuppressions 返回当前生效的抑制规则（按到期时间排序），用于审计
+func ActiveSuppressions() []Suppression {
+	now := time.Now()
+
+	suppressionsMu.Lock()
+	defer suppressionsMu.Unlock()
+
+	result := make([]Suppression, 0, len(suppressions))
+	for code, entry := range suppressions {
+		if !entry.rule.Active(now) {
+			delete(suppressions, code)
+			continue
+		}
+		s := entry.rule
+		s.Hits = entry.hits.Load()
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Until.Before(result[j].Until)
+	})
+	return result
+}
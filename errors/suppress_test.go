@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSuppress(t *testing.T) {
+	defer Unsuppress("UPSTREAM_TIMEOUT")
+
+	sink := NewCounterSink()
+	SetMetricsSink(sink)
+	defer SetMetricsSink(nil)
+
+	err := NewBuilder().Code("UPSTREAM_TIMEOUT").Message("payment gateway timeout").Severity(SeverityHigh).Build()
+	labels := LabelsOf(err)
+
+	EmitMetric(err)
+	if sink.Count(labels) != 1 {
+		t.Fatalf("expected metric before suppression, got %d", sink.Count(labels))
+	}
+
+	SuppressWithReason("UPSTREAM_TIMEOUT", 50*time.Millisecond, "gateway maintenance")
+	if !IsSuppressed(err) {
+		t.Fatal("expected error to be suppressed")
+	}
+	if got := EffectiveSeverity(err); got != SeverityLow {
+		t.Errorf("EffectiveSeverity() = %v, want %v", got, SeverityLow)
+	}
+	EmitMetric(err)
+	if sink.Count(labels) != 1 {
+		t.Errorf("suppressed error should not be counted, got %d", sink.Count(labels))
+	}
+
+	// 同一错误多次检查只计一次命中，不同的错误分别计数
+	IsSuppressed(New("UPSTREAM_TIMEOUT", "another timeout"))
+	active := ActiveSuppressions()
+	if len(active) != 1 || active[0].Reason != "gateway maintenance" || active[0].Hits != 2 {
+		t.Errorf("ActiveSuppressions() = %+v", active)
+	}
+
+	// 窗口到期后自动失效
+	time.Sleep(60 * time.Millisecond)
+	if IsSuppressed(err) {
+		t.Error("suppression should expire after window")
+	}
+	if len(ActiveSuppressions()) != 0 {
+		t.Error("expired suppression should not be listed")
+	}
+	if got := EffectiveSeverity(err); got != SeverityHigh {
+		t.Errorf("EffectiveSeverity() after expiry = %v, want %v", got, SeverityHigh)
+	}
+}
+
+func TestSuppress_CountedOncePerError(t *testing.T) {
+	defer Unsuppress(CodeDatabaseError)
+	SetReportHooks(NewReportHooks(SeverityHigh, ReporterFunc(func(context.Context, *Error) {})))
+	defer SetReportHooks(nil)
+	Suppress(CodeDatabaseError, time.Minute)
+
+	// 同时经过指标与上报
+	handler := NewHTTPMiddleware(HTTPMiddlewareOptions{EmitMetrics: true})(HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		return FromType(DatabaseError)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if active := ActiveSuppressions(); len(active) != 1 || active[0].Hits != 1 {
+		t.Errorf("ActiveSuppressions() = %+v, want one hit", active)
+	}
+}
+
+func TestSuppress_Concurrent(t *testing.T) {
+	defer Unsuppress("BURST")
+	Suppress("BURST", time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			IsSuppressed(New("BURST", "x"))
+			ActiveSuppressions()
+		}()
+	}
+	wg.Wait()
+	if active := ActiveSuppressions(); len(active) != 1 || active[0].Hits != 50 {
+		t.Errorf("ActiveSuppressions() = %+v, want 50 hits", active)
+	}
+}
+
+func TestUnsuppress(t *testing.T) {
+	Suppress("FLAKY", time.Minute)
+	Unsuppress("FLAKY")
+	if IsSuppressed(New("FLAKY", "flaky")) {
+		t.Error("Unsuppress should remove the rule immediately")
+	}
+	if IsSuppressed(nil) {
+		t.Error("nil error should never be suppressed")
+	}
+}
@@ -0,0 +1,288 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlacklistStore 令牌黑名单存储接口
+// 默认使用进程内存储；多实例部署时应使用 Redis、数据库等共享存储，保证撤销在所有实例上生效
+type BlacklistStore interface {
+	// Get 返回令牌在黑名单中的过期时间，不存在时 found 为 false
+	Get(ctx context.Context, token string) (expireAt time.Time, found bool, err error)
+	// Set 将令牌加入黑名单，expireAt 之后条目可被清理
+	Set(ctx context.Context, token string, expireAt time.Time) error
+	// Delete 从黑名单中移除令牌
+	Delete(ctx context.Context, token string) error
+	// Cleanup 清理已过期的条目，返回清理数量；自带过期机制的存储可直接返回 0
+	Cleanup(ctx context.Context) (int, error)
+}
+
+// BlacklistSizer 可选接口，支持统计条目数量的存储实现它以支持 GetBlacklistSize
+type BlacklistSizer interface {
+	Len(ctx context.Context) (int, error)
+}
+
+// hashToken 返回令牌的 SHA-256 十六进制摘要，外部存储只保存摘要，避免泄露令牌原文
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// memoryBlacklistShards 内存黑名单的分片数量
+const memoryBlacklistShards = 16
+
+// memoryBlacklistShard 单个分片，独立加锁
+type memoryBlacklistShard struct {
+	mu    sync.RWMutex
+	items map[string]time.Time
+}
+
+// MemoryBlacklistStore 进程内黑名单存储（默认实现）
+// 按令牌哈希分片，每个分片拥有独立的 map 和锁
+type MemoryBlacklistStore struct {
+	shards [memoryBlacklistShards]*memoryBlacklistShard
+}
+
+// NewMemoryBlacklistStore 创建进程内黑名单存储
+func NewMemoryBlacklistStore() *MemoryBlacklistStore {
+	s := &MemoryBlacklistStore{}
+	for i := range s.shards {
+		s.shards[i] = &memoryBlacklistShard{items: make(map[string]time.Time)}
+	}
+	return s
+}
+
+// shard 使用 FNV-1a 哈希整个令牌定位分片
+func (s *MemoryBlacklistStore) shard(token string) *memoryBlacklistShard {
+	var h uint32 = 2166136261
+	for i := 0; i < len(token); i++ {
+		h ^= uint32(token[i])
+		h *= 16777619
+	}
+	return s.shards[h%memoryBlacklistShards]
+}
+
+// Get 实现 BlacklistStore 接口
+func (s *MemoryBlacklistStore) Get(_ context.Context, token string) (time.Time, bool, error) {
+	sh := s.shard(token)
+	sh.mu.RLock()
+	expireAt, ok := sh.items[token]
+	sh.mu.RUnlock()
+	return expireAt, ok, nil
+}
+
+// Set 实现 BlacklistStore 接口
+func (s *MemoryBlacklistStore) Set(_ context.Context, token string, expireAt time.Time) error {
+	sh := s.shard(token)
+	sh.mu.Lock()
+	sh.items[token] = expireAt
+	sh.mu.Unlock()
+	return nil
+}
+
+// Delete 实现 BlacklistStore 接口
+func (s *MemoryBlacklistStore) Delete(_ context.Context, token string) error {
+	sh := s.shard(token)
+	sh.mu.Lock()
+	delete(sh.items, token)
+	sh.mu.Unlock()
+	return nil
+}
+
+// Cleanup 实现 BlacklistStore 接口，逐个分片清理以减少锁持有时间
+func (s *MemoryBlacklistStore) Cleanup(_ context.Context) (int, error) {
+	now := time.Now()
+	cleaned := 0
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		for token, expireAt := range sh.items {
+			if now.After(expireAt) {
+				delete(sh.items, token)
+				cleaned++
+			}
+		}
+		sh.mu.Unlock()
+	}
+	return cleaned, nil
+}
+
+// Len 实现 BlacklistSizer 接口
+func (s *MemoryBlacklistStore) Len(_ context.Context) (int, error) {
+	total := 0
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		total += len(sh.items)
+		sh.mu.RUnlock()
+	}
+	return total, nil
+}
+
+// RedisClient RedisBlacklistStore 所需的最小 Redis 客户端接口
+// 不直接依赖具体的 Redis 库，可用几行代码适配 go-redis 等客户端：
+//
+//	type redisAdapter struct{ c *redis.Client }
+//
+//	func (a redisAdapter) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+//		return a.c.Set(ctx, key, value, ttl).Err()
+//	}
+//	func (a redisAdapter) Get(ctx context.Context, key string) (string, bool, error) {
+//		v, err := a.c.Get(ctx, key).Result()
+//		if err == redis.Nil {
+//			return "", false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//	func (a redisAdapter) Del(ctx context.Context, key string) error {
+//		return a.c.Del(ctx, key).Err()
+//	}
+type RedisClient interface {
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	Del(ctx context.Context, key string) error
+}
+
+// RedisBlacklistStore 基于 Redis 的黑名单存储
+// 键为 prefix + SHA-256(令牌)，值为过期时间（Unix 秒），并设置同样的 TTL，由 Redis 自动清理
+type RedisBlacklistStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisBlacklistStore 创建 Redis 黑名单存储，prefix 为空时使用 "jwt:blacklist:"
+func NewRedisBlacklistStore(client RedisClient, prefix string) *RedisBlacklistStore {
+	if prefix == "" {
+		prefix = "jwt:blacklist:"
+	}
+	return &RedisBlacklistStore{client: client, prefix: prefix}
+}
+
+func (s *RedisBlacklistStore) key(token string) string {
+	return s.prefix + hashToken(token)
+}
+
+// Get 实现 BlacklistStore 接口
+func (s *RedisBlacklistStore) Get(ctx context.Context, token string) (time.Time, bool, error) {
+	value, found, err := s.client.Get(ctx, s.key(token))
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid blacklist value %q: %w", value, err)
+	}
+	return time.Unix(unix, 0), true, nil
+}
+
+// Set 实现 BlacklistStore 接口
+func (s *RedisBlacklistStore) Set(ctx context.Context, token string, expireAt time.Time) error {
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, s.key(token), strconv.FormatInt(expireAt.Unix(), 10), ttl)
+}
+
+// Delete 实现 BlacklistStore 接口
+func (s *RedisBlacklistStore) Delete(ctx context.Context, token string) error {
+	return s.client.Del(ctx, s.key(token))
+}
+
+// Cleanup 实现 BlacklistStore 接口，过期由 Redis TTL 处理
+func (s *RedisBlacklistStore) Cleanup(context.Context) (int, error) {
+	return 0, nil
+}
+
+// sqlIdentifier 限制表名只能包含字母、数字、下划线和点（schema.table）
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLBlacklistStore 基于 database/sql 的黑名单存储，SQL 语法面向 PostgreSQL
+// 使用 pgx 时可通过 github.com/jackc/pgx/v5/stdlib 获得 *sql.DB。
+// 表结构见 CreateTable，只保存令牌的 SHA-256 摘要。
+type SQLBlacklistStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLBlacklistStore 创建数据库黑名单存储，table 为空时使用 "jwt_blacklist"
+func NewSQLBlacklistStore(db *sql.DB, table string) (*SQLBlacklistStore, error) {
+	if db == nil {
+		return nil, errors.New("db cannot be nil")
+	}
+	if table == "" {
+		table = "jwt_blacklist"
+	}
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	return &SQLBlacklistStore{db: db, table: table}, nil
+}
+
+// CreateTable 创建黑名单表及过期时间索引（已存在时跳过）
+func (s *SQLBlacklistStore) CreateTable(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	token_hash CHAR(64) PRIMARY KEY,
+	expires_at TIMESTAMPTZ NOT NULL
+)`); err != nil {
+		return err
+	}
+	index := strings.ReplaceAll(s.table, ".", "_") + "_expires_at_idx"
+	_, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS `+index+` ON `+s.table+` (expires_at)`)
+	return err
+}
+
+// Get 实现 BlacklistStore 接口
+func (s *SQLBlacklistStore) Get(ctx context.Context, token string) (time.Time, bool, error) {
+	var expireAt time.Time
+	err := s.db.QueryRowContext(ctx,
+		`SELECT expires_at FROM `+s.table+` WHERE token_hash = $1`, hashToken(token),
+	).Scan(&expireAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return expireAt, true, nil
+}
+
+// Set 实现 BlacklistStore 接口
+func (s *SQLBlacklistStore) Set(ctx context.Context, token string, expireAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO `+s.table+` (token_hash, expires_at) VALUES ($1, $2)
+ON CONFLICT (token_hash) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		hashToken(token), expireAt)
+	return err
+}
+
+// Delete 实现 BlacklistStore 接口
+func (s *SQLBlacklistStore) Delete(ctx context.Context, token string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE token_hash = $1`, hashToken(token))
+	return err
+}
+
+// Cleanup 实现 BlacklistStore 接口
+func (s *SQLBlacklistStore) Cleanup(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Len 实现 BlacklistSizer 接口
+func (s *SQLBlacklistStore) Len(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+s.table).Scan(&n)
+	return n, err
+}
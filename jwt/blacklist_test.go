@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis 用于测试的内存 Redis 客户端
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+	ttls map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (r *fakeRedis) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[key] = value
+	r.ttls[key] = ttl
+	return nil
+}
+
+func (r *fakeRedis) Get(_ context.Context, key string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.data[key]
+	return v, ok, nil
+}
+
+func (r *fakeRedis) Del(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.data, key)
+	return nil
+}
+
+func TestMemoryBlacklistStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryBlacklistStore()
+
+	_ = store.Set(ctx, "active", time.Now().Add(time.Hour))
+	_ = store.Set(ctx, "expired", time.Now().Add(-time.Hour))
+
+	if _, found, _ := store.Get(ctx, "active"); !found {
+		t.Error("expected active token to be found")
+	}
+	if n, _ := store.Len(ctx); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+	if cleaned, _ := store.Cleanup(ctx); cleaned != 1 {
+		t.Errorf("Cleanup() = %d, want 1", cleaned)
+	}
+	_ = store.Delete(ctx, "active")
+	if n, _ := store.Len(ctx); n != 0 {
+		t.Errorf("Len() after Delete = %d, want 0", n)
+	}
+}
+
+func TestRedisBlacklistStore_SharedAcrossManagers(t *testing.T) {
+	redis := newFakeRedis()
+	newManager := func() *TokenManager {
+		opts := DefaultJWTOptions()
+		opts.BlacklistCleanInterval = 0
+		opts.BlacklistStore = NewRedisBlacklistStore(redis, "")
+		return MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+	}
+	instanceA, instanceB := newManager(), newManager()
+
+	token, err := instanceA.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// B 先验证一次，结果进入 B 的本地缓存
+	if _, err := instanceB.ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+
+	if err := instanceA.RevokeToken(token); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+
+	// 撤销对其他实例立即生效，即使结果已被缓存
+	if _, err := instanceB.ValidateToken(token); !errors.Is(err, ErrRevoked) {
+		t.Errorf("expected revoked error on other instance, got %v", err)
+	}
+
+	for key, ttl := range redis.ttls {
+		if !strings.HasPrefix(key, "jwt:blacklist:") || strings.Contains(key, token) {
+			t.Errorf("unexpected redis key %q", key)
+		}
+		if ttl <= 0 || ttl > instanceA.GetAccessTokenExpiry() {
+			t.Errorf("unexpected ttl %v", ttl)
+		}
+	}
+	if instanceA.GetBlacklistSize() != 0 {
+		t.Error("redis store does not support size and should report 0")
+	}
+}
+
+func TestNewSQLBlacklistStore_Validation(t *testing.T) {
+	if _, err := NewSQLBlacklistStore(nil, ""); err == nil {
+		t.Error("expected error for nil db")
+	}
+}
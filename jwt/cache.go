@@ -0,0 +1,29 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/iwen-conf/utils-pkg/cache"
+)
+
+// cacheShardCount 验证缓存的分片数量
+const cacheShardCount = 16
+
+// cacheItem 缓存的验证结果，验证失败的结果同样缓存，避免重复解析无效令牌
+type cacheItem struct {
+	claims *StandardClaims
+	err    error
+}
+
+// validationCache 令牌验证结果的分片LRU缓存
+type validationCache = cache.Cache[string, cacheItem]
+
+// newValidationCache 创建验证结果缓存，size为总容量
+// 令牌按哈希分散到各个分片，每个分片独立加锁，淘汰操作为O(1)
+func newValidationCache(size int, ttl time.Duration) *validationCache {
+	return cache.New[string, cacheItem](cache.Options{
+		Capacity: size,
+		TTL:      ttl,
+		Shards:   cacheShardCount,
+	})
+}
@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenManager_CacheConcurrentValidation(t *testing.T) {
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", DefaultJWTOptions())
+	defer manager.Shutdown()
+
+	token, err := manager.GenerateToken("user-1")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.ValidateToken(token); err != nil {
+				t.Errorf("ValidateToken() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 同一令牌的并发验证只解析一次
+	if stats := manager.GetCacheStats(); stats.Loads != 1 {
+		t.Errorf("token parsed %d times, want 1", stats.Loads)
+	}
+
+	// 验证失败的结果同样缓存
+	for i := 0; i < 3; i++ {
+		if _, err := manager.ValidateToken("invalid.token.value"); err == nil {
+			t.Fatal("expected validation error")
+		}
+	}
+	if stats := manager.GetCacheStats(); stats.Loads != 2 {
+		t.Errorf("Loads = %d, want 2", stats.Loads)
+	}
+}
+
+// flakyBlacklistStore 在 failing 为 true 时模拟存储故障
+type flakyBlacklistStore struct {
+	BlacklistStore
+	failing atomic.Bool
+}
+
+func (s *flakyBlacklistStore) Get(ctx context.Context, token string) (time.Time, bool, error) {
+	if s.failing.Load() {
+		return time.Time{}, false, errors.New("connection refused")
+	}
+	return s.BlacklistStore.Get(ctx, token)
+}
+
+func TestTokenManager_CacheSkipsStoreErrors(t *testing.T) {
+	store := &flakyBlacklistStore{BlacklistStore: NewMemoryBlacklistStore()}
+	opts := DefaultJWTOptions()
+	opts.BlacklistStore = store
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+	defer manager.Shutdown()
+
+	token, _ := manager.GenerateToken("user-1")
+	store.failing.Store(true)
+	if _, err := manager.ValidateToken(token); err == nil || isTokenError(err) {
+		t.Fatalf("expected store error, got %v", err)
+	}
+
+	// 存储恢复后有效令牌立即可用，故障期间的错误没有被缓存
+	store.failing.Store(false)
+	if _, err := manager.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken() after store recovery error = %v", err)
+	}
+}
+
+func TestTokenManager_CacheDisabledByZeroTTL(t *testing.T) {
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", DefaultJWTOptions())
+	defer manager.Shutdown()
+	manager.SetCacheTTL(0)
+
+	token, _ := manager.GenerateToken("user-1")
+	for i := 0; i < 3; i++ {
+		if _, err := manager.ValidateToken(token); err != nil {
+			t.Fatalf("ValidateToken() error = %v", err)
+		}
+	}
+	if size := manager.GetCacheSize(); size != 0 {
+		t.Errorf("cache size = %d, results should not be cached when TTL is 0", size)
+	}
+}
+
+func TestTokenManager_CacheSizeLimit(t *testing.T) {
+	opts := DefaultJWTOptions()
+	opts.CacheSize = 32
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+	defer manager.Shutdown()
+
+	for i := 0; i < 200; i++ {
+		token, err := manager.GenerateToken(fmt.Sprintf("user-%d", i))
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		if _, err := manager.ValidateToken(token); err != nil {
+			t.Fatalf("Failed to validate token: %v", err)
+		}
+	}
+
+	if size := manager.GetCacheSize(); size > 32+cacheShardCount {
+		t.Errorf("cache size = %d, should stay near configured limit 32", size)
+	}
+}
+
+func BenchmarkTokenManager_ValidateToken_CacheChurn(b *testing.B) {
+	opts := DefaultJWTOptions()
+	opts.CacheSize = 100
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+	defer manager.Shutdown()
+
+	tokens := make([]string, 1000)
+	for i := range tokens {
+		tokens[i], _ = manager.GenerateToken(fmt.Sprintf("user-%d", i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = manager.ValidateToken(tokens[i%len(tokens)])
+	}
+}
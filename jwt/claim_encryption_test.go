@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodePayload(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("failed to parse payload: %v", err)
+	}
+	return payload
+}
+
+func TestTokenManager_CustomClaims(t *testing.T) {
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!")
+	defer manager.Shutdown()
+
+	token, err := manager.GenerateToken("user-1", &TokenOptions{
+		CustomClaims: map[string]interface{}{"department": "engineering", "sub": "ignored"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := decodePayload(t, token)
+	if payload["department"] != "engineering" || payload["sub"] != "user-1" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := claims.GetCustomClaim("department"); !ok || v != "engineering" {
+		t.Errorf("GetCustomClaim(department) = %v, %v", v, ok)
+	}
+	if _, ok := claims.Custom["exp"]; ok {
+		t.Error("standard claims should not appear in Custom")
+	}
+}
+
+func TestTokenManager_ClaimEncryption(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!")
+	defer manager.Shutdown()
+
+	if err := manager.SetClaimEncryption(key, "email", "phone"); err != nil {
+		t.Fatalf("SetClaimEncryption() error = %v", err)
+	}
+
+	token, err := manager.GenerateToken("user-1", &TokenOptions{
+		CustomClaims: map[string]interface{}{
+			"email":  "alice@example.com",
+			"phone":  "13800000000",
+			"tenant": "acme",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := decodePayload(t, token)
+	if payload["tenant"] != "acme" {
+		t.Errorf("routing claim should stay plaintext, got %v", payload["tenant"])
+	}
+	for _, k := range []string{"email", "phone"} {
+		s, _ := payload[k].(string)
+		if !strings.HasPrefix(s, encryptedClaimPrefix) || strings.Contains(s, "alice") {
+			t.Errorf("claim %s should be encrypted in the token, got %v", k, payload[k])
+		}
+	}
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Custom["email"] != "alice@example.com" || claims.Custom["phone"] != "13800000000" {
+		t.Errorf("claims not decrypted: %v", claims.Custom)
+	}
+
+	// 不持有密钥的管理器只能看到密文
+	other := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!")
+	defer other.Shutdown()
+	claims, err = other.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := claims.Custom["email"].(string); !strings.HasPrefix(s, encryptedClaimPrefix) {
+		t.Errorf("manager without key should see ciphertext, got %v", claims.Custom["email"])
+	}
+
+	// 密钥不匹配时验证失败
+	if err := other.SetClaimEncryption([]byte("fedcba9876543210fedcba9876543210"), "email"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.ValidateToken(token); err == nil {
+		t.Error("expected error when decrypting with the wrong key")
+	}
+}
+
+func TestSetClaimEncryption_Errors(t *testing.T) {
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!")
+	defer manager.Shutdown()
+
+	if err := manager.SetClaimEncryption([]byte("short"), "email"); err == nil {
+		t.Error("expected error for invalid key size")
+	}
+	if err := manager.SetClaimEncryption(make([]byte, 32)); err == nil {
+		t.Error("expected error when no claims are specified")
+	}
+	if err := manager.SetClaimEncryption(make([]byte, 32), "sub"); err == nil {
+		t.Error("expected error for standard claim")
+	}
+	if err := manager.SetClaimEncryption(nil); err != nil {
+		t.Errorf("disabling encryption should succeed, got %v", err)
+	}
+}
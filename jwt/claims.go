@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"encoding/json"
+)
+
+// reservedClaims 标准声明使用的键，自定义声明不能覆盖
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true,
+	"iat": true, "jti": true, "type": true, "sid": true,
+}
+
+// standardClaimsJSON 用于序列化标准字段，避免递归调用 MarshalJSON
+type standardClaimsJSON StandardClaims
+
+// MarshalJSON 将自定义声明与标准声明平铺到同一个 JSON 对象中
+func (c StandardClaims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(standardClaimsJSON(c))
+	if err != nil || len(c.Custom) == 0 {
+		return data, err
+	}
+
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range c.Custom {
+		if !reservedClaims[k] {
+			merged[k] = v
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON 解析标准声明，其余键全部放入 Custom
+func (c *StandardClaims) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*standardClaimsJSON)(c)); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	c.Custom = nil
+	for k, v := range all {
+		if reservedClaims[k] {
+			continue
+		}
+		if c.Custom == nil {
+			c.Custom = make(map[string]interface{})
+		}
+		c.Custom[k] = v
+	}
+	return nil
+}
+
+// GetCustomClaim 读取自定义声明
+func (c *StandardClaims) GetCustomClaim(key string) (interface{}, bool) {
+	if c == nil || c.Custom == nil {
+		return nil, false
+	}
+	v, ok := c.Custom[key]
+	return v, ok
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// claimsContextKey 上下文键类型，未导出以避免与其他包冲突
+type claimsContextKey struct{}
+
+// ContextWithClaims 返回携带令牌声明的上下文，通常在认证中间件中调用
+func ContextWithClaims(ctx context.Context, claims *StandardClaims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext 从上下文读取令牌声明
+func ClaimsFromContext(ctx context.Context) (*StandardClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*StandardClaims)
+	return claims, ok && claims != nil
+}
+
+// SubjectFromContext 读取上下文中令牌的主题（用户标识）
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// SessionIDFromContext 读取上下文中令牌的会话 ID
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.SessionID == "" {
+		return "", false
+	}
+	return claims.SessionID, true
+}
+
+// TokenTypeFromContext 读取上下文中令牌的类型
+func TokenTypeFromContext(ctx context.Context) (TokenType, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.TokenType == "" {
+		return "", false
+	}
+	return claims.TokenType, true
+}
+
+// CustomClaim 读取上下文中令牌的自定义声明并转换为 T
+// 令牌解析后数字为 float64、对象为 map[string]interface{}，类型不能直接断言时会经 JSON 转换，
+// 因此 CustomClaim[int]、CustomClaim[[]string] 或 CustomClaim[MyStruct] 都可以直接使用。
+// 声明不存在或无法转换时返回 false。
+func CustomClaim[T any](ctx context.Context, key string) (T, bool) {
+	var zero T
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return zero, false
+	}
+	raw, ok := claims.GetCustomClaim(key)
+	if !ok || raw == nil {
+		return zero, false
+	}
+	if v, ok := raw.(T); ok {
+		return v, true
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return zero, false
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, false
+	}
+	return v, true
+}
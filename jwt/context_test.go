@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClaimsContext(t *testing.T) {
+	manager := MustNewTokenManager(testSecret)
+	defer manager.Shutdown()
+
+	token, err := manager.GenerateToken("user-1", &TokenOptions{
+		TokenType: AccessToken,
+		SessionID: "sess-1",
+		CustomClaims: map[string]interface{}{
+			"tenant_id": 42,
+			"roles":     []string{"admin", "viewer"},
+			"profile":   map[string]interface{}{"name": "alice"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ContextWithClaims(context.Background(), claims)
+
+	if got, ok := ClaimsFromContext(ctx); !ok || got != claims {
+		t.Error("ClaimsFromContext() did not return the stored claims")
+	}
+	if sub, ok := SubjectFromContext(ctx); !ok || sub != "user-1" {
+		t.Errorf("SubjectFromContext() = %q, %v", sub, ok)
+	}
+	if sid, ok := SessionIDFromContext(ctx); !ok || sid != "sess-1" {
+		t.Errorf("SessionIDFromContext() = %q, %v", sid, ok)
+	}
+	if typ, ok := TokenTypeFromContext(ctx); !ok || typ != AccessToken {
+		t.Errorf("TokenTypeFromContext() = %q, %v", typ, ok)
+	}
+
+	if tenant, ok := CustomClaim[int](ctx, "tenant_id"); !ok || tenant != 42 {
+		t.Errorf("CustomClaim[int]() = %d, %v", tenant, ok)
+	}
+	if roles, ok := CustomClaim[[]string](ctx, "roles"); !ok || len(roles) != 2 || roles[0] != "admin" {
+		t.Errorf("CustomClaim[[]string]() = %v, %v", roles, ok)
+	}
+	type profile struct {
+		Name string `json:"name"`
+	}
+	if p, ok := CustomClaim[profile](ctx, "profile"); !ok || p.Name != "alice" {
+		t.Errorf("CustomClaim[profile]() = %+v, %v", p, ok)
+	}
+	if _, ok := CustomClaim[int](ctx, "roles"); ok {
+		t.Error("CustomClaim[int] should fail for a list claim")
+	}
+	if _, ok := CustomClaim[string](ctx, "missing"); ok {
+		t.Error("CustomClaim should report missing claims")
+	}
+}
+
+func TestClaimsContext_Empty(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := ClaimsFromContext(ctx); ok {
+		t.Error("expected no claims in empty context")
+	}
+	if _, ok := SubjectFromContext(ctx); ok {
+		t.Error("expected no subject in empty context")
+	}
+	if _, ok := CustomClaim[string](ctx, "k"); ok {
+		t.Error("expected no custom claim in empty context")
+	}
+	if _, ok := ClaimsFromContext(ContextWithClaims(ctx, nil)); ok {
+		t.Error("nil claims should not be reported as present")
+	}
+}
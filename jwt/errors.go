@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+// 令牌校验的哨兵错误，使用 errors.Is 判断，不要匹配错误文本
+// ValidateToken 等方法返回的是 *errors.Error，其错误链中包含下列哨兵错误之一
+var (
+	// ErrRevoked 令牌已被撤销
+	ErrRevoked = errors.New("token has been revoked")
+	// ErrExpired 令牌已过期
+	ErrExpired = errors.New("token has expired")
+	// ErrNotYetValid 令牌尚未生效（nbf 在未来）
+	ErrNotYetValid = errors.New("token is not valid yet")
+	// ErrMalformed 令牌格式错误或声明无法解析
+	ErrMalformed = errors.New("token is malformed")
+	// ErrSignature 签名无效或签名算法不符
+	ErrSignature = errors.New("token signature is invalid")
+	// ErrWrongType 令牌类型不符，例如用访问令牌刷新
+	ErrWrongType = errors.New("token type is not allowed here")
+	// ErrInvalid 其他原因导致的无效令牌
+	ErrInvalid = errors.New("token is invalid")
+	// ErrStoreUnavailable 令牌存储读写失败，不代表令牌本身无效
+	ErrStoreUnavailable = errors.New("token store is unavailable")
+)
+
+// 令牌错误码，过期与通用无效沿用 errors 包中的 CodeExpiredToken 与 CodeInvalidToken
+const (
+	CodeTokenRevoked     = "TOKEN_REVOKED"
+	CodeTokenNotYetValid = "TOKEN_NOT_YET_VALID"
+	CodeTokenMalformed   = "TOKEN_MALFORMED"
+	CodeTokenSignature   = "TOKEN_SIGNATURE_INVALID"
+	CodeTokenWrongType   = "TOKEN_WRONG_TYPE"
+	CodeTokenStore       = "TOKEN_STORE_UNAVAILABLE"
+)
+
+// tokenErrorSpec 哨兵错误对应的错误码、消息与 HTTP 状态码
+type tokenErrorSpec struct {
+	code   string
+	zh, en string
+	status int
+}
+
+var tokenErrorSpecs = map[error]tokenErrorSpec{
+	ErrRevoked:          {CodeTokenRevoked, "令牌已被撤销", "Token has been revoked", http.StatusUnauthorized},
+	ErrExpired:          {apperrors.CodeExpiredToken, "令牌已过期", "Token has expired", http.StatusUnauthorized},
+	ErrNotYetValid:      {CodeTokenNotYetValid, "令牌尚未生效", "Token is not valid yet", http.StatusUnauthorized},
+	ErrMalformed:        {CodeTokenMalformed, "令牌格式错误", "Token is malformed", http.StatusUnauthorized},
+	ErrSignature:        {CodeTokenSignature, "令牌签名无效", "Token signature is invalid", http.StatusUnauthorized},
+	ErrWrongType:        {CodeTokenWrongType, "令牌类型不允许", "Token type is not allowed here", http.StatusForbidden},
+	ErrInvalid:          {apperrors.CodeInvalidToken, "令牌无效", "Token is invalid", http.StatusUnauthorized},
+	ErrStoreUnavailable: {CodeTokenStore, "令牌存储不可用", "Token store is unavailable", http.StatusServiceUnavailable},
+}
+
+func init() {
+	// 注册令牌错误码，使其在 errors 包的严格模式下视为合法，
+	// 并登记状态码，使 errors.WriteError 等与 HTTPStatus 返回相同的状态码
+	for _, spec := range tokenErrorSpecs {
+		severity := apperrors.SeverityMedium
+		if spec.status >= http.StatusInternalServerError {
+			severity = apperrors.SeverityHigh
+		}
+		apperrors.RegisterHTTPStatus(spec.code, spec.status)
+		apperrors.RegisterErrorType(apperrors.ErrorType{
+			Code:      spec.code,
+			Message:   spec.zh,
+			MessageEN: spec.en,
+			Severity:  severity,
+			Category:  apperrors.CategoryAuth,
+		})
+	}
+}
+
+// tokenError 构造带错误码和中英文消息的令牌错误，错误链依次包含哨兵错误和底层原因
+func tokenError(sentinel, cause error) *apperrors.Error {
+	spec := tokenErrorSpecs[sentinel]
+	original := sentinel
+	if cause != nil {
+		original = fmt.Errorf("%w: %w", sentinel, cause)
+	}
+	return apperrors.Wrap(original, spec.code, spec.zh).
+		WithMessages(spec.zh, spec.en).
+		WithContext("category", apperrors.CategoryAuth)
+}
+
+// classifyParseError 将 golang-jwt 的解析错误映射为令牌哨兵错误
+func classifyParseError(err error) *apperrors.Error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return tokenError(ErrExpired, err)
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return tokenError(ErrNotYetValid, err)
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return tokenError(ErrMalformed, err)
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return tokenError(ErrSignature, err)
+	default:
+		return tokenError(ErrInvalid, err)
+	}
+}
+
+// isTokenError 判断错误是否为令牌本身的校验结论（错误链中包含令牌哨兵错误），存储故障不算
+func isTokenError(err error) bool {
+	if errors.Is(err, ErrStoreUnavailable) {
+		return false
+	}
+	for sentinel := range tokenErrorSpecs {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorCode 返回令牌错误的错误码，非令牌错误返回空字符串
+func ErrorCode(err error) string {
+	var e *apperrors.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// HTTPStatus 返回令牌错误对应的 HTTP 状态码：
+// 令牌类型不符返回 403，令牌存储不可用返回 503，其余令牌错误返回 401，非令牌错误返回 500
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	for sentinel, spec := range tokenErrorSpecs {
+		if errors.Is(err, sentinel) {
+			return spec.status
+		}
+	}
+	return http.StatusInternalServerError
+}
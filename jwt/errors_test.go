@@ -0,0 +1,136 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+const testSecret = "this-is-a-very-secure-jwt-secret-key-32bytes!"
+
+func TestValidateToken_StructuredErrors(t *testing.T) {
+	manager := MustNewTokenManager(testSecret)
+	defer manager.Shutdown()
+
+	expired, _ := manager.GenerateToken("user", &TokenOptions{TokenType: AccessToken, ExpiresIn: time.Nanosecond})
+	time.Sleep(time.Second)
+
+	valid, _ := manager.GenerateToken("user")
+	tampered := valid[:len(valid)-2] + "xx"
+
+	revoked, _ := manager.GenerateToken("user")
+	if err := manager.RevokeToken(revoked); err != nil {
+		t.Fatal(err)
+	}
+
+	other := MustNewTokenManager("another-very-secure-jwt-secret-key-32bytes!")
+	defer other.Shutdown()
+	foreign, _ := other.GenerateToken("user")
+
+	tests := []struct {
+		name     string
+		token    string
+		sentinel error
+		code     string
+		status   int
+	}{
+		{"expired", expired, ErrExpired, apperrors.CodeExpiredToken, http.StatusUnauthorized},
+		{"tampered", tampered, ErrSignature, CodeTokenSignature, http.StatusUnauthorized},
+		{"foreign key", foreign, ErrSignature, CodeTokenSignature, http.StatusUnauthorized},
+		{"malformed", "not-a-token", ErrMalformed, CodeTokenMalformed, http.StatusUnauthorized},
+		{"bad segments", "aaaa.bbbb.cccc", ErrMalformed, CodeTokenMalformed, http.StatusUnauthorized},
+		{"revoked", revoked, ErrRevoked, CodeTokenRevoked, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.ValidateToken(tt.token)
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("ValidateToken() error = %v, want %v", err, tt.sentinel)
+			}
+			if code := ErrorCode(err); code != tt.code {
+				t.Errorf("ErrorCode() = %s, want %s", code, tt.code)
+			}
+			if status := HTTPStatus(err); status != tt.status {
+				t.Errorf("HTTPStatus() = %d, want %d", status, tt.status)
+			}
+		})
+	}
+
+	// 底层 golang-jwt 错误仍保留在错误链中
+	_, err := manager.ValidateToken(expired)
+	if !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("expected jwt.ErrTokenExpired in chain, got %v", err)
+	}
+}
+
+func TestRefreshToken_WrongType(t *testing.T) {
+	manager := MustNewTokenManager(testSecret)
+	defer manager.Shutdown()
+
+	access, _ := manager.GenerateToken("user")
+	_, _, err := manager.RefreshToken(access)
+	if !errors.Is(err, ErrWrongType) {
+		t.Fatalf("RefreshToken() error = %v, want ErrWrongType", err)
+	}
+	if HTTPStatus(err) != http.StatusForbidden {
+		t.Errorf("HTTPStatus() = %d, want 403", HTTPStatus(err))
+	}
+	// errors 包的响应写入使用相同的状态码
+	if status := apperrors.HTTPStatus(err); status != http.StatusForbidden {
+		t.Errorf("errors.HTTPStatus() = %d, want 403", status)
+	}
+	rec := httptest.NewRecorder()
+	apperrors.WriteError(rec, httptest.NewRequest(http.MethodPost, "/refresh", nil), err)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("WriteError status = %d, want 403", rec.Code)
+	}
+
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || appErr.LocalizedMessage(apperrors.LocaleEN) != "Token type is not allowed here" {
+		t.Errorf("expected localized *errors.Error, got %v", err)
+	}
+}
+
+func TestValidateToken_StrictMode(t *testing.T) {
+	apperrors.SetStrictMode(true)
+	defer apperrors.SetStrictMode(false)
+
+	manager := MustNewTokenManager(testSecret)
+	defer manager.Shutdown()
+
+	access, _ := manager.GenerateToken("user")
+	revoked, _ := manager.GenerateToken("user")
+	_ = manager.RevokeToken(revoked)
+	other := MustNewTokenManager("another-very-secure-jwt-secret-key-32bytes!")
+	defer other.Shutdown()
+	foreign, _ := other.GenerateToken("user")
+
+	// 严格模式下令牌错误码均已注册，构造错误不会 panic
+	for _, token := range []string{"garbage.token.x", "not-a-token", revoked, foreign} {
+		if _, err := manager.ValidateToken(token); err == nil {
+			t.Errorf("ValidateToken(%q) should fail", token)
+		}
+	}
+	if _, _, err := manager.RefreshToken(access); !errors.Is(err, ErrWrongType) {
+		t.Errorf("RefreshToken() error = %v, want ErrWrongType", err)
+	}
+}
+
+func TestHTTPStatus_NonTokenError(t *testing.T) {
+	if HTTPStatus(nil) != http.StatusOK {
+		t.Error("HTTPStatus(nil) should be 200")
+	}
+	if HTTPStatus(errors.New("boom")) != http.StatusInternalServerError {
+		t.Error("HTTPStatus(other) should be 500")
+	}
+	if ErrorCode(errors.New("boom")) != "" {
+		t.Error("ErrorCode(other) should be empty")
+	}
+}
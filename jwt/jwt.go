@@ -1,13 +1,16 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/iwen-conf/utils-pkg/cache"
 )
 
 // TokenType 定义令牌类型
@@ -31,6 +34,8 @@ type StandardClaims struct {
 	SessionID string `json:"sid,omitempty"`
 	// 令牌ID
 	TokenID string `json:"jti,omitempty"`
+	// 自定义声明，序列化时与标准声明平铺在同一层级
+	Custom map[string]interface{} `json:"-"`
 }
 
 // TokenOptions JWT令牌选项
@@ -71,6 +76,21 @@ type JWTOptions struct {
 	AccessTokenExpiry time.Duration
 	// 刷新令牌默认过期时间
 	RefreshTokenExpiry time.Duration
+	// 黑名单存储，为 nil 时使用进程内存储；多实例部署时应使用共享存储
+	BlacklistStore BlacklistStore
+	// HMAC 签名算法（HS256/HS384/HS512），为 nil 时使用 HS256
+	// 非对称算法请使用 NewTokenManagerWithKeys
+	SigningMethod jwt.SigningMethod
+	// 不透明令牌存储，启用 TokenModeOpaque 时必须设置
+	OpaqueTokenStore OpaqueTokenStore
+	// 各令牌类型的签发模式，未设置的类型使用 TokenModeJWT
+	TokenModes map[TokenType]TokenMode
+	// 为每种令牌类型从主密钥派生独立的 HMAC 签名密钥（HKDF），仅适用于 NewTokenManager
+	DeriveTypeKeys bool
+	// 启用派生密钥后仍接受主密钥签名的旧令牌，用于平滑迁移
+	AcceptLegacyTokens bool
+	// 令牌重放检测，为 nil 时不记录使用情况；仅对 ValidateTokenFrom 生效
+	ReplayDetector *ReplayDetector
 }
 
 // DefaultJWTOptions 返回默认的JWT管理器选项
@@ -86,27 +106,36 @@ func DefaultJWTOptions() *JWTOptions {
 	}
 }
 
-// cacheItem 缓存项结构
-type cacheItem struct {
-	claims    *StandardClaims
-	err       error
-	timestamp time.Time
-}
-
 // TokenManager JWT 令牌管理器
 type TokenManager struct {
 	secretKey []byte
-	// token 黑名单 - 使用分段锁减少竞争
-	blacklist         map[string]time.Time
-	blacklistLock     []*sync.RWMutex // 分段锁数组
-	blacklistSegments int             // 分段数量
-
-	// 令牌验证结果缓存
-	cache     map[string]cacheItem
-	cacheLock sync.RWMutex
+	// 签名算法与密钥：HMAC 时均为 secretKey，RSA/ECDSA 时分别为私钥和公钥
+	signingMethod jwt.SigningMethod
+	signKey       interface{}
+	verifyKey     interface{}
+	// token 黑名单存储
+	blacklist BlacklistStore
+
+	// 令牌验证结果缓存（分片LRU）
+	cache     *validationCache
 	cacheSize int
 	cacheTTL  time.Duration
 
+	// 自定义声明加密，未启用时为 nil
+	claimEncryptor atomic.Pointer[claimEncryptor]
+
+	// 不透明令牌存储与各令牌类型的签发模式
+	opaqueStore  OpaqueTokenStore
+	tokenModes   map[TokenType]TokenMode
+	tokenModesMu sync.RWMutex
+
+	// 按令牌类型派生的签名密钥，未启用时为 nil
+	typeKeys           *typeKeys
+	acceptLegacyTokens atomic.Bool
+
+	// 令牌重放检测，未启用时为 nil
+	replayDetector *ReplayDetector
+
 	// 清理黑名单的定时器
 	cleanupTicker *time.Ticker
 	stopCleanup   chan struct{}
@@ -157,19 +186,45 @@ func NewTokenManager(secretKey string, options ...*JWTOptions) (*TokenManager, e
 		opts = options[0]
 	}
 
-	// 创建分段锁，减少并发写入的锁竞争
-	const numSegments = 16 // 16个分段
-	locks := make([]*sync.RWMutex, numSegments)
-	for i := 0; i < numSegments; i++ {
-		locks[i] = &sync.RWMutex{}
+	method := opts.SigningMethod
+	if method == nil {
+		method = jwt.SigningMethodHS256
+	}
+	hmacMethod, ok := method.(*jwt.SigningMethodHMAC)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s requires NewTokenManagerWithKeys", ErrKeyTypeMismatch, method.Alg())
+	}
+
+	var derivedKeys *typeKeys
+	if opts.DeriveTypeKeys {
+		var err error
+		if derivedKeys, err = newTypeKeys([]byte(secretKey), hmacMethod); err != nil {
+			return nil, err
+		}
+	}
+
+	manager := newTokenManager(opts, method, []byte(secretKey), []byte(secretKey))
+	manager.secretKey = []byte(secretKey)
+	if derivedKeys != nil {
+		manager.typeKeys = derivedKeys
+		manager.acceptLegacyTokens.Store(opts.AcceptLegacyTokens)
+	}
+	return manager, nil
+}
+
+// newTokenManager 按选项构建管理器并启动黑名单清理
+func newTokenManager(opts *JWTOptions, method jwt.SigningMethod, signKey, verifyKey interface{}) *TokenManager {
+	blacklist := opts.BlacklistStore
+	if blacklist == nil {
+		blacklist = NewMemoryBlacklistStore()
 	}
 
 	manager := &TokenManager{
-		secretKey:          []byte(secretKey),
-		blacklist:          make(map[string]time.Time),
-		blacklistLock:      locks,
-		blacklistSegments:  numSegments,
-		cache:              make(map[string]cacheItem),
+		signingMethod:      method,
+		signKey:            signKey,
+		verifyKey:          verifyKey,
+		blacklist:          blacklist,
+		cache:              newValidationCache(opts.CacheSize, opts.CacheTTL),
 		cacheSize:          opts.CacheSize,
 		cacheTTL:           opts.CacheTTL,
 		enableLog:          opts.EnableLog,
@@ -177,6 +232,14 @@ func NewTokenManager(secretKey string, options ...*JWTOptions) (*TokenManager, e
 		stopCleanup:        make(chan struct{}),
 		accessTokenExpiry:  opts.AccessTokenExpiry,
 		refreshTokenExpiry: opts.RefreshTokenExpiry,
+		opaqueStore:        opts.OpaqueTokenStore,
+		replayDetector:     opts.ReplayDetector,
+	}
+	if len(opts.TokenModes) > 0 {
+		manager.tokenModes = make(map[TokenType]TokenMode, len(opts.TokenModes))
+		for tokenType, mode := range opts.TokenModes {
+			manager.tokenModes[tokenType] = mode
+		}
 	}
 
 	// 启动黑名单自动清理
@@ -185,7 +248,7 @@ func NewTokenManager(secretKey string, options ...*JWTOptions) (*TokenManager, e
 		go manager.startCleanupRoutine()
 	}
 
-	return manager, nil
+	return manager
 }
 
 // MustNewTokenManager creates a new JWT token manager and panics on error
@@ -198,16 +261,6 @@ func MustNewTokenManager(secretKey string, options ...*JWTOptions) *TokenManager
 	return manager
 }
 
-// 获取令牌对应的锁索引
-func (m *TokenManager) getLockIndex(token string) int {
-	// 简单哈希函数，将令牌映射到锁索引
-	var sum int
-	for i := 0; i < len(token) && i < 10; i++ {
-		sum += int(token[i])
-	}
-	return sum % m.blacklistSegments
-}
-
 // startCleanupRoutine 启动黑名单自动清理例程
 func (m *TokenManager) startCleanupRoutine() {
 	for {
@@ -215,6 +268,8 @@ func (m *TokenManager) startCleanupRoutine() {
 		case <-m.cleanupTicker.C:
 			m.CleanBlacklist()
 			m.cleanCache() // 同时清理过期缓存
+			m.cleanUsageStore()
+			m.cleanOpaqueStore()
 		case <-m.stopCleanup:
 			m.cleanupTicker.Stop()
 			return
@@ -242,11 +297,13 @@ func (m *TokenManager) EnableCache(enable bool) {
 // SetCacheTTL 设置缓存过期时间
 func (m *TokenManager) SetCacheTTL(ttl time.Duration) {
 	m.cacheTTL = ttl
+	m.cache.SetTTL(ttl)
 }
 
 // SetCacheSize 设置缓存大小
 func (m *TokenManager) SetCacheSize(size int) {
 	m.cacheSize = size
+	m.cache.Resize(size)
 }
 
 // SetTokenExpiry 设置令牌过期时间
@@ -314,20 +371,50 @@ func (m *TokenManager) GenerateToken(subject string, options ...*TokenOptions) (
 		TokenID:   tokenID,
 	}
 
-	// 添加自定义声明
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	if opts.CustomClaims != nil {
-		for k, v := range opts.CustomClaims {
-			// 不能直接将StandardClaims转为MapClaims
-			// 使用RegisteredClaims的私有字段存储自定义声明
-			if mapClaims, ok := token.Claims.(jwt.MapClaims); ok {
-				mapClaims[k] = v
+	// 不透明模式下声明只保存在服务端，无需签名或加密
+	if m.TokenMode(tokenType) == TokenModeOpaque {
+		if m.opaqueStore == nil {
+			return "", ErrNoOpaqueStore
+		}
+		if len(opts.CustomClaims) > 0 {
+			claims.Custom = opts.CustomClaims
+		}
+		tokenStr, err := m.issueOpaqueToken(claims)
+		if err != nil {
+			m.logf("不透明令牌保存失败: %v", err)
+			return "", err
+		}
+		if m.enableLog {
+			m.logf("已生成%s不透明令牌，主题: %s, 过期时间: %v", tokenType, subject, expiresIn)
+		}
+		return tokenStr, nil
+	}
+
+	// 添加自定义声明，启用声明加密时加密指定字段
+	if len(opts.CustomClaims) > 0 {
+		claims.Custom = opts.CustomClaims
+		if enc := m.claimEncryptor.Load(); enc != nil {
+			encrypted, err := enc.encrypt(opts.CustomClaims)
+			if err != nil {
+				return "", err
 			}
+			claims.Custom = encrypted
 		}
 	}
+	if m.signKey == nil {
+		return "", ErrVerifyOnly
+	}
+	signKey, kid, err := m.signingKey(tokenType)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(m.signingMethod, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
 
 	// 签名生成令牌
-	tokenStr, err := token.SignedString(m.secretKey)
+	tokenStr, err := token.SignedString(signKey)
 	if err != nil {
 		m.logf("令牌签名失败: %v", err)
 		return "", err
@@ -343,59 +430,83 @@ func (m *TokenManager) GenerateToken(subject string, options ...*TokenOptions) (
 
 // ValidateToken 验证JWT令牌并返回声明
 func (m *TokenManager) ValidateToken(tokenStr string) (*StandardClaims, error) {
+	// 不透明令牌以存储为准，撤销或删除后立即失效，不使用缓存
+	if !m.enableCache || m.cacheTTL <= 0 || IsOpaqueToken(tokenStr) {
+		return m.validateToken(tokenStr)
+	}
+
 	// 先检查缓存以提高性能
-	if m.enableCache {
-		if claims, err, found := m.checkCache(tokenStr); found {
-			return claims, err
+	// 使用共享黑名单时令牌可能已在其他实例上被撤销，因此缓存命中的有效结果仍需检查黑名单
+	if claims, err, found := m.checkCache(tokenStr); found {
+		if err == nil {
+			if revoked, checkErr := m.isRevoked(tokenStr); checkErr != nil {
+				return nil, checkErr
+			} else if revoked {
+				m.cache.Delete(tokenStr)
+				return nil, tokenError(ErrRevoked, nil)
+			}
 		}
+		return claims, err
 	}
 
+	// 同一令牌的并发验证只解析一次，结果（包括验证失败）写入缓存后共享
+	// 黑名单存储等基础设施错误不是令牌本身的结论，作为 loader 错误返回，不写入缓存
+	item, err := m.cache.GetOrLoad(context.Background(), tokenStr,
+		func(_ context.Context, tokenStr string) (cacheItem, error) {
+			claims, err := m.validateToken(tokenStr)
+			if err != nil && !isTokenError(err) {
+				return cacheItem{}, err
+			}
+			return cacheItem{claims: claims, err: err}, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return item.claims, item.err
+}
+
+// validateToken 执行实际的黑名单检查、格式预检和签名验证
+func (m *TokenManager) validateToken(tokenStr string) (*StandardClaims, error) {
 	// 快速检查是否在黑名单中
-	if m.IsBlacklisted(tokenStr) {
-		if m.enableCache {
-			m.cacheResult(tokenStr, nil, errors.New("token has been revoked"))
-		}
-		return nil, errors.New("token has been revoked")
+	revoked, err := m.isRevoked(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, tokenError(ErrRevoked, nil)
+	}
+
+	if IsOpaqueToken(tokenStr) {
+		return m.validateOpaqueToken(tokenStr)
 	}
 
 	// 进行预检查，避免解析无效token
 	if !m.isTokenFormatValid(tokenStr) {
-		if m.enableCache {
-			m.cacheResult(tokenStr, nil, errors.New("invalid token format"))
-		}
-		return nil, errors.New("invalid token format")
+		return nil, tokenError(ErrMalformed, nil)
 	}
 
 	// 解析并验证令牌
+	// 只接受管理器配置的算法，防止 alg 混淆攻击（例如用公钥作为 HMAC 密钥伪造 RS256 令牌）
 	token, err := jwt.ParseWithClaims(tokenStr, &StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != m.signingMethod.Alg() {
 			return nil, fmt.Errorf("意外的签名方法: %v", token.Header["alg"])
 		}
-		return m.secretKey, nil
-	})
-
-	// 如果解析出错
+		return m.verificationKey(token)
+	}, jwt.WithValidMethods([]string{m.signingMethod.Alg()}))
 	if err != nil {
-		if m.enableCache {
-			m.cacheResult(tokenStr, nil, err)
-		}
-		return nil, err
+		return nil, classifyParseError(err)
 	}
 
-	// 如果验证通过
-	if claims, ok := token.Claims.(*StandardClaims); ok && token.Valid {
-		// 缓存验证成功的结果
-		if m.enableCache {
-			m.cacheResult(tokenStr, claims, nil)
-		}
-		return claims, nil
+	claims, ok := token.Claims.(*StandardClaims)
+	if !ok || !token.Valid {
+		return nil, tokenError(ErrInvalid, nil)
 	}
-
-	// 缓存无效令牌结果
-	if m.enableCache {
-		m.cacheResult(tokenStr, nil, errors.New("invalid token"))
+	if enc := m.claimEncryptor.Load(); enc != nil && len(claims.Custom) > 0 {
+		if err := enc.decrypt(claims.Custom); err != nil {
+			return nil, tokenError(ErrMalformed, err)
+		}
 	}
-	return nil, errors.New("invalid token")
+	return claims, nil
 }
 
 // RefreshToken 刷新访问令牌并返回访问令牌和刷新令牌
@@ -408,7 +519,7 @@ func (m *TokenManager) RefreshToken(refreshTokenStr string) (accessToken string,
 
 	// 确保是刷新令牌类型
 	if claims.TokenType != RefreshToken {
-		return "", "", errors.New("provided token is not a valid refresh token")
+		return "", "", tokenError(ErrWrongType, nil).WithDetails("provided token is not a refresh token")
 	}
 
 	// 创建新的访问令牌
@@ -444,20 +555,8 @@ func (m *TokenManager) isTokenFormatValid(tokenStr string) bool {
 
 // 检查缓存中是否有验证结果
 func (m *TokenManager) checkCache(tokenStr string) (*StandardClaims, error, bool) {
-	m.cacheLock.RLock()
-	item, exists := m.cache[tokenStr]
-	m.cacheLock.RUnlock()
-
-	if !exists {
-		return nil, nil, false
-	}
-
-	// 检查缓存项是否过期
-	if time.Since(item.timestamp) > m.cacheTTL {
-		// 缓存已过期，移除它
-		m.cacheLock.Lock()
-		delete(m.cache, tokenStr)
-		m.cacheLock.Unlock()
+	item, found := m.cache.Get(tokenStr)
+	if !found {
 		return nil, nil, false
 	}
 
@@ -468,78 +567,12 @@ func (m *TokenManager) checkCache(tokenStr string) (*StandardClaims, error, bool
 	return item.claims, item.err, true
 }
 
-// 缓存验证结果
-func (m *TokenManager) cacheResult(tokenStr string, claims *StandardClaims, err error) {
-	m.cacheLock.Lock()
-	defer m.cacheLock.Unlock()
-
-	// 如果缓存已满，先清理部分旧数据
-	if len(m.cache) >= m.cacheSize {
-		m.evictOldestCache(m.cacheSize / 5) // 清理20%的缓存
-	}
-
-	// 添加到缓存
-	m.cache[tokenStr] = cacheItem{
-		claims:    claims,
-		err:       err,
-		timestamp: time.Now(),
-	}
-}
-
-// 清理最旧的部分缓存
-func (m *TokenManager) evictOldestCache(count int) {
-	// 找出最老的缓存项进行清理
-	if count <= 0 || len(m.cache) == 0 {
-		return
-	}
-
-	type cacheAge struct {
-		token string
-		time  time.Time
-	}
-
-	// 收集所有缓存项的时间戳
-	items := make([]cacheAge, 0, len(m.cache))
-	for token, item := range m.cache {
-		items = append(items, cacheAge{token, item.timestamp})
-	}
-
-	// 按时间戳排序（不需要完全排序，找到n个最老的即可）
-	// 使用简单的选择排序，只排序需要的部分
-	for i := 0; i < count && i < len(items); i++ {
-		oldest := i
-		for j := i + 1; j < len(items); j++ {
-			if items[j].time.Before(items[oldest].time) {
-				oldest = j
-			}
-		}
-		// 交换
-		if oldest != i {
-			items[i], items[oldest] = items[oldest], items[i]
-		}
-
-		// 删除这个最老的项
-		delete(m.cache, items[i].token)
-	}
-}
-
 // 清理过期的缓存
 func (m *TokenManager) cleanCache() {
-	m.cacheLock.Lock()
-	defer m.cacheLock.Unlock()
-
-	now := time.Now()
-	expiredTime := now.Add(-m.cacheTTL)
-
-	// 删除所有过期的缓存项
-	for token, item := range m.cache {
-		if item.timestamp.Before(expiredTime) {
-			delete(m.cache, token)
-		}
-	}
+	removed := m.cache.RemoveExpired()
 
 	if m.enableLog {
-		m.logf("已清理过期缓存，当前缓存大小: %d", len(m.cache))
+		m.logf("已清理 %d 条过期缓存，当前缓存大小: %d", removed, m.cache.Len())
 	}
 }
 
@@ -568,81 +601,70 @@ func (m *TokenManager) RevokeToken(tokenStr string) error {
 		m.logf("撤销令牌: %s..., 过期时间: %v", tokenStr[:10], expireTime)
 	}
 
-	// 使用分段锁减少锁竞争
-	lockIndex := m.getLockIndex(tokenStr)
-	m.blacklistLock[lockIndex].Lock()
-	defer m.blacklistLock[lockIndex].Unlock()
+	if err := m.blacklist.Set(context.Background(), tokenStr, expireTime); err != nil {
+		return fmt.Errorf("failed to write blacklist: %w", err)
+	}
 
-	m.blacklist[tokenStr] = expireTime
+	// 不透明令牌同时从存储中删除
+	if IsOpaqueToken(tokenStr) && m.opaqueStore != nil {
+		if err := m.opaqueStore.Delete(context.Background(), tokenStr); err != nil {
+			return tokenError(ErrStoreUnavailable, err).WithDetails("failed to delete opaque token")
+		}
+	}
 
 	// 从缓存中移除该令牌的验证结果（如果有）
 	if m.enableCache {
-		m.cacheLock.Lock()
-		delete(m.cache, tokenStr)
-		m.cacheLock.Unlock()
+		m.cache.Delete(tokenStr)
 	}
 
 	return nil
 }
 
 // IsBlacklisted 检查令牌是否在黑名单中
+// 黑名单存储出错时记录日志并返回 false，ValidateToken 会直接返回该错误
 func (m *TokenManager) IsBlacklisted(tokenStr string) bool {
-	// 找到对应的分段锁
-	lockIndex := m.getLockIndex(tokenStr)
-	m.blacklistLock[lockIndex].RLock()
-	expireAt, exists := m.blacklist[tokenStr]
-	m.blacklistLock[lockIndex].RUnlock()
+	revoked, err := m.isRevoked(tokenStr)
+	if err != nil {
+		m.logf("检查黑名单失败: %v", err)
+		return false
+	}
+	return revoked
+}
 
+// isRevoked 查询黑名单，过期条目会被顺带移除
+func (m *TokenManager) isRevoked(tokenStr string) (bool, error) {
+	ctx := context.Background()
+	expireAt, exists, err := m.blacklist.Get(ctx, tokenStr)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blacklist: %w", err)
+	}
 	if !exists {
-		return false
+		return false, nil
 	}
 
 	// 如果黑名单过期时间已到，从黑名单中移除
-	now := time.Now()
-	if now.After(expireAt) {
+	if time.Now().After(expireAt) {
 		if m.enableLog && len(tokenStr) > 10 {
 			m.logf("令牌在黑名单中但已过期，移除: %s...", tokenStr[:10])
 		}
-
-		// 获取写锁删除过期条目
-		m.blacklistLock[lockIndex].Lock()
-		delete(m.blacklist, tokenStr)
-		m.blacklistLock[lockIndex].Unlock()
-
-		return false
+		if err := m.blacklist.Delete(ctx, tokenStr); err != nil {
+			m.logf("移除过期黑名单记录失败: %v", err)
+		}
+		return false, nil
 	}
 
 	if m.enableLog && len(tokenStr) > 10 {
 		m.logf("令牌在黑名单中: %s..., 将在 %v 过期", tokenStr[:10], expireAt)
 	}
-	return true
+	return true, nil
 }
 
 // CleanBlacklist 清理过期的黑名单记录
 func (m *TokenManager) CleanBlacklist() {
-	now := time.Now()
-	cleaned := 0
-
-	// 逐个分段清理，减少锁持有时间
-	for i := 0; i < m.blacklistSegments; i++ {
-		m.blacklistLock[i].Lock()
-
-		// 收集当前分段中的过期令牌
-		var expiredTokens []string
-		for token, expireAt := range m.blacklist {
-			// 计算锁索引，确保只清理当前分段的令牌
-			if m.getLockIndex(token) == i && now.After(expireAt) {
-				expiredTokens = append(expiredTokens, token)
-			}
-		}
-
-		// 删除收集到的过期令牌
-		for _, token := range expiredTokens {
-			delete(m.blacklist, token)
-			cleaned++
-		}
-
-		m.blacklistLock[i].Unlock()
+	cleaned, err := m.blacklist.Cleanup(context.Background())
+	if err != nil {
+		m.logf("清理黑名单失败: %v", err)
+		return
 	}
 
 	if m.enableLog && cleaned > 0 {
@@ -651,26 +673,28 @@ func (m *TokenManager) CleanBlacklist() {
 }
 
 // GetBlacklistSize 返回黑名单大小
+// 黑名单存储未实现 BlacklistSizer 或统计失败时返回 0
 func (m *TokenManager) GetBlacklistSize() int {
-	total := 0
-	for i := 0; i < m.blacklistSegments; i++ {
-		m.blacklistLock[i].RLock()
-		// 只统计当前分段负责的令牌数量
-		for token := range m.blacklist {
-			if m.getLockIndex(token) == i {
-				total++
-			}
-		}
-		m.blacklistLock[i].RUnlock()
+	sizer, ok := m.blacklist.(BlacklistSizer)
+	if !ok {
+		return 0
 	}
-	return total
+	n, err := sizer.Len(context.Background())
+	if err != nil {
+		m.logf("统计黑名单大小失败: %v", err)
+		return 0
+	}
+	return n
 }
 
 // GetCacheSize 返回缓存大小
 func (m *TokenManager) GetCacheSize() int {
-	m.cacheLock.RLock()
-	defer m.cacheLock.RUnlock()
-	return len(m.cache)
+	return m.cache.Len()
+}
+
+// GetCacheStats 返回验证结果缓存的命中率、淘汰数等统计指标
+func (m *TokenManager) GetCacheStats() cache.Stats {
+	return m.cache.Stats()
 }
 
 // GetTokenExpiryConfig 返回当前配置的JWT选项
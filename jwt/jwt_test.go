@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -19,8 +21,8 @@ func TestMustNewTokenManager(t *testing.T) {
 	if manager.refreshTokenExpiry != DefaultJWTOptions().RefreshTokenExpiry {
 		t.Errorf("Expected default refresh token expiry %v, got %v", DefaultJWTOptions().RefreshTokenExpiry, manager.refreshTokenExpiry)
 	}
-	if len(manager.blacklist) != 0 {
-		t.Error("Expected empty blacklist map")
+	if manager.GetBlacklistSize() != 0 {
+		t.Error("Expected empty blacklist")
 	}
 }
 
@@ -186,15 +188,12 @@ func TestTokenManager_RevokeToken(t *testing.T) {
 
 	// 验证已撤销的令牌不能通过验证
 	_, err = manager.ValidateToken(token)
-	if err == nil || err.Error() != "token has been revoked" {
+	if !errors.Is(err, ErrRevoked) {
 		t.Errorf("Validation should fail for revoked token, got: %v", err)
 	}
 
 	// 测试清理过期的黑名单记录
-	lockIndex := manager.getLockIndex(token)
-	manager.blacklistLock[lockIndex].Lock()
-	manager.blacklist[token] = time.Now().Add(-time.Hour) // 设置为过期时间
-	manager.blacklistLock[lockIndex].Unlock()
+	_ = manager.blacklist.Set(context.Background(), token, time.Now().Add(-time.Hour)) // 设置为过期时间
 
 	manager.CleanBlacklist()
 	if manager.IsBlacklisted(token) {
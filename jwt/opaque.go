@@ -0,0 +1,239 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpaqueTokenPrefix 不透明令牌的前缀，用于与 JWT 区分
+const OpaqueTokenPrefix = "opq_"
+
+// opaqueTokenBytes 不透明令牌的随机字节数
+const opaqueTokenBytes = 32
+
+// ErrNoOpaqueStore 启用不透明令牌模式但未配置存储
+var ErrNoOpaqueStore = errors.New("opaque token mode requires an OpaqueTokenStore")
+
+// TokenMode 令牌的签发模式
+type TokenMode int
+
+const (
+	// TokenModeJWT 自包含的签名 JWT（默认）
+	TokenModeJWT TokenMode = iota
+	// TokenModeOpaque 随机引用令牌，声明保存在服务端存储中，客户端无法读取
+	TokenModeOpaque
+)
+
+// OpaqueTokenStore 不透明令牌的声明存储
+// 多实例部署时应使用 Redis、数据库等共享存储
+type OpaqueTokenStore interface {
+	// Save 保存令牌对应的声明，expireAt 之后条目可被清理
+	Save(ctx context.Context, token string, claims *StandardClaims, expireAt time.Time) error
+	// Load 读取令牌对应的声明，不存在时 found 为 false
+	Load(ctx context.Context, token string) (claims *StandardClaims, found bool, err error)
+	// Delete 删除令牌，撤销后立即失效
+	Delete(ctx context.Context, token string) error
+}
+
+// IsOpaqueToken 判断令牌是否为不透明令牌
+func IsOpaqueToken(token string) bool {
+	return strings.HasPrefix(token, OpaqueTokenPrefix)
+}
+
+// SetTokenMode 设置指定令牌类型的签发模式，例如刷新令牌使用不透明模式、访问令牌仍使用 JWT
+// 未配置 OpaqueTokenStore 时设置不透明模式返回 ErrNoOpaqueStore；已签发的令牌不受影响
+func (m *TokenManager) SetTokenMode(tokenType TokenType, mode TokenMode) error {
+	if mode == TokenModeOpaque && m.opaqueStore == nil {
+		return ErrNoOpaqueStore
+	}
+	m.tokenModesMu.Lock()
+	defer m.tokenModesMu.Unlock()
+	if m.tokenModes == nil {
+		m.tokenModes = make(map[TokenType]TokenMode)
+	}
+	m.tokenModes[tokenType] = mode
+	return nil
+}
+
+// TokenMode 返回指定令牌类型的签发模式
+func (m *TokenManager) TokenMode(tokenType TokenType) TokenMode {
+	m.tokenModesMu.RLock()
+	defer m.tokenModesMu.RUnlock()
+	return m.tokenModes[tokenType]
+}
+
+// issueOpaqueToken 生成随机令牌并把声明写入存储
+func (m *TokenManager) issueOpaqueToken(claims *StandardClaims) (string, error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate opaque token: %w", err)
+	}
+	token := OpaqueTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	if err := m.opaqueStore.Save(context.Background(), token, claims, claims.ExpiresAt.Time); err != nil {
+		return "", tokenError(ErrStoreUnavailable, err).WithDetails("failed to save opaque token")
+	}
+	return token, nil
+}
+
+// validateOpaqueToken 从存储读取声明并检查有效期
+func (m *TokenManager) validateOpaqueToken(token string) (*StandardClaims, error) {
+	if m.opaqueStore == nil {
+		return nil, tokenError(ErrMalformed, ErrNoOpaqueStore)
+	}
+	claims, found, err := m.opaqueStore.Load(context.Background(), token)
+	if err != nil {
+		return nil, tokenError(ErrStoreUnavailable, err).WithDetails("failed to load opaque token")
+	}
+	if !found || claims == nil {
+		return nil, tokenError(ErrInvalid, nil).WithDetails("opaque token not found")
+	}
+
+	now := time.Now()
+	if claims.ExpiresAt != nil && !now.Before(claims.ExpiresAt.Time) {
+		return nil, tokenError(ErrExpired, nil)
+	}
+	if claims.NotBefore != nil && now.Before(claims.NotBefore.Time) {
+		return nil, tokenError(ErrNotYetValid, nil)
+	}
+	return claims, nil
+}
+
+// cleanOpaqueStore 清理不透明令牌存储中的过期条目，存储不支持清理时跳过
+func (m *TokenManager) cleanOpaqueStore() {
+	cleaner, ok := m.opaqueStore.(interface {
+		Cleanup(ctx context.Context) (int, error)
+	})
+	if !ok {
+		return
+	}
+	if n, err := cleaner.Cleanup(context.Background()); err != nil {
+		m.logf("清理不透明令牌失败: %v", err)
+	} else if n > 0 && m.enableLog {
+		m.logf("已清理 %d 条过期不透明令牌", n)
+	}
+}
+
+// MemoryOpaqueTokenStore 进程内不透明令牌存储，仅适用于单实例部署或测试
+type MemoryOpaqueTokenStore struct {
+	mu    sync.RWMutex
+	items map[string]memoryOpaqueEntry
+}
+
+// memoryOpaqueEntry 存储的声明（JSON）与过期时间
+type memoryOpaqueEntry struct {
+	data     []byte
+	expireAt time.Time
+}
+
+// NewMemoryOpaqueTokenStore 创建进程内不透明令牌存储
+func NewMemoryOpaqueTokenStore() *MemoryOpaqueTokenStore {
+	return &MemoryOpaqueTokenStore{items: make(map[string]memoryOpaqueEntry)}
+}
+
+// Save 实现 OpaqueTokenStore 接口，声明以 JSON 保存，读取结果与共享存储一致
+func (s *MemoryOpaqueTokenStore) Save(_ context.Context, token string, claims *StandardClaims, expireAt time.Time) error {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.items[token] = memoryOpaqueEntry{data: data, expireAt: expireAt}
+	s.mu.Unlock()
+	return nil
+}
+
+// Load 实现 OpaqueTokenStore 接口，过期条目视为不存在
+func (s *MemoryOpaqueTokenStore) Load(_ context.Context, token string) (*StandardClaims, bool, error) {
+	s.mu.RLock()
+	entry, ok := s.items[token]
+	s.mu.RUnlock()
+	if !ok || time.Now().After(entry.expireAt) {
+		return nil, false, nil
+	}
+	claims := &StandardClaims{}
+	if err := json.Unmarshal(entry.data, claims); err != nil {
+		return nil, false, err
+	}
+	return claims, true, nil
+}
+
+// Delete 实现 OpaqueTokenStore 接口
+func (s *MemoryOpaqueTokenStore) Delete(_ context.Context, token string) error {
+	s.mu.Lock()
+	delete(s.items, token)
+	s.mu.Unlock()
+	return nil
+}
+
+// Cleanup 清理已过期的条目，返回清理数量
+func (s *MemoryOpaqueTokenStore) Cleanup(_ context.Context) (int, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cleaned := 0
+	for token, entry := range s.items {
+		if now.After(entry.expireAt) {
+			delete(s.items, token)
+			cleaned++
+		}
+	}
+	return cleaned, nil
+}
+
+// RedisOpaqueTokenStore 基于 Redis 的不透明令牌存储
+// 键为 prefix + SHA-256(令牌)，值为声明 JSON，TTL 与令牌有效期一致
+type RedisOpaqueTokenStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisOpaqueTokenStore 创建 Redis 不透明令牌存储，prefix 为空时使用 "jwt:opaque:"
+func NewRedisOpaqueTokenStore(client RedisClient, prefix string) *RedisOpaqueTokenStore {
+	if prefix == "" {
+		prefix = "jwt:opaque:"
+	}
+	return &RedisOpaqueTokenStore{client: client, prefix: prefix}
+}
+
+func (s *RedisOpaqueTokenStore) key(token string) string {
+	return s.prefix + hashToken(token)
+}
+
+// Save 实现 OpaqueTokenStore 接口
+func (s *RedisOpaqueTokenStore) Save(ctx context.Context, token string, claims *StandardClaims, expireAt time.Time) error {
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.key(token), string(data), ttl)
+}
+
+// Load 实现 OpaqueTokenStore 接口
+func (s *RedisOpaqueTokenStore) Load(ctx context.Context, token string) (*StandardClaims, bool, error) {
+	value, found, err := s.client.Get(ctx, s.key(token))
+	if err != nil || !found {
+		return nil, false, err
+	}
+	claims := &StandardClaims{}
+	if err := json.Unmarshal([]byte(value), claims); err != nil {
+		return nil, false, fmt.Errorf("invalid opaque token value: %w", err)
+	}
+	return claims, true, nil
+}
+
+// Delete 实现 OpaqueTokenStore 接口
+func (s *RedisOpaqueTokenStore) Delete(ctx context.Context, token string) error {
+	return s.client.Del(ctx, s.key(token))
+}
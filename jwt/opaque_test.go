@@ -0,0 +1,236 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newOpaqueManager(t *testing.T, store OpaqueTokenStore) *TokenManager {
+	t.Helper()
+	opts := DefaultJWTOptions()
+	opts.OpaqueTokenStore = store
+	opts.TokenModes = map[TokenType]TokenMode{RefreshToken: TokenModeOpaque}
+	manager, err := NewTokenManager(testSecret, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(manager.Shutdown)
+	return manager
+}
+
+func TestOpaqueToken_PerTypeMode(t *testing.T) {
+	manager := newOpaqueManager(t, NewMemoryOpaqueTokenStore())
+
+	access, err := manager.GenerateToken("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsOpaqueToken(access) || strings.Count(access, ".") != 2 {
+		t.Errorf("access token should be a JWT, got %q", access)
+	}
+
+	refresh, err := manager.GenerateToken("user", &TokenOptions{
+		TokenType:    RefreshToken,
+		SessionID:    "s1",
+		CustomClaims: map[string]interface{}{"role": "admin"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsOpaqueToken(refresh) || strings.Contains(refresh, ".") {
+		t.Fatalf("refresh token should be opaque, got %q", refresh)
+	}
+
+	claims, err := manager.ValidateToken(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user" || claims.TokenType != RefreshToken || claims.SessionID != "s1" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims.Custom["role"] != "admin" {
+		t.Errorf("custom claims = %v", claims.Custom)
+	}
+
+	// 刷新流程与 JWT 模式一致
+	newAccess, sameRefresh, err := manager.RefreshToken(refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sameRefresh != refresh || IsOpaqueToken(newAccess) {
+		t.Errorf("RefreshToken() = %q, %q", newAccess, sameRefresh)
+	}
+}
+
+func TestOpaqueToken_Errors(t *testing.T) {
+	store := NewMemoryOpaqueTokenStore()
+	manager := newOpaqueManager(t, store)
+
+	if _, err := manager.ValidateToken(OpaqueTokenPrefix + "unknown"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unknown token error = %v, want ErrInvalid", err)
+	}
+
+	expired, _ := manager.GenerateToken("user", &TokenOptions{TokenType: RefreshToken, ExpiresIn: time.Hour})
+	claims, _, _ := store.Load(context.Background(), expired)
+	claims.ExpiresAt.Time = time.Now().Add(-time.Second)
+	_ = store.Save(context.Background(), expired, claims, time.Now().Add(time.Hour))
+	if _, err := manager.ValidateToken(expired); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired token error = %v, want ErrExpired", err)
+	}
+
+	revoked, _ := manager.GenerateToken("user", &TokenOptions{TokenType: RefreshToken})
+	if _, err := manager.ValidateToken(revoked); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RevokeToken(revoked); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := store.Load(context.Background(), revoked); found {
+		t.Error("revoked opaque token should be deleted from store")
+	}
+	if _, err := manager.ValidateToken(revoked); !errors.Is(err, ErrRevoked) {
+		t.Errorf("revoked token error = %v, want ErrRevoked", err)
+	}
+}
+
+func TestOpaqueToken_StoreDeletionTakesEffect(t *testing.T) {
+	store := NewMemoryOpaqueTokenStore()
+	manager := newOpaqueManager(t, store)
+
+	token, _ := manager.GenerateToken("user", &TokenOptions{TokenType: RefreshToken})
+	if _, err := manager.ValidateToken(token); err != nil {
+		t.Fatal(err)
+	}
+	// 其他实例直接删除存储条目后，不应命中缓存
+	_ = store.Delete(context.Background(), token)
+	if _, err := manager.ValidateToken(token); !errors.Is(err, ErrInvalid) {
+		t.Errorf("deleted token error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestSetTokenMode(t *testing.T) {
+	manager := MustNewTokenManager(testSecret)
+	defer manager.Shutdown()
+
+	if err := manager.SetTokenMode(AccessToken, TokenModeOpaque); !errors.Is(err, ErrNoOpaqueStore) {
+		t.Errorf("SetTokenMode() without store error = %v, want ErrNoOpaqueStore", err)
+	}
+
+	manager = newOpaqueManager(t, NewMemoryOpaqueTokenStore())
+	if err := manager.SetTokenMode(AccessToken, TokenModeOpaque); err != nil {
+		t.Fatal(err)
+	}
+	token, _ := manager.GenerateToken("user")
+	if !IsOpaqueToken(token) {
+		t.Errorf("access token should be opaque after SetTokenMode, got %q", token)
+	}
+	if err := manager.SetTokenMode(RefreshToken, TokenModeJWT); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := manager.GenerateToken("user", &TokenOptions{TokenType: RefreshToken}); IsOpaqueToken(token) {
+		t.Errorf("refresh token should be a JWT, got %q", token)
+	}
+}
+
+func TestRedisOpaqueTokenStore(t *testing.T) {
+	client := newFakeRedis()
+	store := NewRedisOpaqueTokenStore(client, "")
+	manager := newOpaqueManager(t, store)
+
+	token, err := manager.GenerateToken("user", &TokenOptions{TokenType: RefreshToken, ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "jwt:opaque:" + hashToken(token)
+	if _, ok := client.data[key]; !ok {
+		t.Fatalf("expected key %s in redis", key)
+	}
+	if ttl := client.ttls[key]; ttl <= 0 || ttl > time.Hour {
+		t.Errorf("ttl = %v, want (0, 1h]", ttl)
+	}
+
+	claims, err := manager.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user" {
+		t.Errorf("Subject = %q", claims.Subject)
+	}
+
+	if err := manager.RevokeToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.data[key]; ok {
+		t.Error("revoked token should be deleted from redis")
+	}
+}
+
+func TestMemoryOpaqueTokenStore_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryOpaqueTokenStore()
+	claims := &StandardClaims{Subject: "user"}
+	_ = store.Save(ctx, "active", claims, time.Now().Add(time.Hour))
+	_ = store.Save(ctx, "expired", claims, time.Now().Add(-time.Hour))
+
+	if _, found, _ := store.Load(ctx, "expired"); found {
+		t.Error("expired entry should not be found")
+	}
+	if cleaned, _ := store.Cleanup(ctx); cleaned != 1 {
+		t.Errorf("Cleanup() = %d, want 1", cleaned)
+	}
+	if _, found, _ := store.Load(ctx, "active"); !found {
+		t.Error("active entry should be found")
+	}
+}
+
+func TestOpaqueToken_CleanupRoutine(t *testing.T) {
+	store := NewMemoryOpaqueTokenStore()
+	_ = store.Save(context.Background(), OpaqueTokenPrefix+"expired", &StandardClaims{}, time.Now().Add(-time.Hour))
+
+	opts := DefaultJWTOptions()
+	opts.OpaqueTokenStore = store
+	opts.BlacklistCleanInterval = 10 * time.Millisecond
+	manager := MustNewTokenManager(testSecret, opts)
+	defer manager.Shutdown()
+
+	// 后台清理例程同时清理过期的不透明令牌
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.RLock()
+		n := len(store.items)
+		store.mu.RUnlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired opaque token was not cleaned up")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// failingOpaqueStore 模拟不可用的不透明令牌存储
+type failingOpaqueStore struct{ OpaqueTokenStore }
+
+func (failingOpaqueStore) Load(context.Context, string) (*StandardClaims, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func TestOpaqueToken_StoreErrorIsStructured(t *testing.T) {
+	manager := newOpaqueManager(t, failingOpaqueStore{NewMemoryOpaqueTokenStore()})
+
+	_, err := manager.ValidateToken(OpaqueTokenPrefix + "token")
+	if !errors.Is(err, ErrStoreUnavailable) || ErrorCode(err) != CodeTokenStore {
+		t.Fatalf("ValidateToken() error = %v, want ErrStoreUnavailable", err)
+	}
+	if status := HTTPStatus(err); status != http.StatusServiceUnavailable {
+		t.Errorf("HTTPStatus() = %d, want 503", status)
+	}
+	if isTokenError(err) {
+		t.Error("store errors must not be treated as token verdicts")
+	}
+}
@@ -0,0 +1,259 @@
+package jwt
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// defaultReplayWindow 默认的重放检测窗口
+const defaultReplayWindow = time.Minute
+
+// maxRecentUsages 每个令牌保留的最近使用记录上限，限制单个令牌占用的内存
+const maxRecentUsages = 32
+
+// TokenUsage 一次令牌使用
+type TokenUsage struct {
+	// 使用来源，通常为客户端 IP，也可以是设备指纹等标识
+	Source string `json:"source"`
+	// 使用时间，为空时取当前时间
+	At time.Time `json:"at"`
+}
+
+// TokenUsageRecord 令牌使用情况
+type TokenUsageRecord struct {
+	// 令牌首次使用时间
+	FirstSeen time.Time
+	// 窗口内在本次之前的使用记录
+	Recent []TokenUsage
+	// 是否在窗口内出现了来源明显不同的使用
+	Replayed bool
+}
+
+// ReplayEvent 疑似令牌重放事件
+type ReplayEvent struct {
+	TokenID   string
+	Subject   string
+	SessionID string
+	FirstSeen time.Time
+	// 本次使用与窗口内来源不同的上一次使用
+	Current  TokenUsage
+	Previous TokenUsage
+}
+
+// UsageStore 令牌使用记录存储，多实例部署时应使用共享存储
+type UsageStore interface {
+	// Record 追加一次使用，返回令牌首次使用时间与 since 之后的既有记录（不含本次），
+	// expireAt 之后该令牌的记录可以被清理
+	Record(ctx context.Context, tokenID string, usage TokenUsage, since, expireAt time.Time) (firstSeen time.Time, recent []TokenUsage, err error)
+}
+
+// memoryUsageEntry 单个令牌的使用记录
+type memoryUsageEntry struct {
+	firstSeen time.Time
+	usages    []TokenUsage
+	expireAt  time.Time
+}
+
+// MemoryUsageStore 进程内的使用记录存储
+type MemoryUsageStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryUsageEntry
+}
+
+// NewMemoryUsageStore 创建内存使用记录存储
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{entries: make(map[string]*memoryUsageEntry)}
+}
+
+// Record 实现 UsageStore 接口
+func (s *MemoryUsageStore) Record(_ context.Context, tokenID string, usage TokenUsage, since, expireAt time.Time) (time.Time, []TokenUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[tokenID]
+	if !ok {
+		entry = &memoryUsageEntry{firstSeen: usage.At}
+		s.entries[tokenID] = entry
+	}
+	if expireAt.After(entry.expireAt) {
+		entry.expireAt = expireAt
+	}
+
+	// 丢弃窗口外的记录
+	kept := entry.usages[:0]
+	for _, u := range entry.usages {
+		if !u.At.Before(since) {
+			kept = append(kept, u)
+		}
+	}
+	recent := append([]TokenUsage(nil), kept...)
+
+	kept = append(kept, usage)
+	if len(kept) > maxRecentUsages {
+		kept = kept[len(kept)-maxRecentUsages:]
+	}
+	entry.usages = kept
+	return entry.firstSeen, recent, nil
+}
+
+// FirstSeen 返回令牌首次使用时间
+func (s *MemoryUsageStore) FirstSeen(tokenID string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[tokenID]
+	if !ok {
+		return time.Time{}, false
+	}
+	return entry.firstSeen, true
+}
+
+// Cleanup 清理已过期令牌的使用记录，返回清理数量
+func (s *MemoryUsageStore) Cleanup(_ context.Context) (int, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cleaned := 0
+	for tokenID, entry := range s.entries {
+		if now.After(entry.expireAt) {
+			delete(s.entries, tokenID)
+			cleaned++
+		}
+	}
+	return cleaned, nil
+}
+
+// ReplayOptions 重放检测选项
+type ReplayOptions struct {
+	// 使用记录存储，为 nil 时使用进程内存储
+	Store UsageStore
+	// 检测窗口，窗口内来自不同来源的使用视为疑似重放，默认 1 分钟
+	Window time.Duration
+	// 判断两个来源是否相同，默认 SameNetwork
+	SameSource func(a, b string) bool
+	// 发现疑似重放时调用，只用于告警与审计，不会拒绝令牌
+	OnReplay func(ctx context.Context, event ReplayEvent)
+}
+
+// ReplayDetector 记录令牌的使用时间与来源，检测短时间内来自明显不同来源的使用（令牌被盗用重放）
+type ReplayDetector struct {
+	store      UsageStore
+	window     time.Duration
+	sameSource func(a, b string) bool
+	onReplay   func(ctx context.Context, event ReplayEvent)
+}
+
+// NewReplayDetector 创建重放检测器
+func NewReplayDetector(opts ReplayOptions) *ReplayDetector {
+	d := &ReplayDetector{
+		store:      opts.Store,
+		window:     opts.Window,
+		sameSource: opts.SameSource,
+		onReplay:   opts.OnReplay,
+	}
+	if d.store == nil {
+		d.store = NewMemoryUsageStore()
+	}
+	if d.window <= 0 {
+		d.window = defaultReplayWindow
+	}
+	if d.sameSource == nil {
+		d.sameSource = SameNetwork
+	}
+	return d
+}
+
+// Observe 记录令牌的一次使用并检测重放，令牌没有 TokenID 时不做记录
+func (d *ReplayDetector) Observe(ctx context.Context, claims *StandardClaims, usage TokenUsage) (TokenUsageRecord, error) {
+	if claims == nil || claims.TokenID == "" {
+		return TokenUsageRecord{}, nil
+	}
+	if usage.At.IsZero() {
+		usage.At = time.Now()
+	}
+	expireAt := usage.At.Add(d.window)
+	if claims.ExpiresAt != nil && claims.ExpiresAt.After(expireAt) {
+		expireAt = claims.ExpiresAt.Time
+	}
+
+	firstSeen, recent, err := d.store.Record(ctx, claims.TokenID, usage, usage.At.Add(-d.window), expireAt)
+	if err != nil {
+		return TokenUsageRecord{}, err
+	}
+	record := TokenUsageRecord{FirstSeen: firstSeen, Recent: recent}
+	for i := len(recent) - 1; i >= 0; i-- {
+		if d.sameSource(recent[i].Source, usage.Source) {
+			continue
+		}
+		record.Replayed = true
+		if d.onReplay != nil {
+			d.onReplay(ctx, ReplayEvent{
+				TokenID:   claims.TokenID,
+				Subject:   claims.Subject,
+				SessionID: claims.SessionID,
+				FirstSeen: firstSeen,
+				Current:   usage,
+				Previous:  recent[i],
+			})
+		}
+		break
+	}
+	return record, nil
+}
+
+// SameNetwork 默认的来源比较：两个 IP 位于同一 IPv4 /24 或 IPv6 /64 网段时视为相同来源，
+// 其他来源按字符串比较。同一客户端在 NAT 或移动网络下的小范围地址变化不会被误报。
+func SameNetwork(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return false
+	}
+	if v4A, v4B := ipA.To4(), ipB.To4(); v4A != nil || v4B != nil {
+		mask := net.CIDRMask(24, 32)
+		return v4A != nil && v4B != nil && v4A.Mask(mask).Equal(v4B.Mask(mask))
+	}
+	mask := net.CIDRMask(64, 128)
+	return ipA.Mask(mask).Equal(ipB.Mask(mask))
+}
+
+// ValidateTokenFrom 验证令牌，并在配置了 ReplayDetector 时记录本次使用的来源
+// 使用记录写入失败不影响验证结果，只记录日志
+func (m *TokenManager) ValidateTokenFrom(ctx context.Context, tokenStr, source string) (*StandardClaims, error) {
+	claims, err := m.ValidateToken(tokenStr)
+	if err != nil || m.replayDetector == nil {
+		return claims, err
+	}
+
+	observed := claims
+	if observed.TokenID == "" {
+		c := *claims
+		c.TokenID = hashToken(tokenStr)
+		observed = &c
+	}
+	if _, err := m.replayDetector.Observe(ctx, observed, TokenUsage{Source: source}); err != nil {
+		m.logf("记录令牌使用失败: %v", err)
+	}
+	return claims, nil
+}
+
+// cleanUsageStore 清理重放检测存储中的过期记录，存储不支持清理时跳过
+func (m *TokenManager) cleanUsageStore() {
+	if m.replayDetector == nil {
+		return
+	}
+	cleaner, ok := m.replayDetector.store.(interface {
+		Cleanup(ctx context.Context) (int, error)
+	})
+	if !ok {
+		return
+	}
+	if n, err := cleaner.Cleanup(context.Background()); err != nil {
+		m.logf("清理令牌使用记录失败: %v", err)
+	} else if n > 0 && m.enableLog {
+		m.logf("已清理 %d 条令牌使用记录", n)
+	}
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReplayDetector_Observe(t *testing.T) {
+	var events []ReplayEvent
+	detector := NewReplayDetector(ReplayOptions{
+		Window: time.Minute,
+		OnReplay: func(_ context.Context, e ReplayEvent) {
+			events = append(events, e)
+		},
+	})
+	ctx := context.Background()
+	claims := &StandardClaims{TokenID: "jti-1", Subject: "user-1"}
+	start := time.Now()
+
+	first, err := detector.Observe(ctx, claims, TokenUsage{Source: "203.0.113.10", At: start})
+	if err != nil || first.Replayed || !first.FirstSeen.Equal(start) {
+		t.Fatalf("first use = %+v, %v", first, err)
+	}
+
+	// 同一网段内的地址变化不视为重放
+	rec, _ := detector.Observe(ctx, claims, TokenUsage{Source: "203.0.113.99", At: start.Add(time.Second)})
+	if rec.Replayed || len(rec.Recent) != 1 {
+		t.Errorf("same network use = %+v", rec)
+	}
+
+	rec, _ = detector.Observe(ctx, claims, TokenUsage{Source: "198.51.100.7", At: start.Add(2 * time.Second)})
+	if !rec.Replayed || !rec.FirstSeen.Equal(start) {
+		t.Errorf("different source use = %+v", rec)
+	}
+	if len(events) != 1 || events[0].Subject != "user-1" || events[0].Previous.Source != "203.0.113.99" {
+		t.Errorf("events = %+v", events)
+	}
+
+	// 窗口外的使用不参与比较，但首次使用时间保持不变
+	rec, _ = detector.Observe(ctx, claims, TokenUsage{Source: "192.0.2.1", At: start.Add(5 * time.Minute)})
+	if rec.Replayed || len(rec.Recent) != 0 || !rec.FirstSeen.Equal(start) {
+		t.Errorf("use after window = %+v", rec)
+	}
+
+	if rec, _ := detector.Observe(ctx, &StandardClaims{}, TokenUsage{Source: "x"}); rec.Replayed || !rec.FirstSeen.IsZero() {
+		t.Errorf("claims without TokenID should be ignored: %+v", rec)
+	}
+}
+
+func TestSameNetwork(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"10.0.0.1", "10.0.0.200", true},
+		{"10.0.0.1", "10.0.1.1", false},
+		{"2001:db8::1", "2001:db8::ffff", true},
+		{"2001:db8::1", "2001:db8:0:1::1", false},
+		{"10.0.0.1", "::ffff:10.0.0.2", true},
+		{"10.0.0.1", "2001:db8::1", false},
+		{"device-a", "device-a", true},
+		{"device-a", "device-b", false},
+	}
+	for _, tt := range tests {
+		if got := SameNetwork(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameNetwork(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTokenManager_ValidateTokenFrom(t *testing.T) {
+	store := NewMemoryUsageStore()
+	replayed := 0
+	opts := DefaultJWTOptions()
+	opts.BlacklistCleanInterval = 0
+	opts.ReplayDetector = NewReplayDetector(ReplayOptions{
+		Store:    store,
+		OnReplay: func(context.Context, ReplayEvent) { replayed++ },
+	})
+	manager := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+
+	token, _ := manager.GenerateToken("user-1")
+	ctx := context.Background()
+	claims, err := manager.ValidateTokenFrom(ctx, token, "203.0.113.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.FirstSeen(claims.TokenID); !ok {
+		t.Error("first use should be recorded")
+	}
+
+	if _, err := manager.ValidateTokenFrom(ctx, token, "198.51.100.7"); err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 1 {
+		t.Errorf("replayed = %d, want 1", replayed)
+	}
+
+	if _, err := manager.ValidateTokenFrom(ctx, "invalid.token.value", "203.0.113.10"); err == nil {
+		t.Error("invalid token should fail")
+	}
+
+	expired := time.Now().Add(-time.Second)
+	store.entries[claims.TokenID].expireAt = expired
+	if n, _ := store.Cleanup(ctx); n != 1 {
+		t.Errorf("Cleanup() = %d, want 1", n)
+	}
+}
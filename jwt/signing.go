@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 签名配置相关错误
+var (
+	// ErrKeyTypeMismatch 密钥类型与签名算法不匹配
+	ErrKeyTypeMismatch = errors.New("key type does not match signing method")
+	// ErrVerifyOnly 管理器只配置了公钥，只能验证令牌不能签发
+	ErrVerifyOnly = errors.New("token manager has no private key and can only verify tokens")
+)
+
+// minRSAKeyBits RSA 密钥最小长度
+const minRSAKeyBits = 2048
+
+// NewTokenManagerWithKeys 创建使用非对称算法签名的令牌管理器
+// 支持 RS256/RS384/RS512、PS256/PS384/PS512 与 ES256/ES384/ES512。
+// privateKey 为 nil 时管理器只能验证令牌（例如验证外部 IdP 签发的令牌），GenerateToken 返回 ErrVerifyOnly；
+// publicKey 为 nil 时从私钥推导。验证时只接受 method 指定的算法。
+func NewTokenManagerWithKeys(method jwt.SigningMethod, privateKey crypto.PrivateKey, publicKey crypto.PublicKey, options ...*JWTOptions) (*TokenManager, error) {
+	if method == nil {
+		return nil, errors.New("signing method cannot be nil")
+	}
+	if privateKey == nil && publicKey == nil {
+		return nil, errors.New("at least one of private key and public key is required")
+	}
+
+	var signKey, verifyKey interface{}
+	switch m := method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		priv, pub, err := rsaKeyPair(privateKey, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method.Alg(), err)
+		}
+		if priv != nil {
+			signKey = priv
+		}
+		verifyKey = pub
+	case *jwt.SigningMethodECDSA:
+		priv, pub, err := ecdsaKeyPair(privateKey, publicKey, m.CurveBits)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", method.Alg(), err)
+		}
+		if priv != nil {
+			signKey = priv
+		}
+		verifyKey = pub
+	default:
+		return nil, fmt.Errorf("%w: unsupported signing method %s", ErrKeyTypeMismatch, method.Alg())
+	}
+
+	opts := DefaultJWTOptions()
+	if len(options) > 0 && options[0] != nil {
+		opts = options[0]
+	}
+	if opts.DeriveTypeKeys {
+		return nil, fmt.Errorf("%w: DeriveTypeKeys requires an HMAC signing method", ErrKeyTypeMismatch)
+	}
+	return newTokenManager(opts, method, signKey, verifyKey), nil
+}
+
+// SigningMethod 返回管理器使用的签名算法
+func (m *TokenManager) SigningMethod() jwt.SigningMethod {
+	return m.signingMethod
+}
+
+// rsaKeyPair 校验并返回 RSA 密钥对
+func rsaKeyPair(privateKey crypto.PrivateKey, publicKey crypto.PublicKey) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	var priv *rsa.PrivateKey
+	if privateKey != nil {
+		var ok bool
+		if priv, ok = privateKey.(*rsa.PrivateKey); !ok {
+			return nil, nil, fmt.Errorf("%w: expected *rsa.PrivateKey, got %T", ErrKeyTypeMismatch, privateKey)
+		}
+	}
+
+	var pub *rsa.PublicKey
+	switch {
+	case publicKey != nil:
+		var ok bool
+		if pub, ok = publicKey.(*rsa.PublicKey); !ok {
+			return nil, nil, fmt.Errorf("%w: expected *rsa.PublicKey, got %T", ErrKeyTypeMismatch, publicKey)
+		}
+		if priv != nil && !priv.PublicKey.Equal(pub) {
+			return nil, nil, errors.New("public key does not match private key")
+		}
+	default:
+		pub = &priv.PublicKey
+	}
+
+	if pub.N.BitLen() < minRSAKeyBits {
+		return nil, nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+	}
+	return priv, pub, nil
+}
+
+// ecdsaKeyPair 校验并返回 ECDSA 密钥对，曲线必须与算法一致（ES256 对应 P-256）
+func ecdsaKeyPair(privateKey crypto.PrivateKey, publicKey crypto.PublicKey, curveBits int) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
+	var priv *ecdsa.PrivateKey
+	if privateKey != nil {
+		var ok bool
+		if priv, ok = privateKey.(*ecdsa.PrivateKey); !ok {
+			return nil, nil, fmt.Errorf("%w: expected *ecdsa.PrivateKey, got %T", ErrKeyTypeMismatch, privateKey)
+		}
+	}
+
+	var pub *ecdsa.PublicKey
+	switch {
+	case publicKey != nil:
+		var ok bool
+		if pub, ok = publicKey.(*ecdsa.PublicKey); !ok {
+			return nil, nil, fmt.Errorf("%w: expected *ecdsa.PublicKey, got %T", ErrKeyTypeMismatch, publicKey)
+		}
+		if priv != nil && !priv.PublicKey.Equal(pub) {
+			return nil, nil, errors.New("public key does not match private key")
+		}
+	default:
+		pub = &priv.PublicKey
+	}
+
+	// ES512 的 CurveBits 为 521，与 P-521 的 BitSize 一致
+	if pub.Curve.Params().BitSize != curveBits {
+		return nil, nil, fmt.Errorf("%w: curve %s does not match %d-bit signing method",
+			ErrKeyTypeMismatch, pub.Curve.Params().Name, curveBits)
+	}
+	return priv, pub, nil
+}
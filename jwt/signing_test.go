@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestNewTokenManagerWithKeys_RSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodRS512, jwt.SigningMethodPS256} {
+		t.Run(method.Alg(), func(t *testing.T) {
+			manager, err := NewTokenManagerWithKeys(method, key, nil)
+			if err != nil {
+				t.Fatalf("NewTokenManagerWithKeys() error = %v", err)
+			}
+			defer manager.Shutdown()
+
+			token, err := manager.GenerateToken("user-1")
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			claims, err := manager.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if claims.Subject != "user-1" {
+				t.Errorf("Subject = %q, want user-1", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestNewTokenManagerWithKeys_ECDSAVerifyOnly(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟外部 IdP 签发令牌，本地只持有公钥
+	issuer, err := NewTokenManagerWithKeys(jwt.SigningMethodES256, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer issuer.Shutdown()
+	verifier, err := NewTokenManagerWithKeys(jwt.SigningMethodES256, nil, &key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer verifier.Shutdown()
+
+	token, err := issuer.GenerateToken("user-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken() error = %v", err)
+	}
+	if _, err := verifier.GenerateToken("user-2"); !errors.Is(err, ErrVerifyOnly) {
+		t.Errorf("GenerateToken() error = %v, want ErrVerifyOnly", err)
+	}
+}
+
+func TestNewTokenManagerWithKeys_Mismatch(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	smallRSA, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		priv   interface{}
+	}{
+		{"ecdsa key for RS256", jwt.SigningMethodRS256, p256},
+		{"rsa key for ES256", jwt.SigningMethodES256, rsaKey},
+		{"P-384 key for ES256", jwt.SigningMethodES256, p384},
+		{"short RSA key", jwt.SigningMethodRS256, smallRSA},
+		{"HMAC method", jwt.SigningMethodHS256, rsaKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTokenManagerWithKeys(tt.method, tt.priv, nil); err == nil {
+				t.Error("expected error for mismatched key")
+			}
+		})
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := NewTokenManagerWithKeys(jwt.SigningMethodRS256, rsaKey, &other.PublicKey); err == nil {
+		t.Error("expected error for mismatched public key")
+	}
+}
+
+func TestValidateToken_RejectsAlgorithmMismatch(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rs256, err := NewTokenManagerWithKeys(jwt.SigningMethodRS256, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs256.Shutdown()
+	rs512, err := NewTokenManagerWithKeys(jwt.SigningMethodRS512, nil, &key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs512.Shutdown()
+
+	token, _ := rs256.GenerateToken("user-3")
+	if _, err := rs512.ValidateToken(token); err == nil {
+		t.Error("RS512 manager accepted an RS256 token")
+	}
+
+	// HMAC 管理器不能接受非对称令牌，反之亦然
+	hmac := MustNewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!")
+	defer hmac.Shutdown()
+	if _, err := hmac.ValidateToken(token); err == nil {
+		t.Error("HS256 manager accepted an RS256 token")
+	}
+	hsToken, _ := hmac.GenerateToken("user-3")
+	if _, err := rs256.ValidateToken(hsToken); err == nil {
+		t.Error("RS256 manager accepted an HS256 token")
+	}
+}
+
+func TestNewTokenManager_HMACSigningMethod(t *testing.T) {
+	opts := DefaultJWTOptions()
+	opts.SigningMethod = jwt.SigningMethodHS512
+	manager, err := NewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Shutdown()
+
+	token, _ := manager.GenerateToken("user-4")
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &StandardClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Method.Alg() != "HS512" {
+		t.Errorf("alg = %s, want HS512", parsed.Method.Alg())
+	}
+
+	opts.SigningMethod = jwt.SigningMethodRS256
+	if _, err := NewTokenManager("this-is-a-very-secure-jwt-secret-key-32bytes!", opts); !errors.Is(err, ErrKeyTypeMismatch) {
+		t.Errorf("NewTokenManager() error = %v, want ErrKeyTypeMismatch", err)
+	}
+}
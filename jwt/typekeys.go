@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// typeKeyIDPrefix 使用派生密钥签名的令牌在头部 kid 中携带的前缀，后接令牌类型
+const typeKeyIDPrefix = "type:"
+
+// typeKeyInfoPrefix HKDF 的 info 前缀，与令牌类型拼接后作为派生标签
+const typeKeyInfoPrefix = "utils-pkg/jwt signing key: "
+
+// errLegacyKeyRejected 令牌使用主密钥签名，但管理器已不再接受旧令牌
+var errLegacyKeyRejected = errors.New("token is signed with the legacy master key")
+
+// derivedTokenTypes 使用派生密钥签名的令牌类型
+var derivedTokenTypes = []TokenType{AccessToken, RefreshToken}
+
+// typeKeys 按令牌类型从主密钥派生的 HMAC 签名密钥
+// 泄露某一类型的签名能力（例如访问令牌的签发服务）无法伪造其他类型的令牌
+type typeKeys struct {
+	keys map[TokenType][]byte
+}
+
+// newTypeKeys 在创建时派生所有令牌类型的密钥（HKDF-SHA256，info 为类型标签），
+// 密钥长度与 HMAC 算法的摘要长度一致；验证时不再派生，避免未经验证的令牌触发计算和内存增长
+func newTypeKeys(master []byte, method *jwt.SigningMethodHMAC) (*typeKeys, error) {
+	k := &typeKeys{keys: make(map[TokenType][]byte, len(derivedTokenTypes))}
+	for _, tokenType := range derivedTokenTypes {
+		key, err := hkdf.Key(sha256.New, master, nil, typeKeyInfoPrefix+string(tokenType), method.Hash.Size())
+		if err != nil {
+			return nil, fmt.Errorf("derive signing key for %s token: %w", tokenType, err)
+		}
+		k.keys[tokenType] = key
+	}
+	return k, nil
+}
+
+// key 返回令牌类型对应的派生密钥，只支持 derivedTokenTypes 中的类型
+func (k *typeKeys) key(tokenType TokenType) ([]byte, error) {
+	key, ok := k.keys[tokenType]
+	if !ok {
+		return nil, fmt.Errorf("no derived signing key for %q tokens", tokenType)
+	}
+	return key, nil
+}
+
+// typeKeyID 返回令牌类型对应的 kid
+func typeKeyID(tokenType TokenType) string {
+	return typeKeyIDPrefix + string(tokenType)
+}
+
+// SetAcceptLegacyTokens 设置启用派生密钥后是否仍接受主密钥签名的旧令牌
+// 迁移期间保持开启，旧令牌全部过期后关闭；关闭时会清空验证结果缓存
+func (m *TokenManager) SetAcceptLegacyTokens(accept bool) {
+	m.acceptLegacyTokens.Store(accept)
+	if !accept {
+		m.cache.Clear()
+	}
+}
+
+// signingKey 返回签发指定类型令牌的密钥与 kid，未启用派生密钥时 kid 为空
+func (m *TokenManager) signingKey(tokenType TokenType) (interface{}, string, error) {
+	if m.typeKeys == nil {
+		return m.signKey, "", nil
+	}
+	key, err := m.typeKeys.key(tokenType)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, typeKeyID(tokenType), nil
+}
+
+// verificationKey 按令牌头部的 kid 选择验证密钥
+// kid 必须与声明中的令牌类型一致，防止用一种类型的密钥签发另一种类型的令牌；
+// 不带 kid 的令牌使用主密钥签名，只在接受旧令牌时有效
+func (m *TokenManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if m.typeKeys == nil {
+		return m.verifyKey, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if !m.acceptLegacyTokens.Load() {
+			return nil, errLegacyKeyRejected
+		}
+		return m.verifyKey, nil
+	}
+
+	claims, ok := token.Claims.(*StandardClaims)
+	if !ok || !strings.HasPrefix(kid, typeKeyIDPrefix) || kid != typeKeyID(claims.TokenType) {
+		return nil, fmt.Errorf("key id %q does not match token type", kid)
+	}
+	return m.typeKeys.key(claims.TokenType)
+}
@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTypeKeysManager(t *testing.T, acceptLegacy bool) *TokenManager {
+	t.Helper()
+	opts := DefaultJWTOptions()
+	opts.DeriveTypeKeys = true
+	opts.AcceptLegacyTokens = acceptLegacy
+	manager, err := NewTokenManager(testSecret, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(manager.Shutdown)
+	return manager
+}
+
+// signWithKey 使用指定密钥和 kid 签发令牌，模拟持有部分签名能力的攻击者
+func signWithKey(t *testing.T, key []byte, kid string, tokenType TokenType) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &StandardClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Subject:   "attacker",
+		TokenType: tokenType,
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	tokenStr, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenStr
+}
+
+func TestDeriveTypeKeys_RoundTrip(t *testing.T) {
+	manager := newTypeKeysManager(t, false)
+
+	for _, tokenType := range []TokenType{AccessToken, RefreshToken} {
+		token, err := manager.GenerateToken("user-1", &TokenOptions{TokenType: tokenType})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := manager.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("%s: %v", tokenType, err)
+		}
+		if claims.TokenType != tokenType {
+			t.Errorf("TokenType = %s, want %s", claims.TokenType, tokenType)
+		}
+	}
+
+	access, _ := manager.typeKeys.key(AccessToken)
+	refresh, _ := manager.typeKeys.key(RefreshToken)
+	if string(access) == string(refresh) || string(access) == testSecret {
+		t.Error("derived keys must differ per token type and from the master secret")
+	}
+	if len(access) != 32 {
+		t.Errorf("HS256 derived key length = %d, want 32", len(access))
+	}
+}
+
+func TestDeriveTypeKeys_AccessKeyCannotForgeRefresh(t *testing.T) {
+	manager := newTypeKeysManager(t, false)
+	accessKey, _ := manager.typeKeys.key(AccessToken)
+
+	for name, token := range map[string]string{
+		"kid of refresh":      signWithKey(t, accessKey, typeKeyID(RefreshToken), RefreshToken),
+		"kid of access":       signWithKey(t, accessKey, typeKeyID(AccessToken), RefreshToken),
+		"unknown kid":         signWithKey(t, accessKey, "other", RefreshToken),
+		"legacy without kid":  signWithKey(t, accessKey, "", RefreshToken),
+		"access key is valid": signWithKey(t, accessKey, typeKeyID(AccessToken), AccessToken),
+	} {
+		_, err := manager.ValidateToken(token)
+		if name == "access key is valid" {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrSignature) {
+			t.Errorf("%s: error = %v, want ErrSignature", name, err)
+		}
+	}
+
+	if _, _, err := manager.RefreshToken(signWithKey(t, accessKey, typeKeyID(RefreshToken), RefreshToken)); err == nil {
+		t.Error("forged refresh token was accepted by RefreshToken")
+	}
+}
+
+func TestDeriveTypeKeys_LegacyTokens(t *testing.T) {
+	legacy := MustNewTokenManager(testSecret)
+	defer legacy.Shutdown()
+	oldToken, _ := legacy.GenerateToken("user-1", &TokenOptions{TokenType: RefreshToken})
+
+	manager := newTypeKeysManager(t, true)
+	if _, err := manager.ValidateToken(oldToken); err != nil {
+		t.Fatalf("legacy token rejected during rollout: %v", err)
+	}
+
+	// 派生密钥签发的新令牌不能被未升级的实例验证
+	newToken, _ := manager.GenerateToken("user-1")
+	if _, err := legacy.ValidateToken(newToken); err == nil {
+		t.Error("manager without derived keys accepted a derived-key token")
+	}
+
+	// 迁移完成后关闭，缓存中的旧结果一并失效
+	manager.SetAcceptLegacyTokens(false)
+	if _, err := manager.ValidateToken(oldToken); !errors.Is(err, ErrSignature) {
+		t.Errorf("error = %v, want ErrSignature after disabling legacy tokens", err)
+	}
+	if _, err := manager.ValidateToken(newToken); err != nil {
+		t.Errorf("derived-key token rejected: %v", err)
+	}
+}
+
+func TestDeriveTypeKeys_RequiresHMAC(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	opts := DefaultJWTOptions()
+	opts.DeriveTypeKeys = true
+	if _, err := NewTokenManagerWithKeys(jwt.SigningMethodRS256, key, nil, opts); !errors.Is(err, ErrKeyTypeMismatch) {
+		t.Errorf("error = %v, want ErrKeyTypeMismatch", err)
+	}
+}
+
+func TestDeriveTypeKeys_UnknownTypeRejected(t *testing.T) {
+	manager := newTypeKeysManager(t, false)
+	accessKey, _ := manager.typeKeys.key(AccessToken)
+
+	// 攻击者可以任意选择 type 与 kid，验证时不得为新类型派生密钥
+	for i := 0; i < 3; i++ {
+		tokenType := TokenType(fmt.Sprintf("bogus-%d", i))
+		token := signWithKey(t, accessKey, typeKeyID(tokenType), tokenType)
+		if _, err := manager.ValidateToken(token); !errors.Is(err, ErrSignature) {
+			t.Errorf("%s: error = %v, want ErrSignature", tokenType, err)
+		}
+	}
+	if n := len(manager.typeKeys.keys); n != len(derivedTokenTypes) {
+		t.Errorf("derived key count = %d, want %d", n, len(derivedTokenTypes))
+	}
+
+	if _, err := manager.GenerateToken("user-1", &TokenOptions{TokenType: "service"}); err == nil {
+		t.Error("expected error when signing a token type without a derived key")
+	}
+}
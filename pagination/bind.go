@@ -0,0 +1,272 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+// BindTag DTO 分页字段使用的标签名
+const BindTag = "pagination"
+
+// 排序与游标的查询参数名
+const (
+	FieldSort   = "sort"
+	FieldCursor = "cursor"
+)
+
+// QuerySource 查询参数来源，Hertz 的 *app.RequestContext 与 Gin 的 *gin.Context 都满足该接口
+type QuerySource interface {
+	Query(key string) string
+}
+
+// QueryValues 将 url.Values 适配为 QuerySource，用于 net/http：QueryValues(r.URL.Query())
+type QueryValues url.Values
+
+// Query 实现 QuerySource 接口
+func (v QueryValues) Query(key string) string {
+	return url.Values(v).Get(key)
+}
+
+// BindPagination 按 `pagination` 标签从查询参数填充 DTO 中的分页字段并严格校验，
+// 随后按 `validate` 标签校验整个 DTO（见 errors.ValidateStruct），所有错误合并为一个 *errors.Error 返回。
+//
+//	type ListUsersRequest struct {
+//		Keyword string `json:"keyword" validate:"max=64"`
+//		Limit   int    `pagination:"limit"`
+//		Offset  int    `pagination:"offset"`
+//		Sort    string `pagination:"sort,allowed=created_at|name"`
+//	}
+//
+//	var req ListUsersRequest
+//	if err := pagination.BindPagination(c, &req); err != nil {
+//		return err
+//	}
+//
+// 标签第一项为字段种类：limit、offset、page、per_page（int 字段），cursor、sort（string 字段，sort 也可为 []string）。
+// 之后可跟选项：
+//   - param=name 查询参数名，默认与种类同名
+//   - default=n 参数缺省时的值，limit 与 per_page 默认 DefaultLimit，page 默认 1，offset 默认 0
+//   - allowed=a|b 仅 sort 可用，允许排序的字段；排序值形如 "name,-created_at"，前缀 - 表示降序
+//
+// 与 Normalize 不同，越界值不会被钳制而是返回错误。dto 不是结构体指针或标签写错属于编程错误，会直接 panic。
+func BindPagination(c QuerySource, dto any) error {
+	rv := reflect.ValueOf(dto)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("pagination: BindPagination expects a non-nil pointer to struct, got %T", dto))
+	}
+
+	v := apperrors.NewValidator()
+	bindFields(c, rv.Elem(), v)
+	v.Struct(dto)
+	if v.HasErrors() {
+		return v.GetError()
+	}
+	return nil
+}
+
+// bindFields 填充结构体中带标签的字段，递归处理匿名嵌入的结构体
+func bindFields(c QuerySource, rv reflect.Value, v *apperrors.Validator) {
+	for _, f := range cachedBindFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		raw := strings.TrimSpace(c.Query(f.param))
+
+		switch f.kind {
+		case FieldCursor:
+			fv.SetString(raw)
+		case FieldSort:
+			items, err := parseSort(f, raw)
+			if err != nil {
+				v.AddError(err)
+				continue
+			}
+			if fv.Kind() == reflect.String {
+				fv.SetString(strings.Join(items, ","))
+			} else {
+				fv.Set(reflect.ValueOf(items))
+			}
+		default:
+			n := f.defaultValue
+			if raw != "" {
+				parsed, err := strconv.Atoi(raw)
+				if err != nil {
+					v.AddError(integerError(f.param, raw))
+					continue
+				}
+				n = parsed
+			}
+			if err := validateBoundField(f, n); err != nil {
+				v.AddError(err)
+				continue
+			}
+			fv.SetInt(int64(n))
+		}
+	}
+}
+
+// validateBoundField 按字段种类校验数值
+func validateBoundField(f bindField, n int) *apperrors.ValidationError {
+	switch f.kind {
+	case FieldLimit, FieldPerPage:
+		return validateLimitField(f.param, n)
+	case FieldOffset:
+		return minError(f.param, n, 0)
+	default: // FieldPage
+		return minError(f.param, n, 1)
+	}
+}
+
+// sortFieldPattern 合法的排序字段名
+var sortFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// parseSort 解析并校验排序参数，返回去除空白后的排序项
+func parseSort(f bindField, raw string) ([]string, *apperrors.ValidationError) {
+	if raw == "" {
+		return nil, nil
+	}
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name := strings.TrimPrefix(item, "-")
+		if !sortFieldPattern.MatchString(name) || (len(f.allowed) > 0 && !f.allowed[name]) {
+			return nil, sortError(f, name, raw)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// sortError 构造排序字段不合法的校验错误
+func sortError(f bindField, name, raw string) *apperrors.ValidationError {
+	allowed := f.allowedList
+	ve := apperrors.NewValidationError(f.param, "oneof",
+		fmt.Sprintf("Field '%s' cannot sort by '%s'", f.param, name), raw).
+		WithParams(map[string]interface{}{"allowed": allowed})
+	if len(allowed) > 0 {
+		ve.WithMessages(
+			fmt.Sprintf("%s 不支持按 %s 排序，可选：%s", f.param, name, strings.Join(allowed, ", ")),
+			fmt.Sprintf("%s cannot sort by %s, allowed: %s", f.param, name, strings.Join(allowed, ", ")),
+		)
+	} else {
+		ve.WithMessages(
+			fmt.Sprintf("%s 包含无效的排序字段 %s", f.param, name),
+			fmt.Sprintf("%s contains an invalid sort field %s", f.param, name),
+		)
+	}
+	return ve
+}
+
+// bindField 解析后的分页字段
+type bindField struct {
+	index        []int
+	kind         string
+	param        string
+	defaultValue int
+	allowed      map[string]bool
+	allowedList  []string
+}
+
+// bindFieldCache 按类型缓存解析结果
+var bindFieldCache sync.Map // map[reflect.Type][]bindField
+
+// cachedBindFields 返回类型的分页字段，首次使用时解析标签
+func cachedBindFields(t reflect.Type) []bindField {
+	if fields, ok := bindFieldCache.Load(t); ok {
+		return fields.([]bindField)
+	}
+	fields := parseBindFields(t, nil)
+	bindFieldCache.Store(t, fields)
+	return fields
+}
+
+// parseBindFields 解析结构体中带 `pagination` 标签的字段
+func parseBindFields(t reflect.Type, parent []int) []bindField {
+	var fields []bindField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		tag, ok := f.Tag.Lookup(BindTag)
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				fields = append(fields, parseBindFields(f.Type, index)...)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			panic(fmt.Sprintf("pagination: field %s.%s with %s tag must be exported", t.Name(), f.Name, BindTag))
+		}
+		fields = append(fields, parseBindField(t, f, index, tag))
+	}
+	return fields
+}
+
+// parseBindField 解析单个字段的标签并检查字段类型
+func parseBindField(t reflect.Type, f reflect.StructField, index []int, tag string) bindField {
+	parts := strings.Split(tag, ",")
+	field := bindField{index: index, kind: strings.TrimSpace(parts[0])}
+	field.param = field.kind
+
+	fail := func(format string, args ...interface{}) {
+		panic(fmt.Sprintf("pagination: field %s.%s: %s", t.Name(), f.Name, fmt.Sprintf(format, args...)))
+	}
+
+	switch field.kind {
+	case FieldLimit, FieldPerPage:
+		field.defaultValue = DefaultLimit
+	case FieldPage:
+		field.defaultValue = 1
+	case FieldOffset, FieldCursor, FieldSort:
+	default:
+		fail("unknown kind %q", field.kind)
+	}
+
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch {
+		case key == "param" && value != "":
+			field.param = value
+		case key == "default" && field.kind != FieldCursor && field.kind != FieldSort:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				fail("invalid default %q", value)
+			}
+			field.defaultValue = n
+		case key == "allowed" && field.kind == FieldSort:
+			field.allowed = make(map[string]bool)
+			for _, name := range strings.Split(value, "|") {
+				if name = strings.TrimSpace(name); name != "" {
+					field.allowed[name] = true
+					field.allowedList = append(field.allowedList, name)
+				}
+			}
+		default:
+			fail("invalid option %q", part)
+		}
+	}
+
+	kind := f.Type.Kind()
+	switch field.kind {
+	case FieldCursor:
+		if kind != reflect.String {
+			fail("%s must be a string", field.kind)
+		}
+	case FieldSort:
+		if kind != reflect.String && f.Type != reflect.TypeOf([]string(nil)) {
+			fail("%s must be a string or []string", field.kind)
+		}
+	default:
+		if kind < reflect.Int || kind > reflect.Int64 {
+			fail("%s must be a signed integer", field.kind)
+		}
+	}
+	return field
+}
@@ -0,0 +1,148 @@
+package pagination
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+type listUsersRequest struct {
+	Keyword string   `validate:"max=8"`
+	Limit   int      `pagination:"limit"`
+	Offset  int64    `pagination:"offset"`
+	Sort    []string `pagination:"sort,allowed=created_at|name"`
+}
+
+type pageParams struct {
+	Page    int `pagination:"page"`
+	PerPage int `pagination:"per_page,param=size,default=10"`
+}
+
+type listOrdersRequest struct {
+	pageParams
+	Cursor string `pagination:"cursor"`
+	Sort   string `pagination:"sort"`
+}
+
+func query(raw string) QuerySource {
+	values, _ := url.ParseQuery(raw)
+	return QueryValues(values)
+}
+
+func TestBindPagination(t *testing.T) {
+	var req listUsersRequest
+	if err := BindPagination(query("sort=-created_at,email"), &req); err == nil {
+		t.Fatal("expected error for invalid sort field")
+	}
+
+	req = listUsersRequest{}
+	if err := BindPagination(query("limit=50&offset=100&sort=-created_at, name"), &req); err != nil {
+		t.Fatalf("BindPagination() error = %v", err)
+	}
+	want := listUsersRequest{Limit: 50, Offset: 100, Sort: []string{"-created_at", "name"}}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("BindPagination() = %+v, want %+v", req, want)
+	}
+
+	req = listUsersRequest{}
+	if err := BindPagination(query(""), &req); err != nil {
+		t.Fatalf("BindPagination() error = %v", err)
+	}
+	if req.Limit != DefaultLimit || req.Offset != 0 || req.Sort != nil {
+		t.Errorf("defaults = %+v", req)
+	}
+}
+
+func TestBindPagination_Embedded(t *testing.T) {
+	var req listOrdersRequest
+	if err := BindPagination(query("page=3&size=25&cursor=abc&sort=id,-amount"), &req); err != nil {
+		t.Fatalf("BindPagination() error = %v", err)
+	}
+	if req.Page != 3 || req.PerPage != 25 || req.Cursor != "abc" || req.Sort != "id,-amount" {
+		t.Errorf("BindPagination() = %+v", req)
+	}
+
+	req = listOrdersRequest{}
+	if err := BindPagination(query(""), &req); err != nil {
+		t.Fatalf("BindPagination() error = %v", err)
+	}
+	if req.Page != 1 || req.PerPage != 10 {
+		t.Errorf("defaults = %+v", req)
+	}
+}
+
+func TestBindPagination_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantField string
+		wantRule  string
+	}{
+		{"limit not integer", "limit=abc", FieldLimit, "integer"},
+		{"limit too large", "limit=1000", FieldLimit, "range"},
+		{"negative offset", "offset=-1", FieldOffset, "min"},
+		{"sort not allowed", "sort=password", FieldSort, "oneof"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req listUsersRequest
+			err := BindPagination(query(tt.query), &req)
+			appErr, ok := err.(*apperrors.Error)
+			if !ok {
+				t.Fatalf("BindPagination() error = %v, want *errors.Error", err)
+			}
+			if appErr.Context["field"] != tt.wantField || appErr.Context["rule"] != tt.wantRule {
+				t.Errorf("field = %v, rule = %v, want %s/%s",
+					appErr.Context["field"], appErr.Context["rule"], tt.wantField, tt.wantRule)
+			}
+		})
+	}
+
+	// 分页错误与 validate 标签错误合并返回
+	var req listUsersRequest
+	req.Keyword = "too long keyword"
+	err := BindPagination(query("page=1&per_page=0&limit=0"), &req)
+	appErr, ok := err.(*apperrors.Error)
+	if !ok || appErr.Context["error_count"] != 2 {
+		t.Errorf("BindPagination() error = %#v, want 2 combined errors", err)
+	}
+
+	var orders listOrdersRequest
+	err = BindPagination(query("page=0&size=101"), &orders)
+	if appErr, ok := err.(*apperrors.Error); !ok || appErr.Context["error_count"] != 2 {
+		t.Errorf("BindPagination() error = %#v, want 2 combined errors", err)
+	}
+}
+
+func TestBindPagination_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		dto  any
+	}{
+		{"not pointer", listUsersRequest{}},
+		{"nil pointer", (*listUsersRequest)(nil)},
+		{"unknown kind", &struct {
+			Limit int `pagination:"size"`
+		}{}},
+		{"wrong type", &struct {
+			Limit string `pagination:"limit"`
+		}{}},
+		{"bad option", &struct {
+			Limit int `pagination:"limit,allowed=a"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			_ = BindPagination(query(""), tt.dto)
+		})
+	}
+}
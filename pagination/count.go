@@ -0,0 +1,125 @@
+package pagination
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CountFunc 精确统计总记录数，例如执行 SELECT COUNT(*)。
+type CountFunc func(ctx context.Context) (int64, error)
+
+// EstimateFunc 返回总记录数的近似值，例如读取 pg_class.reltuples。
+type EstimateFunc func(ctx context.Context) (int64, error)
+
+// CountResult 为总数查询结果。
+type CountResult struct {
+	Total      int64 // 总记录数
+	IsEstimate bool  // 是否为估算值
+}
+
+// CountOptions 控制单次 Count 调用的估算行为。
+// 当 Estimate 不为空且估算值 >= EstimateThreshold 时，直接使用估算值而跳过精确计数；
+// 小表估算误差较大且精确计数代价低，因此低于阈值时仍执行精确计数。
+type CountOptions struct {
+	Estimate          EstimateFunc
+	EstimateThreshold int64
+}
+
+// countEntry 缓存条目
+type countEntry struct {
+	result    CountResult
+	expiresAt time.Time
+}
+
+// CountCache 缓存分页查询的总记录数，避免每次翻页都对大表执行 COUNT。
+// 条目在 TTL 后过期，数据变更时可调用 Invalidate 主动失效。
+type CountCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]countEntry
+}
+
+// NewCountCache 创建总数缓存，ttl 为缓存条目的最长有效期。
+func NewCountCache(ttl time.Duration) *CountCache {
+	return &CountCache{
+		ttl:     ttl,
+		entries: make(map[string]countEntry),
+	}
+}
+
+// Get 读取未过期的缓存总数。
+func (c *CountCache) Get(key string) (CountResult, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return CountResult{}, false
+	}
+	return entry.result, true
+}
+
+// Set 写入总数缓存。
+func (c *CountCache) Set(key string, result CountResult) {
+	c.mu.Lock()
+	c.entries[key] = countEntry{result: result, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+// Invalidate 使指定键的缓存失效。
+func (c *CountCache) Invalidate(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// InvalidatePrefix 使所有以 prefix 开头的键失效，便于按表名清理不同过滤条件下的总数。
+func (c *CountCache) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// Purge 清理所有过期条目。
+func (c *CountCache) Purge() {
+	now := time.Now()
+	c.mu.Lock()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// Count 返回 key 对应的总记录数，缓存未命中时调用 count（或 opts 中的估算函数）并写入缓存。
+// opts 为 nil 时始终执行精确计数。
+func (c *CountCache) Count(ctx context.Context, key string, count CountFunc, opts *CountOptions) (CountResult, error) {
+	if result, ok := c.Get(key); ok {
+		return result, nil
+	}
+
+	if opts != nil && opts.Estimate != nil {
+		estimate, err := opts.Estimate(ctx)
+		if err == nil && estimate >= opts.EstimateThreshold {
+			result := CountResult{Total: estimate, IsEstimate: true}
+			c.Set(key, result)
+			return result, nil
+		}
+		// 估算失败或低于阈值时回退到精确计数
+	}
+
+	total, err := count(ctx)
+	if err != nil {
+		return CountResult{}, err
+	}
+	result := CountResult{Total: total}
+	c.Set(key, result)
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCountCache_CachesExactCount(t *testing.T) {
+	cache := NewCountCache(time.Minute)
+	calls := 0
+	count := func(ctx context.Context) (int64, error) {
+		calls++
+		return 42, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := cache.Count(context.Background(), "users", count, nil)
+		if err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+		if result.Total != 42 || result.IsEstimate {
+			t.Errorf("Count() = %+v, want exact 42", result)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("count called %d times, want 1", calls)
+	}
+
+	cache.Invalidate("users")
+	if _, err := cache.Count(context.Background(), "users", count, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("count called %d times after Invalidate, want 2", calls)
+	}
+}
+
+func TestCountCache_TTL(t *testing.T) {
+	cache := NewCountCache(10 * time.Millisecond)
+	cache.Set("orders", CountResult{Total: 7})
+
+	if _, ok := cache.Get("orders"); !ok {
+		t.Fatal("expected cached value")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("orders"); ok {
+		t.Error("expected value to expire")
+	}
+
+	cache.Purge()
+	if len(cache.entries) != 0 {
+		t.Errorf("Purge() left %d entries", len(cache.entries))
+	}
+}
+
+func TestCountCache_Estimate(t *testing.T) {
+	exact := func(ctx context.Context) (int64, error) { return 10, nil }
+
+	tests := []struct {
+		name         string
+		estimate     EstimateFunc
+		threshold    int64
+		wantTotal    int64
+		wantEstimate bool
+	}{
+		{
+			name:         "above threshold uses estimate",
+			estimate:     func(ctx context.Context) (int64, error) { return 1_000_000, nil },
+			threshold:    10_000,
+			wantTotal:    1_000_000,
+			wantEstimate: true,
+		},
+		{
+			name:      "below threshold falls back to exact",
+			estimate:  func(ctx context.Context) (int64, error) { return 12, nil },
+			threshold: 10_000,
+			wantTotal: 10,
+		},
+		{
+			name:      "estimator error falls back to exact",
+			estimate:  func(ctx context.Context) (int64, error) { return 0, errors.New("no stats") },
+			wantTotal: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCountCache(time.Minute)
+			result, err := cache.Count(context.Background(), "k", exact, &CountOptions{
+				Estimate:          tt.estimate,
+				EstimateThreshold: tt.threshold,
+			})
+			if err != nil {
+				t.Fatalf("Count() error = %v", err)
+			}
+			if result.Total != tt.wantTotal || result.IsEstimate != tt.wantEstimate {
+				t.Errorf("Count() = %+v, want Total=%d IsEstimate=%v", result, tt.wantTotal, tt.wantEstimate)
+			}
+		})
+	}
+}
+
+func TestCountCache_InvalidatePrefix(t *testing.T) {
+	cache := NewCountCache(time.Minute)
+	cache.Set("users:active", CountResult{Total: 1})
+	cache.Set("users:all", CountResult{Total: 2})
+	cache.Set("orders:all", CountResult{Total: 3})
+
+	cache.InvalidatePrefix("users:")
+
+	if _, ok := cache.Get("users:active"); ok {
+		t.Error("users:active should be invalidated")
+	}
+	if _, ok := cache.Get("orders:all"); !ok {
+		t.Error("orders:all should remain cached")
+	}
+}
+
+func TestOffsetResponse_CalculateWithCount(t *testing.T) {
+	var resp OffsetResponse
+	resp.CalculateWithCount(OffsetRequest{Offset: 0, Limit: 20}, CountResult{Total: 500000, IsEstimate: true}, 20)
+
+	if !resp.IsEstimate || resp.Total != 500000 || !resp.HasMore {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
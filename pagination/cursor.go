@@ -3,13 +3,13 @@
 package pagination
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/iwen-conf/utils-pkg/crypto/macsign"
 )
 
 // 哨兵错误，便于使用 errors.Is 判断错误类型
@@ -99,19 +99,20 @@ func (Base64JSONCodec) Decode(s string, v any) error {
 // 格式：v1.{payload}.{sig}
 // 请使用 NewHMACCodec 构造，不要直接创建零值。
 type HMACCodec struct {
-	key   []byte        // 私有字段，防止外部修改
-	inner CursorCodec
+	signer *macsign.Signer // 私有字段，防止外部修改
+	inner  CursorCodec
 }
 
 // NewHMACCodec 创建带 HMAC-SHA256 签名的游标编解码器。
 // key 为签名密钥，建议长度 >= 32 字节。若 key 长度不足会返回警告错误（但仍可使用）。
 func NewHMACCodec(key []byte) (*HMACCodec, error) {
-	if len(key) == 0 {
+	signer, err := macsign.New(key, macsign.EncodingBase64URL)
+	if err != nil {
 		return nil, ErrEmptyHMACKey
 	}
 	codec := &HMACCodec{
-		key:   key,
-		inner: Base64JSONCodec{},
+		signer: signer,
+		inner:  Base64JSONCodec{},
 	}
 	if len(key) < RecommendedHMACKeySize {
 		return codec, ErrHMACKeyTooShort // 返回警告但不阻止创建
@@ -123,37 +124,32 @@ func (c *HMACCodec) Encode(v any) (string, error) {
 	if c.inner == nil {
 		c.inner = Base64JSONCodec{}
 	}
+	if c.signer == nil {
+		return "", ErrEmptyHMACKey
+	}
 	payload, err := c.inner.Encode(v)
 	if err != nil {
 		return "", err
 	}
-	mac := hmac.New(sha256.New, c.key)
-	mac.Write([]byte(payload))
-	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
-	// 使用 strings.Builder 减少内存分配
-	var sb strings.Builder
-	sb.Grow(len("v1.") + len(payload) + len(".") + len(sig))
-	sb.WriteString("v1.")
-	sb.WriteString(payload)
-	sb.WriteByte('.')
-	sb.WriteString(sig)
-	return sb.String(), nil
+	return "v1." + c.signer.Seal(payload), nil
 }
 
 func (c *HMACCodec) Decode(s string, v any) error {
 	if c.inner == nil {
 		c.inner = Base64JSONCodec{}
 	}
-	parts := strings.Split(s, ".")
-	if len(parts) != 3 || parts[0] != "v1" {
+	if c.signer == nil {
+		return ErrEmptyHMACKey
+	}
+	token, ok := strings.CutPrefix(s, "v1.")
+	if !ok {
 		return ErrInvalidCursorFormat
 	}
-	payload := parts[1]
-	sig := parts[2]
-	mac := hmac.New(sha256.New, c.key)
-	mac.Write([]byte(payload))
-	expSig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
-	if !hmac.Equal([]byte(sig), []byte(expSig)) {
+	payload, err := c.signer.Open(token)
+	if errors.Is(err, macsign.ErrInvalidToken) {
+		return ErrInvalidCursorFormat
+	}
+	if err != nil {
 		return ErrInvalidSignature
 	}
 	return c.inner.Decode(payload, v)
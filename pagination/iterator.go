@@ -0,0 +1,194 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrStopIteration 在 ForEach 回调中返回该错误可提前结束遍历，ForEach 本身返回 nil。
+var ErrStopIteration = errors.New("pagination: stop iteration")
+
+// OffsetFetchFunc 按偏移量请求拉取一页数据。
+type OffsetFetchFunc[T any] func(ctx context.Context, req OffsetRequest) ([]T, error)
+
+// CursorFetchFunc 按游标请求拉取一页数据，返回的 CursorResponse 决定是否继续翻页。
+type CursorFetchFunc[T any] func(ctx context.Context, req CursorRequest) ([]T, CursorResponse, error)
+
+// IteratorOptions 控制迭代器的翻页行为。
+type IteratorOptions struct {
+	// Limit 每页条数，按 Normalize 规则应用默认值与上限
+	Limit int
+	// Interval 两次拉取之间的最小间隔，用于限制对下游的请求速率；0 表示不限制
+	Interval time.Duration
+	// MaxPages 最多拉取的页数，0 表示不限制
+	MaxPages int
+}
+
+// PageIterator 在服务端逐页拉取并逐条遍历全部数据，适用于批处理任务。
+//
+//	it := pagination.NewOffsetIterator(ctx, fetch, pagination.IteratorOptions{Limit: 100})
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type PageIterator[T any] struct {
+	ctx      context.Context
+	fetch    func(ctx context.Context) ([]T, bool, error)
+	interval time.Duration
+	maxPages int
+
+	items     []T
+	index     int
+	pages     int
+	more      bool
+	err       error
+	lastFetch time.Time
+}
+
+// NewOffsetIterator 创建基于偏移量的迭代器。
+// 当返回的记录数少于 Limit 时视为最后一页，无需额外的 COUNT 查询。
+func NewOffsetIterator[T any](ctx context.Context, fetch OffsetFetchFunc[T], opts IteratorOptions) *PageIterator[T] {
+	req := OffsetRequest{Limit: opts.Limit}
+	req.Normalize()
+
+	return newPageIterator(ctx, opts, func(ctx context.Context) ([]T, bool, error) {
+		items, err := fetch(ctx, req)
+		if err != nil {
+			return nil, false, err
+		}
+		req.Offset += len(items)
+		return items, len(items) >= req.Limit, nil
+	})
+}
+
+// NewCursorIterator 创建基于游标的迭代器，从 startCursor 开始（为空表示第一页）。
+// 当 HasMore 为 false 或 NextCursor 为空时结束。
+func NewCursorIterator[T any](ctx context.Context, fetch CursorFetchFunc[T], startCursor string, opts IteratorOptions) *PageIterator[T] {
+	req := CursorRequest{Cursor: startCursor, Limit: opts.Limit}
+	req.Normalize()
+
+	return newPageIterator(ctx, opts, func(ctx context.Context) ([]T, bool, error) {
+		items, resp, err := fetch(ctx, req)
+		if err != nil {
+			return nil, false, err
+		}
+		req.Cursor = resp.NextCursor
+		return items, resp.HasMore && resp.NextCursor != "", nil
+	})
+}
+
+func newPageIterator[T any](ctx context.Context, opts IteratorOptions, fetch func(ctx context.Context) ([]T, bool, error)) *PageIterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &PageIterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		interval: opts.Interval,
+		maxPages: opts.MaxPages,
+		index:    -1,
+		more:     true,
+	}
+}
+
+// Next 前进到下一条记录，没有更多数据或发生错误时返回 false。
+func (it *PageIterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for {
+		if it.index+1 < len(it.items) {
+			it.index++
+			return true
+		}
+		if !it.more || (it.maxPages > 0 && it.pages >= it.maxPages) {
+			return false
+		}
+		if !it.fetchPage() {
+			return false
+		}
+	}
+}
+
+// fetchPage 拉取下一页，遵守速率限制与上下文取消。
+func (it *PageIterator[T]) fetchPage() bool {
+	if err := it.wait(); err != nil {
+		it.err = err
+		return false
+	}
+
+	items, more, err := it.fetch(it.ctx)
+	it.lastFetch = time.Now()
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.pages++
+	it.items = items
+	it.index = -1
+	// 空页说明数据已经取完，避免下游返回错误的 HasMore 导致死循环
+	it.more = more && len(items) > 0
+	return true
+}
+
+// wait 等待到满足最小拉取间隔。
+func (it *PageIterator[T]) wait() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	if it.interval <= 0 || it.lastFetch.IsZero() {
+		return nil
+	}
+	delay := it.interval - time.Since(it.lastFetch)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-it.ctx.Done():
+		return it.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Item 返回当前记录，需在 Next 返回 true 后调用。
+func (it *PageIterator[T]) Item() T {
+	if it.index < 0 || it.index >= len(it.items) {
+		var zero T
+		return zero
+	}
+	return it.items[it.index]
+}
+
+// Err 返回遍历过程中遇到的错误。
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// Pages 返回已拉取的页数。
+func (it *PageIterator[T]) Pages() int {
+	return it.pages
+}
+
+// ForEach 遍历剩余的全部记录，每条记录前检查上下文是否已取消。
+// fn 返回 ErrStopIteration 时提前结束并返回 nil，返回其他错误时立即结束并返回该错误。
+func (it *PageIterator[T]) ForEach(fn func(item T) error) error {
+	for it.Next() {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return err
+		}
+		if err := fn(it.Item()); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return it.err
+}
@@ -0,0 +1,128 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func offsetSource(total int) OffsetFetchFunc[int] {
+	return func(ctx context.Context, req OffsetRequest) ([]int, error) {
+		var items []int
+		for i := req.Offset; i < req.Offset+req.Limit && i < total; i++ {
+			items = append(items, i)
+		}
+		return items, nil
+	}
+}
+
+func TestOffsetIterator_AllItems(t *testing.T) {
+	for _, total := range []int{0, 1, 9, 10, 11, 35} {
+		it := NewOffsetIterator(context.Background(), offsetSource(total), IteratorOptions{Limit: 10})
+		var got []int
+		for it.Next() {
+			got = append(got, it.Item())
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("total=%d: Err() = %v", total, err)
+		}
+		if len(got) != total {
+			t.Fatalf("total=%d: got %d items", total, len(got))
+		}
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("total=%d: item %d = %d", total, i, v)
+			}
+		}
+	}
+}
+
+func TestCursorIterator(t *testing.T) {
+	fetch := func(ctx context.Context, req CursorRequest) ([]string, CursorResponse, error) {
+		page := 0
+		if req.Cursor != "" {
+			page, _ = strconv.Atoi(req.Cursor)
+		}
+		items := []string{"p" + strconv.Itoa(page) + "a", "p" + strconv.Itoa(page) + "b"}
+		resp := CursorResponse{HasMore: page < 2}
+		if resp.HasMore {
+			resp.NextCursor = strconv.Itoa(page + 1)
+		}
+		return items, resp, nil
+	}
+
+	it := NewCursorIterator(context.Background(), fetch, "", IteratorOptions{Limit: 2})
+	var got []string
+	if err := it.ForEach(func(item string) error {
+		got = append(got, item)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEach() error = %v", err)
+	}
+	if len(got) != 6 || it.Pages() != 3 {
+		t.Errorf("got %v over %d pages, want 6 items over 3 pages", got, it.Pages())
+	}
+}
+
+func TestPageIterator_StopAndErrors(t *testing.T) {
+	it := NewOffsetIterator(context.Background(), offsetSource(100), IteratorOptions{Limit: 10})
+	count := 0
+	err := it.ForEach(func(item int) error {
+		count++
+		if item == 14 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil || count != 15 {
+		t.Errorf("ForEach() = %v after %d items, want nil after 15", err, count)
+	}
+
+	fetchErr := errors.New("db down")
+	failing := func(ctx context.Context, req OffsetRequest) ([]int, error) {
+		if req.Offset > 0 {
+			return nil, fetchErr
+		}
+		return []int{1, 2}, nil
+	}
+	it = NewOffsetIterator(context.Background(), failing, IteratorOptions{Limit: 2})
+	for it.Next() {
+	}
+	if !errors.Is(it.Err(), fetchErr) {
+		t.Errorf("Err() = %v, want %v", it.Err(), fetchErr)
+	}
+}
+
+func TestPageIterator_ContextAndRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewOffsetIterator(ctx, offsetSource(100), IteratorOptions{Limit: 10})
+	err := it.ForEach(func(item int) error {
+		if item == 5 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach() = %v, want context.Canceled", err)
+	}
+
+	start := time.Now()
+	it = NewOffsetIterator(context.Background(), offsetSource(30), IteratorOptions{Limit: 10, Interval: 20 * time.Millisecond})
+	for it.Next() {
+	}
+	// 4 次拉取（最后一次为空页）之间至少间隔 3 次
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("rate limit not applied, elapsed %v", elapsed)
+	}
+
+	it = NewOffsetIterator(context.Background(), offsetSource(100), IteratorOptions{Limit: 10, MaxPages: 2})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 20 {
+		t.Errorf("MaxPages=2 yielded %d items, want 20", n)
+	}
+}
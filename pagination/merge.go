@@ -0,0 +1,184 @@
+package pagination
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PageFetcher 多数据源合并分页中的单个数据源（分片、下游服务等）。
+// req.Cursor 为 cursorOf(该源上一条已返回条目) 的结果，首次为空，数据源应返回其后的 req.Limit 条记录，
+// 并按与 MergePages 的 cmp 一致的顺序排序；只使用返回的 CursorResponse.HasMore。
+type PageFetcher[T any] = CursorFetchFunc[T]
+
+// MergedPage 合并后的一页数据，NextCursor 为包含各数据源位置的组合游标
+type MergedPage[T any] struct {
+	Items []T `json:"items"`
+	CursorResponse
+}
+
+// mergeCursor 组合游标，按数据源顺序记录各自的位置
+type mergeCursor struct {
+	Sources []mergeSourceState `json:"s"`
+}
+
+// mergeSourceState 单个数据源的位置
+type mergeSourceState struct {
+	Cursor string `json:"c,omitempty"` // 该源最后一条已返回条目的游标
+	Done   bool   `json:"d,omitempty"` // 该源已无更多数据
+}
+
+// MergePages 对多个有序数据源执行 k 路归并分页，返回统一排序的一页和组合游标。
+// cmp 定义全局排序，相等时按数据源在 sources 中的顺序排列以保证翻页稳定；
+// cursorOf 返回条目在其数据源中的游标（通常是排序键），用于下次从该条目之后继续。
+// codec 为 nil 时使用 Base64JSONCodec，对外暴露时建议使用 HMACCodec 防止篡改。
+func MergePages[T any](
+	ctx context.Context,
+	sources []PageFetcher[T],
+	cmp func(a, b T) int,
+	cursorOf func(T) string,
+	req CursorRequest,
+	codec CursorCodec,
+) (MergedPage[T], error) {
+	req.Normalize()
+	if codec == nil {
+		codec = Base64JSONCodec{}
+	}
+
+	state := mergeCursor{Sources: make([]mergeSourceState, len(sources))}
+	if req.Cursor != "" {
+		if err := codec.Decode(req.Cursor, &state); err != nil {
+			return MergedPage[T]{}, err
+		}
+		if len(state.Sources) != len(sources) {
+			return MergedPage[T]{}, fmt.Errorf("%w: cursor has %d sources, expected %d",
+				ErrInvalidCursorFormat, len(state.Sources), len(sources))
+		}
+	}
+
+	pages, err := fetchSources(ctx, sources, state, req.Limit)
+	if err != nil {
+		return MergedPage[T]{}, err
+	}
+
+	// k 路归并：每个数据源最多贡献 Limit 条，取全局前 Limit 条
+	h := &mergeHeap[T]{pages: pages, cmp: cmp}
+	for i := range pages {
+		if len(pages[i].items) > 0 {
+			h.sources = append(h.sources, i)
+		}
+	}
+	heap.Init(h)
+
+	items := make([]T, 0, req.Limit)
+	for len(items) < req.Limit && h.Len() > 0 {
+		p := &pages[h.sources[0]]
+		items = append(items, p.items[p.pos])
+		p.pos++
+		if p.pos < len(p.items) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	// 更新各数据源位置
+	hasMore := false
+	for i := range pages {
+		p := &pages[i]
+		s := &state.Sources[i]
+		if s.Done {
+			continue
+		}
+		if p.pos > 0 {
+			s.Cursor = cursorOf(p.items[p.pos-1])
+		}
+		s.Done = p.pos == len(p.items) && !p.hasMore
+		if !s.Done {
+			hasMore = true
+		}
+	}
+
+	page := MergedPage[T]{Items: items}
+	page.HasMore = hasMore
+	if hasMore {
+		next, err := codec.Encode(state)
+		if err != nil {
+			return MergedPage[T]{}, err
+		}
+		page.NextCursor = next
+	}
+	return page, nil
+}
+
+// sourcePage 单个数据源本次拉取的数据及消费位置
+type sourcePage[T any] struct {
+	items   []T
+	hasMore bool
+	pos     int
+}
+
+// fetchSources 并发拉取所有未结束的数据源
+func fetchSources[T any](ctx context.Context, sources []PageFetcher[T], state mergeCursor, limit int) ([]sourcePage[T], error) {
+	pages := make([]sourcePage[T], len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for i, fetch := range sources {
+		if state.Sources[i].Done {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, fetch PageFetcher[T]) {
+			defer wg.Done()
+			items, resp, err := fetch(ctx, CursorRequest{Cursor: state.Sources[i].Cursor, Limit: limit})
+			if err != nil {
+				errs[i] = fmt.Errorf("pagination: source %d: %w", i, err)
+				return
+			}
+			if len(items) > limit {
+				items = items[:limit]
+				resp.HasMore = true
+			}
+			pages[i] = sourcePage[T]{items: items, hasMore: resp.HasMore}
+		}(i, fetch)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// mergeHeap 以各数据源当前条目排序的最小堆，元素为数据源下标
+type mergeHeap[T any] struct {
+	sources []int
+	pages   []sourcePage[T]
+	cmp     func(a, b T) int
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.sources) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.sources[i], h.sources[j]
+	pa, pb := &h.pages[a], &h.pages[b]
+	if c := h.cmp(pa.items[pa.pos], pb.items[pb.pos]); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.sources[i], h.sources[j] = h.sources[j], h.sources[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.sources = append(h.sources, x.(int)) }
+
+func (h *mergeHeap[T]) Pop() any {
+	old := h.sources
+	x := old[len(old)-1]
+	h.sources = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,203 @@
+package pagination
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// sortedSource 基于有序整数切片的键集数据源，游标为最后一条的值
+func sortedSource(data []int) PageFetcher[int] {
+	return func(ctx context.Context, req CursorRequest) ([]int, CursorResponse, error) {
+		start := 0
+		if req.Cursor != "" {
+			after, err := strconv.Atoi(req.Cursor)
+			if err != nil {
+				return nil, CursorResponse{}, err
+			}
+			start, _ = slices.BinarySearch(data, after+1)
+		}
+		end := min(start+req.Limit, len(data))
+		return data[start:end], CursorResponse{HasMore: end < len(data)}, nil
+	}
+}
+
+func mergeAll(t *testing.T, sources []PageFetcher[int], limit int, codec CursorCodec) ([]int, int) {
+	t.Helper()
+	var (
+		got    []int
+		cursor string
+		pages  int
+	)
+	for {
+		page, err := MergePages(context.Background(), sources, cmp.Compare[int], strconv.Itoa,
+			CursorRequest{Cursor: cursor, Limit: limit}, codec)
+		if err != nil {
+			t.Fatalf("MergePages() error = %v", err)
+		}
+		pages++
+		got = append(got, page.Items...)
+		if !page.HasMore {
+			if page.NextCursor != "" {
+				t.Fatalf("expected empty NextCursor on last page, got %q", page.NextCursor)
+			}
+			return got, pages
+		}
+		cursor = page.NextCursor
+		if pages > 100 {
+			t.Fatal("pagination did not terminate")
+		}
+	}
+}
+
+func TestMergePages_AllItemsInOrder(t *testing.T) {
+	shards := [][]int{
+		{1, 4, 7, 10, 13, 16},
+		{2, 5, 8},
+		{0, 3, 6, 9, 11, 12, 14, 15, 17, 18, 19},
+	}
+	var sources []PageFetcher[int]
+	var want []int
+	for _, shard := range shards {
+		sources = append(sources, sortedSource(shard))
+		want = append(want, shard...)
+	}
+	slices.Sort(want)
+
+	for _, limit := range []int{1, 3, 5, 7, 20} {
+		got, _ := mergeAll(t, sources, limit, nil)
+		if !slices.Equal(got, want) {
+			t.Errorf("limit=%d: got %v, want %v", limit, got, want)
+		}
+	}
+}
+
+func TestMergePages_PageSize(t *testing.T) {
+	sources := []PageFetcher[int]{
+		sortedSource([]int{1, 3, 5, 7, 9}),
+		sortedSource([]int{2, 4, 6, 8, 10}),
+	}
+	page, err := MergePages(context.Background(), sources, cmp.Compare[int], strconv.Itoa, CursorRequest{Limit: 4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(page.Items, []int{1, 2, 3, 4}) || !page.HasMore {
+		t.Fatalf("first page = %v (hasMore=%v)", page.Items, page.HasMore)
+	}
+
+	_, pages := mergeAll(t, sources, 4, nil)
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+}
+
+func TestMergePages_TiesFollowSourceOrder(t *testing.T) {
+	type row struct {
+		Key    int
+		Source string
+	}
+	source := func(name string, keys ...int) PageFetcher[row] {
+		return func(ctx context.Context, req CursorRequest) ([]row, CursorResponse, error) {
+			var items []row
+			for _, k := range keys {
+				if req.Cursor != "" {
+					after, _ := strconv.Atoi(req.Cursor)
+					if k <= after {
+						continue
+					}
+				}
+				items = append(items, row{Key: k, Source: name})
+			}
+			return items, CursorResponse{}, nil
+		}
+	}
+	sources := []PageFetcher[row]{source("a", 1, 2), source("b", 1, 2)}
+	compare := func(x, y row) int { return cmp.Compare(x.Key, y.Key) }
+	cursorOf := func(r row) string { return strconv.Itoa(r.Key) }
+
+	var got []string
+	cursor := ""
+	for {
+		page, err := MergePages(context.Background(), sources, compare, cursorOf, CursorRequest{Cursor: cursor, Limit: 3}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range page.Items {
+			got = append(got, r.Source+strconv.Itoa(r.Key))
+		}
+		if !page.HasMore {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if want := []string{"a1", "b1", "a2", "b2"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMergePages_DoneSourcesNotRefetched(t *testing.T) {
+	calls := 0
+	short := sortedSource([]int{0})
+	counting := func(ctx context.Context, req CursorRequest) ([]int, CursorResponse, error) {
+		calls++
+		return short(ctx, req)
+	}
+	sources := []PageFetcher[int]{counting, sortedSource([]int{1, 2, 3, 4, 5, 6})}
+
+	got, pages := mergeAll(t, sources, 2, nil)
+	if len(got) != 7 {
+		t.Fatalf("got %v", got)
+	}
+	if calls != 1 {
+		t.Errorf("exhausted source fetched %d times over %d pages, want 1", calls, pages)
+	}
+}
+
+func TestMergePages_HMACCodec(t *testing.T) {
+	codec, err := NewHMACCodec([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := []PageFetcher[int]{sortedSource([]int{1, 3, 5}), sortedSource([]int{2, 4, 6})}
+
+	got, _ := mergeAll(t, sources, 2, codec)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("got %v", got)
+	}
+
+	page, _ := MergePages(context.Background(), sources, cmp.Compare[int], strconv.Itoa, CursorRequest{Limit: 2}, codec)
+	tampered := page.NextCursor[:len(page.NextCursor)-1] + "x"
+	if _, err := MergePages(context.Background(), sources, cmp.Compare[int], strconv.Itoa, CursorRequest{Cursor: tampered, Limit: 2}, codec); err == nil {
+		t.Error("expected error for tampered cursor")
+	}
+}
+
+func TestMergePages_CursorSourceMismatch(t *testing.T) {
+	two := []PageFetcher[int]{sortedSource([]int{1, 3}), sortedSource([]int{2, 4})}
+	page, err := MergePages(context.Background(), two, cmp.Compare[int], strconv.Itoa, CursorRequest{Limit: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	three := append(two, sortedSource([]int{5}))
+	_, err = MergePages(context.Background(), three, cmp.Compare[int], strconv.Itoa, CursorRequest{Cursor: page.NextCursor, Limit: 1}, nil)
+	if !errors.Is(err, ErrInvalidCursorFormat) {
+		t.Errorf("expected ErrInvalidCursorFormat, got %v", err)
+	}
+}
+
+func TestMergePages_FetchError(t *testing.T) {
+	boom := errors.New("shard unavailable")
+	failing := func(ctx context.Context, req CursorRequest) ([]int, CursorResponse, error) {
+		return nil, CursorResponse{}, boom
+	}
+	sources := []PageFetcher[int]{sortedSource([]int{1}), failing}
+
+	_, err := MergePages(context.Background(), sources, cmp.Compare[int], strconv.Itoa, CursorRequest{}, nil)
+	if !errors.Is(err, boom) {
+		t.Errorf("expected source error, got %v", err)
+	}
+}
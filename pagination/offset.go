@@ -14,6 +14,7 @@ type OffsetResponse struct {
 	Limit  int   `json:"limit"`            // 每页条数
 	Total  int64 `json:"total"`            // 总记录数
 	HasMore bool `json:"has_more"`         // 是否还有更多数据
+	IsEstimate bool `json:"is_estimate,omitempty"` // Total 是否为估算值
 }
 
 // Normalize 对请求的 Offset 和 Limit 进行归一化（默认值与上限钳制）。
@@ -57,6 +58,12 @@ func (r *OffsetResponse) Calculate(req OffsetRequest, total int64, actualCount i
 	r.HasMore = int64(req.Offset+actualCount) < total
 }
 
+// CalculateWithCount 使用 CountCache 返回的结果计算分页元信息，并标记总数是否为估算值。
+func (r *OffsetResponse) CalculateWithCount(req OffsetRequest, count CountResult, actualCount int) {
+	r.Calculate(req, count.Total, actualCount)
+	r.IsEstimate = count.IsEstimate
+}
+
 // IsFirstPage 判断是否为第一页。
 func (r *OffsetRequest) IsFirstPage() bool {
 	return r.Offset == 0
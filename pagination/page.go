@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 页码分页的查询参数名
+const (
+	FieldPage    = "page"
+	FieldPerPage = "per_page"
+)
+
+// PageResult 基于页码的分页响应，供 handler 统一返回列表数据。
+// Page 从 1 开始；Total 为 0 时 TotalPages 为 0。
+type PageResult[T any] struct {
+	Items      []T        `json:"items"`
+	Total      int64      `json:"total"`
+	IsEstimate bool       `json:"is_estimate,omitempty"` // Total 是否为估算值
+	Page       int        `json:"page"`
+	PerPage    int        `json:"per_page"`
+	TotalPages int        `json:"total_pages"`
+	HasNext    bool       `json:"has_next"`
+	HasPrev    bool       `json:"has_prev"`
+	Links      *PageLinks `json:"links,omitempty"` // 调用 BuildLinks 后才输出
+}
+
+// PageLinks 分页导航链接，没有对应页时为空字符串。
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// NewPageResult 根据当前页数据与总数构建分页响应。
+// page 小于 1 时视为 1，perPage 按 DefaultLimit/MaxLimit 归一化；items 为 nil 时输出空数组。
+func NewPageResult[T any](items []T, total int64, page, perPage int) PageResult[T] {
+	if page < 1 {
+		page = 1
+	}
+	req := OffsetRequest{Limit: perPage}
+	req.Normalize()
+	perPage = req.Limit
+
+	if items == nil {
+		items = []T{}
+	}
+	if total < 0 {
+		total = 0
+	}
+	totalPages := int((total + int64(perPage) - 1) / int64(perPage))
+
+	return PageResult[T]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// NewPageResultWithCount 使用 CountCache 返回的结果构建分页响应，并标记总数是否为估算值。
+func NewPageResultWithCount[T any](items []T, count CountResult, page, perPage int) PageResult[T] {
+	r := NewPageResult(items, count.Total, page, perPage)
+	r.IsEstimate = count.IsEstimate
+	return r
+}
+
+// BuildLinks 以 baseURL 为基础生成 self/first/last/next/prev 链接。
+// baseURL 中已有的查询参数（如筛选条件）会保留，page 与 per_page 会被覆盖。
+// 当前页超出最后一页时，prev 指向最后一页。
+func (r *PageResult[T]) BuildLinks(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base url: %w", err)
+	}
+
+	lastPage := max(r.TotalPages, 1)
+	links := &PageLinks{
+		Self:  pageURL(u, r.Page, r.PerPage),
+		First: pageURL(u, 1, r.PerPage),
+		Last:  pageURL(u, lastPage, r.PerPage),
+	}
+	if r.HasNext {
+		links.Next = pageURL(u, r.Page+1, r.PerPage)
+	}
+	if r.HasPrev {
+		links.Prev = pageURL(u, min(r.Page-1, lastPage), r.PerPage)
+	}
+	r.Links = links
+	return nil
+}
+
+// LinkHeader 返回 RFC 5988 格式的 Link 响应头，未调用 BuildLinks 时返回空字符串。
+//
+//	<https://api.example.com/users?page=3&per_page=20>; rel="next", <...>; rel="last"
+func (r *PageResult[T]) LinkHeader() string {
+	if r.Links == nil {
+		return ""
+	}
+	return r.Links.Header()
+}
+
+// Header 返回 RFC 5988 格式的 Link 响应头，按 first、prev、next、last 的顺序输出。
+func (l PageLinks) Header() string {
+	var parts []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", l.First},
+		{"prev", l.Prev},
+		{"next", l.Next},
+		{"last", l.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, fmt.Sprintf(`<%s>; rel="%s"`, link.url, link.rel))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pageURL 复制 base 并设置页码参数
+func pageURL(base *url.URL, page, perPage int) string {
+	u := *base
+	q := u.Query()
+	q.Set(FieldPage, strconv.Itoa(page))
+	q.Set(FieldPerPage, strconv.Itoa(perPage))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,124 @@
+package pagination
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewPageResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		total          int64
+		page           int
+		perPage        int
+		wantPage       int
+		wantPerPage    int
+		wantTotalPages int
+		wantNext       bool
+		wantPrev       bool
+	}{
+		{"first page", 45, 1, 20, 1, 20, 3, true, false},
+		{"middle page", 45, 2, 20, 2, 20, 3, true, true},
+		{"last page", 45, 3, 20, 3, 20, 3, false, true},
+		{"exact multiple", 40, 2, 20, 2, 20, 2, false, true},
+		{"empty", 0, 1, 20, 1, 20, 0, false, false},
+		{"page below one", 45, 0, 20, 1, 20, 3, true, false},
+		{"default per page", 45, 1, 0, 1, DefaultLimit, 3, true, false},
+		{"per page beyond max", 450, 1, MaxLimit + 1, 1, MaxLimit, 5, true, false},
+		{"beyond last page", 45, 5, 20, 5, 20, 3, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewPageResult([]int{1}, tt.total, tt.page, tt.perPage)
+			if r.Page != tt.wantPage || r.PerPage != tt.wantPerPage || r.TotalPages != tt.wantTotalPages {
+				t.Errorf("page=%d per_page=%d total_pages=%d, want %d %d %d",
+					r.Page, r.PerPage, r.TotalPages, tt.wantPage, tt.wantPerPage, tt.wantTotalPages)
+			}
+			if r.HasNext != tt.wantNext || r.HasPrev != tt.wantPrev {
+				t.Errorf("has_next=%v has_prev=%v, want %v %v", r.HasNext, r.HasPrev, tt.wantNext, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestPageResult_JSON(t *testing.T) {
+	r := NewPageResult[string](nil, 0, 1, 10)
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"items":[],"total":0,"page":1,"per_page":10,"total_pages":0,"has_next":false,"has_prev":false}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
+
+func TestNewPageResultWithCount(t *testing.T) {
+	r := NewPageResultWithCount([]int{1}, CountResult{Total: 500000, IsEstimate: true}, 2, 20)
+	if !r.IsEstimate || r.Total != 500000 || r.TotalPages != 25000 || !r.HasNext {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"is_estimate":true`) {
+		t.Errorf("json = %s, want is_estimate", data)
+	}
+}
+
+func TestPageResult_BuildLinks(t *testing.T) {
+	r := NewPageResult([]int{1, 2}, 45, 2, 20)
+	if err := r.BuildLinks("https://api.example.com/users?status=active&page=9"); err != nil {
+		t.Fatal(err)
+	}
+
+	base := "https://api.example.com/users?"
+	want := PageLinks{
+		Self:  base + "page=2&per_page=20&status=active",
+		First: base + "page=1&per_page=20&status=active",
+		Last:  base + "page=3&per_page=20&status=active",
+		Next:  base + "page=3&per_page=20&status=active",
+		Prev:  base + "page=1&per_page=20&status=active",
+	}
+	if *r.Links != want {
+		t.Errorf("links = %+v, want %+v", *r.Links, want)
+	}
+
+	header := r.LinkHeader()
+	wantHeader := `<` + want.First + `>; rel="first", <` + want.Prev + `>; rel="prev", <` +
+		want.Next + `>; rel="next", <` + want.Last + `>; rel="last"`
+	if header != wantHeader {
+		t.Errorf("LinkHeader() = %s, want %s", header, wantHeader)
+	}
+}
+
+func TestPageResult_BuildLinksEdges(t *testing.T) {
+	first := NewPageResult([]int{1}, 5, 1, 20)
+	_ = first.BuildLinks("/items")
+	if first.Links.Prev != "" || first.Links.Next != "" {
+		t.Errorf("single page should have no prev/next: %+v", first.Links)
+	}
+	if first.Links.Last != "/items?page=1&per_page=20" {
+		t.Errorf("Last = %s", first.Links.Last)
+	}
+	if h := first.LinkHeader(); strings.Contains(h, "next") || strings.Contains(h, "prev") {
+		t.Errorf("LinkHeader() = %s", h)
+	}
+
+	beyond := NewPageResult[int](nil, 45, 7, 20)
+	_ = beyond.BuildLinks("/items")
+	if beyond.Links.Prev != "/items?page=3&per_page=20" {
+		t.Errorf("Prev beyond last page = %s, want last page", beyond.Links.Prev)
+	}
+
+	var noLinks PageResult[int]
+	if noLinks.LinkHeader() != "" {
+		t.Error("LinkHeader() without links should be empty")
+	}
+	if err := noLinks.BuildLinks("http://[::1"); err == nil {
+		t.Error("expected error for invalid base url")
+	}
+}
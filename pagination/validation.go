@@ -0,0 +1,98 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+// 分页参数字段名，与查询参数和 JSON 字段一致
+const (
+	FieldLimit  = "limit"
+	FieldOffset = "offset"
+)
+
+// ParseLimitOffset 解析查询字符串中的 limit 与 offset，并严格校验取值范围。
+// 空字符串使用默认值（limit=DefaultLimit，offset=0）；与 Normalize 不同，越界值不会被钳制而是返回错误。
+// 失败时返回错误码为 INVALID_INPUT 的 *errors.Error，上下文带有 field、rule、value、params 与中英文消息，
+// 可直接交给统一的错误响应处理，也可以用 errors.As 取出后读取字段信息。
+func ParseLimitOffset(limitStr, offsetStr string) (OffsetRequest, error) {
+	req := OffsetRequest{Limit: DefaultLimit}
+
+	if s := strings.TrimSpace(limitStr); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return OffsetRequest{}, integerError(FieldLimit, limitStr).Error
+		}
+		req.Limit = limit
+	}
+	if s := strings.TrimSpace(offsetStr); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil {
+			return OffsetRequest{}, integerError(FieldOffset, offsetStr).Error
+		}
+		req.Offset = offset
+	}
+
+	if err := req.Validate(); err != nil {
+		return OffsetRequest{}, err
+	}
+	return req, nil
+}
+
+// Validate 严格校验 Offset 与 Limit，不修改请求；不合法时返回与 ParseLimitOffset 相同的 *errors.Error，合法时返回 nil。
+// 需要宽松处理时使用 Normalize。
+func (r OffsetRequest) Validate() error {
+	if ve := validateLimit(r.Limit); ve != nil {
+		return ve.Error
+	}
+	if ve := minError(FieldOffset, r.Offset, 0); ve != nil {
+		return ve.Error
+	}
+	return nil
+}
+
+// validateLimit 校验 limit 是否在 [MinLimit, MaxLimit] 范围内
+func validateLimit(limit int) *apperrors.ValidationError {
+	return validateLimitField(FieldLimit, limit)
+}
+
+// validateLimitField 校验每页条数是否在 [MinLimit, MaxLimit] 范围内，field 为报错使用的字段名
+func validateLimitField(field string, limit int) *apperrors.ValidationError {
+	if limit >= MinLimit && limit <= MaxLimit {
+		return nil
+	}
+	ve := apperrors.NewValidationError(field, "range",
+		fmt.Sprintf("Field '%s' must be between %d and %d", field, MinLimit, MaxLimit), limit).
+		WithParams(map[string]interface{}{"min": MinLimit, "max": MaxLimit})
+	ve.WithMessages(
+		fmt.Sprintf("%s 必须在 %d 到 %d 之间", field, MinLimit, MaxLimit),
+		fmt.Sprintf("%s must be between %d and %d", field, MinLimit, MaxLimit),
+	)
+	return ve
+}
+
+// minError 校验 value 不小于 min，不满足时构造校验错误
+func minError(field string, value, min int) *apperrors.ValidationError {
+	if value >= min {
+		return nil
+	}
+	ve := apperrors.NewValidationError(field, "min",
+		fmt.Sprintf("Field '%s' must be at least %d", field, min), value).
+		WithParams(map[string]interface{}{"min": min})
+	ve.WithMessages(
+		fmt.Sprintf("%s 不能小于 %d", field, min),
+		fmt.Sprintf("%s must be at least %d", field, min),
+	)
+	return ve
+}
+
+// integerError 构造“必须为整数”的校验错误
+func integerError(field, value string) *apperrors.ValidationError {
+	ve := apperrors.NewValidationError(field, "integer",
+		fmt.Sprintf("Field '%s' must be an integer", field), value)
+	ve.WithMessages(field+" 必须为整数", field+" must be an integer")
+	return ve
+}
@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+func TestParseLimitOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      string
+		offset     string
+		wantLimit  int
+		wantOffset int
+		wantField  string
+		wantRule   string
+	}{
+		{"defaults", "", "", DefaultLimit, 0, "", ""},
+		{"valid", "50", "100", 50, 100, "", ""},
+		{"whitespace", " 10 ", " 5", 10, 5, "", ""},
+		{"limit not integer", "abc", "0", 0, 0, FieldLimit, "integer"},
+		{"offset not integer", "10", "1.5", 0, 0, FieldOffset, "integer"},
+		{"limit too large", "1000", "0", 0, 0, FieldLimit, "range"},
+		{"limit zero", "0", "0", 0, 0, FieldLimit, "range"},
+		{"negative offset", "10", "-1", 0, 0, FieldOffset, "min"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseLimitOffset(tt.limit, tt.offset)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ParseLimitOffset() error = %v", err)
+				}
+				if req.Limit != tt.wantLimit || req.Offset != tt.wantOffset {
+					t.Errorf("ParseLimitOffset() = %+v, want limit=%d offset=%d", req, tt.wantLimit, tt.wantOffset)
+				}
+				return
+			}
+
+			var ve *apperrors.Error
+			if !errors.As(err, &ve) {
+				t.Fatalf("ParseLimitOffset() error = %v, want *errors.Error", err)
+			}
+			if ve.Context["field"] != tt.wantField || ve.Context["rule"] != tt.wantRule {
+				t.Errorf("field/rule = %v/%v, want %s/%s", ve.Context["field"], ve.Context["rule"], tt.wantField, tt.wantRule)
+			}
+			if ve.Code != apperrors.CodeInvalidInput {
+				t.Errorf("Code = %s, want %s", ve.Code, apperrors.CodeInvalidInput)
+			}
+			if ve.LocalizedMessage(apperrors.LocaleEN) == ve.LocalizedMessage(apperrors.LocaleZH) {
+				t.Error("expected distinct zh/en messages")
+			}
+		})
+	}
+}
+
+func TestOffsetRequest_ValidateParams(t *testing.T) {
+	// 合法请求返回无类型的 nil，赋给 error 变量后仍为 nil
+	var err error = OffsetRequest{Limit: DefaultLimit}.Validate()
+	if err != nil {
+		t.Fatalf("Validate() on valid request = %v", err)
+	}
+
+	err = OffsetRequest{Limit: MaxLimit + 1}.Validate()
+	var ve *apperrors.Error
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate() error = %v, want *errors.Error", err)
+	}
+	if apperrors.GetCode(ve) != apperrors.CodeInvalidInput {
+		t.Errorf("error code = %s", ve.Code)
+	}
+	params, ok := ve.Context["params"].(map[string]interface{})
+	if !ok || params["min"] != MinLimit || params["max"] != MaxLimit {
+		t.Errorf("params = %v", ve.Context["params"])
+	}
+	if got := ve.MessageFor("en-US,en;q=0.9"); got != "limit must be between 1 and 100" {
+		t.Errorf("MessageFor(en) = %q", got)
+	}
+}
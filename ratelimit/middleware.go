@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+
+	apperrors "github.com/iwen-conf/utils-pkg/errors"
+)
+
+// TooManyRequestsError 请求被限流时返回的错误类型，错误码 QUOTA_EXCEEDED 对应 HTTP 429
+var TooManyRequestsError = apperrors.ErrorType{
+	Code:      apperrors.CodeQuotaExceeded,
+	Message:   "请求过于频繁，请稍后重试",
+	MessageEN: "Too many requests, please try again later",
+	Severity:  apperrors.SeverityLow,
+	Category:  apperrors.CategoryBusiness,
+}
+
+// MiddlewareOptions net/http 中间件选项
+type MiddlewareOptions struct {
+	// KeyFunc 返回请求的限流键，默认 KeyByIP；返回空字符串时不限流（如白名单）
+	KeyFunc func(r *http.Request) string
+	// OnLimited 请求被拒绝时调用，默认写入 TooManyRequestsError（HTTP 429），
+	// 上下文中带有 retry_after（秒）。限流响应头已经写入
+	OnLimited func(w http.ResponseWriter, r *http.Request, res Result)
+	// FailClosed 限流器出错（如 Redis 不可用）时拒绝请求并返回 503；默认记录日志后放行
+	FailClosed bool
+}
+
+// Middleware 返回 net/http 限流中间件，每个请求都会写入 X-RateLimit-* 响应头
+//
+//	limiter := ratelimit.NewTokenBucket(ratelimit.TokenBucketOptions{Rate: 10, Burst: 20})
+//	handler := ratelimit.Middleware(limiter, ratelimit.MiddlewareOptions{})(mux)
+func Middleware(limiter Limiter, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.KeyFunc == nil {
+		opts.KeyFunc = KeyByIP
+	}
+	if opts.OnLimited == nil {
+		opts.OnLimited = writeLimited
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := opts.KeyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := limiter.Allow(r.Context(), key)
+			if err != nil {
+				if opts.FailClosed {
+					apperrors.WriteError(w, r, apperrors.Wrap(err, apperrors.CodeUnavailable, "rate limiter unavailable"))
+					return
+				}
+				slog.Default().WarnContext(r.Context(), "rate limiter failed, request allowed",
+					slog.String("key", key), slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			SetHeaders(w.Header(), res)
+			if !res.Allowed {
+				opts.OnLimited(w, r, res)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeLimited 默认的限流响应
+func writeLimited(w http.ResponseWriter, r *http.Request, res Result) {
+	err := apperrors.FromType(TooManyRequestsError).
+		WithContext("retry_after", ceilSeconds(res.RetryAfter))
+	apperrors.WriteError(w, r, err)
+}
+
+// KeyByIP 以 RemoteAddr 中的 IP 作为限流键。
+// 位于反向代理之后时 RemoteAddr 是代理地址，应改用可信代理写入的请求头，如 KeyByHeader("X-Real-IP")
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// KeyByHeader 返回以指定请求头作为限流键的 KeyFunc，请求头为空时退回 KeyByIP，
+// 避免客户端省略请求头绕过限流
+func KeyByHeader(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return v
+		}
+		return KeyByIP(r)
+	}
+}
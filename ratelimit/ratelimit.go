@@ -0,0 +1,72 @@
+// Package ratelimit 提供按任意键（IP、用户 ID 等）限流的令牌桶与滑动窗口限流器，
+// 滑动窗口可通过 Store 接口接入 Redis 等共享存储实现分布式限流。
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrExceedsLimit 单次请求的数量超过了限流器的容量，无论等待多久都不会被允许
+	ErrExceedsLimit = errors.New("ratelimit: n exceeds limiter capacity")
+	// ErrInvalidN 单次请求的数量小于 1
+	ErrInvalidN = errors.New("ratelimit: n must be at least 1")
+)
+
+// Limiter 限流器
+type Limiter interface {
+	// Allow 判断 key 的一次请求是否被允许，等同于 AllowN(ctx, key, 1)
+	Allow(ctx context.Context, key string) (Result, error)
+	// AllowN 判断 key 的 n 次请求是否被允许，被允许时计入用量，被拒绝时不计入；n 小于 1 时返回 ErrInvalidN
+	AllowN(ctx context.Context, key string, n int) (Result, error)
+}
+
+// Result 限流判断结果
+type Result struct {
+	// Allowed 是否允许本次请求
+	Allowed bool
+	// Limit 限流器容量：令牌桶为桶大小，滑动窗口为窗口内的请求上限
+	Limit int
+	// Remaining 本次请求之后剩余的可用次数
+	Remaining int
+	// RetryAfter 被拒绝时需要等待多久才可能被允许，允许时为 0
+	RetryAfter time.Duration
+	// ResetAfter 多久之后用量完全恢复
+	ResetAfter time.Duration
+}
+
+// HeaderSetter 响应头设置接口，http.Header 与 Hertz 的 *protocol.ResponseHeader 都满足该接口
+type HeaderSetter interface {
+	Set(key, value string)
+}
+
+// 限流相关的响应头
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// SetHeaders 写入 X-RateLimit-Limit、X-RateLimit-Remaining、X-RateLimit-Reset（秒），
+// 请求被拒绝时还会写入 Retry-After（秒，向上取整）
+func SetHeaders(h HeaderSetter, res Result) {
+	h.Set(HeaderLimit, strconv.Itoa(res.Limit))
+	h.Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+	h.Set(HeaderReset, strconv.FormatInt(ceilSeconds(res.ResetAfter), 10))
+	if !res.Allowed {
+		h.Set(HeaderRetryAfter, strconv.FormatInt(ceilSeconds(res.RetryAfter), 10))
+	}
+}
+
+// ceilSeconds 将时长向上取整为秒
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
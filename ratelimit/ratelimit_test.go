@@ -0,0 +1,234 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Unix(1_700_000_000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func TestTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	tb := NewTokenBucket(TokenBucketOptions{Rate: 2, Burst: 3})
+	tb.now = clock.Now
+
+	for i := 0; i < 3; i++ {
+		res, _ := tb.Allow(ctx, "ip-1")
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("request %d: %+v", i, res)
+		}
+	}
+	res, _ := tb.Allow(ctx, "ip-1")
+	if res.Allowed || res.RetryAfter != 500*time.Millisecond || res.ResetAfter != 1500*time.Millisecond {
+		t.Fatalf("over burst: %+v", res)
+	}
+
+	// 其他键不受影响
+	if res, _ := tb.Allow(ctx, "ip-2"); !res.Allowed {
+		t.Fatal("other keys should have their own bucket")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if res, _ := tb.Allow(ctx, "ip-1"); !res.Allowed {
+		t.Fatalf("token should be refilled: %+v", res)
+	}
+
+	if _, err := tb.AllowN(ctx, "ip-1", 4); !errors.Is(err, ErrExceedsLimit) {
+		t.Errorf("AllowN over burst error = %v", err)
+	}
+
+	// 补满的桶会被清理
+	clock.Advance(time.Hour)
+	tb.Allow(ctx, "ip-3")
+	if tb.Len() > bucketShardCount {
+		t.Errorf("Len() = %d, idle buckets should be swept", tb.Len())
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	store := NewMemoryStore()
+	store.now = clock.Now
+	sw := NewSlidingWindow(SlidingWindowOptions{Limit: 10, Window: time.Minute, Store: store})
+	sw.now = clock.Now
+
+	for i := 0; i < 10; i++ {
+		if res, _ := sw.Allow(ctx, "user-1"); !res.Allowed {
+			t.Fatalf("request %d denied: %+v", i, res)
+		}
+	}
+	res, _ := sw.Allow(ctx, "user-1")
+	if res.Allowed || res.Remaining != 0 || res.RetryAfter <= 0 {
+		t.Fatalf("over limit: %+v", res)
+	}
+	if res, _ := sw.Allow(ctx, "user-2"); !res.Allowed {
+		t.Fatal("other keys should have their own window")
+	}
+
+	// 被拒绝的请求不计数：等待 RetryAfter 后应当被允许
+	clock.Advance(res.RetryAfter + time.Millisecond)
+	if res, _ := sw.Allow(ctx, "user-1"); !res.Allowed {
+		t.Fatalf("request after RetryAfter denied: %+v", res)
+	}
+
+	// 上一个窗口的计数按剩余比例计入，边界处不会出现突发翻倍
+	clock = newFakeClock()
+	store.now = clock.Now
+	sw.now = clock.Now
+	index := clock.Now().UnixNano() / int64(time.Minute)
+	clock.Advance(time.Duration((index+1)*int64(time.Minute)-clock.Now().UnixNano()) - time.Second)
+	for i := 0; i < 10; i++ {
+		sw.Allow(ctx, "user-3")
+	}
+	clock.Advance(15 * time.Second) // 进入下一个窗口 14 秒，上一个窗口权重约 0.77
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if res, _ := sw.Allow(ctx, "user-3"); res.Allowed {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d requests after window boundary, want 2", allowed)
+	}
+
+	if _, err := sw.AllowN(ctx, "user-1", 11); !errors.Is(err, ErrExceedsLimit) {
+		t.Errorf("AllowN over limit error = %v", err)
+	}
+}
+
+func TestSlidingWindow_Concurrent(t *testing.T) {
+	sw := NewSlidingWindow(SlidingWindowOptions{Limit: 50, Window: time.Hour})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, _ := sw.Allow(context.Background(), "shared"); res.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed > 50 {
+		t.Errorf("allowed %d concurrent requests, limit is 50", allowed)
+	}
+}
+
+// failingStore 总是返回错误的存储
+type failingStore struct{}
+
+func (failingStore) Incr(context.Context, string, int64, time.Duration) (int64, error) {
+	return 0, errors.New("redis down")
+}
+
+func (failingStore) Get(context.Context, string) (int64, error) {
+	return 0, errors.New("redis down")
+}
+
+func TestAllowN_InvalidN(t *testing.T) {
+	ctx := context.Background()
+	limiters := map[string]Limiter{
+		"token bucket":   NewTokenBucket(TokenBucketOptions{Rate: 1, Burst: 2}),
+		"sliding window": NewSlidingWindow(SlidingWindowOptions{Limit: 2, Window: time.Hour}),
+	}
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			for _, n := range []int{0, -5} {
+				res, err := l.AllowN(ctx, "k", n)
+				if !errors.Is(err, ErrInvalidN) || res.Allowed {
+					t.Errorf("AllowN(%d) = %+v, %v, want ErrInvalidN", n, res, err)
+				}
+			}
+			// 非法请求不改动用量，限额仍为 2
+			allowed := 0
+			for i := 0; i < 4; i++ {
+				if res, _ := l.Allow(ctx, "k"); res.Allowed {
+					allowed++
+				}
+			}
+			if allowed != 2 {
+				t.Errorf("allowed %d requests after invalid n, want 2", allowed)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	limiter := NewTokenBucket(TokenBucketOptions{Rate: 1, Burst: 1})
+	handler := Middleware(limiter, MiddlewareOptions{KeyFunc: KeyByHeader("X-User-ID")})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+	do := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-ID", user)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("alice"); rec.Code != http.StatusNoContent || rec.Header().Get(HeaderRemaining) != "0" {
+		t.Fatalf("first request: %d %v", rec.Code, rec.Header())
+	}
+	rec := do("alice")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get(HeaderRetryAfter) != "1" {
+		t.Fatalf("limited request: %d %v", rec.Code, rec.Header())
+	}
+	if rec := do("bob"); rec.Code != http.StatusNoContent {
+		t.Errorf("other user: %d", rec.Code)
+	}
+}
+
+func TestMiddleware_StoreError(t *testing.T) {
+	limiter := NewSlidingWindow(SlidingWindowOptions{Limit: 1, Window: time.Second, Store: failingStore{}})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, tt := range []struct {
+		failClosed bool
+		want       int
+	}{
+		{false, http.StatusNoContent},
+		{true, http.StatusServiceUnavailable},
+	} {
+		rec := httptest.NewRecorder()
+		Middleware(limiter, MiddlewareOptions{FailClosed: tt.failClosed})(next).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != tt.want {
+			t.Errorf("FailClosed=%v: status = %d, want %d", tt.failClosed, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultKeyPrefix 滑动窗口在 Store 中使用的默认键前缀
+const DefaultKeyPrefix = "ratelimit:"
+
+// Store 滑动窗口的计数存储，实现需保证同一个键上 Incr 的原子性。
+// Redis 实现可以用 INCRBY 加 EXPIRE ... NX（或一段 Lua 脚本）完成 Incr，用 GET 完成 Get，
+// 多个进程共享同一个 Store 即可实现分布式限流。
+type Store interface {
+	// Incr 将 key 的计数增加 n（n 可以为负数）并返回增加后的值；
+	// key 不存在时创建，并在 ttl 后过期，已存在的 key 不刷新过期时间
+	Incr(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+	// Get 返回 key 的当前计数，不存在或已过期时返回 0
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// SlidingWindowOptions 滑动窗口选项
+type SlidingWindowOptions struct {
+	// Limit 每个窗口内允许的请求数，必须大于 0
+	Limit int
+	// Window 窗口长度，必须大于 0
+	Window time.Duration
+	// Store 计数存储，为 nil 时使用进程内的 MemoryStore
+	Store Store
+	// KeyPrefix Store 中的键前缀，默认 DefaultKeyPrefix；多个限流器共享 Store 时用于区分
+	KeyPrefix string
+}
+
+// SlidingWindow 滑动窗口计数器限流器：按上一个固定窗口的计数加权估算最近一个窗口内的请求数，
+// 每个键只需两个计数器，避免了固定窗口在边界处的突发翻倍。可并发使用。
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	store  Store
+	prefix string
+	now    func() time.Time
+}
+
+// NewSlidingWindow 创建滑动窗口限流器，Limit 或 Window 不大于 0 属于编程错误，会直接 panic
+func NewSlidingWindow(opts SlidingWindowOptions) *SlidingWindow {
+	if opts.Limit <= 0 || opts.Window <= 0 {
+		panic(fmt.Sprintf("ratelimit: invalid sliding window options: limit=%d, window=%s", opts.Limit, opts.Window))
+	}
+	sw := &SlidingWindow{
+		limit:  opts.Limit,
+		window: opts.Window,
+		store:  opts.Store,
+		prefix: opts.KeyPrefix,
+		now:    time.Now,
+	}
+	if sw.store == nil {
+		sw.store = NewMemoryStore()
+	}
+	if sw.prefix == "" {
+		sw.prefix = DefaultKeyPrefix
+	}
+	return sw
+}
+
+// Allow 实现 Limiter 接口
+func (sw *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	return sw.AllowN(ctx, key, 1)
+}
+
+// AllowN 实现 Limiter 接口，n 小于 1 时返回 ErrInvalidN，大于 Limit 时返回 ErrExceedsLimit，Store 出错时原样返回其错误。
+// 先计数再判断，超限时回退本次计数，并发请求不会突破限额。
+func (sw *SlidingWindow) AllowN(ctx context.Context, key string, n int) (Result, error) {
+	if n < 1 {
+		return Result{Limit: sw.limit}, fmt.Errorf("%w: n=%d", ErrInvalidN, n)
+	}
+	if n > sw.limit {
+		return Result{Limit: sw.limit}, fmt.Errorf("%w: n=%d, limit=%d", ErrExceedsLimit, n, sw.limit)
+	}
+
+	now := sw.now()
+	index := now.UnixNano() / int64(sw.window)
+	elapsed := time.Duration(now.UnixNano() - index*int64(sw.window))
+	curKey := sw.windowKey(key, index)
+	ttl := 2 * sw.window
+
+	cur, err := sw.store.Incr(ctx, curKey, int64(n), ttl)
+	if err != nil {
+		return Result{}, err
+	}
+	prev, err := sw.store.Get(ctx, sw.windowKey(key, index-1))
+	if err != nil {
+		return Result{}, err
+	}
+
+	weight := 1 - float64(elapsed)/float64(sw.window)
+	estimated := float64(prev)*weight + float64(cur)
+	res := Result{Limit: sw.limit, ResetAfter: sw.window - elapsed}
+	if estimated <= float64(sw.limit) {
+		res.Allowed = true
+		res.Remaining = sw.remaining(estimated)
+		return res, nil
+	}
+
+	if _, err := sw.store.Incr(ctx, curKey, -int64(n), ttl); err != nil {
+		return Result{}, err
+	}
+	base := float64(cur - int64(n))
+	res.Remaining = sw.remaining(estimated - float64(n))
+	res.RetryAfter = sw.retryAfter(float64(prev), base, float64(n), elapsed)
+	return res, nil
+}
+
+// retryAfter 估算被拒绝的请求需要等待的时间：
+// 当前窗口的计数不变时，上一个窗口的权重随时间下降；当前窗口已经超限时需等到下一个窗口，
+// 由当前窗口的计数作为新的上一个窗口继续衰减
+func (sw *SlidingWindow) retryAfter(prev, base, n float64, elapsed time.Duration) time.Duration {
+	window := float64(sw.window)
+	need := float64(sw.limit) - n
+	if base <= need && prev > 0 {
+		wait := window*(1-(need-base)/prev) - float64(elapsed)
+		return time.Duration(math.Max(wait, 0))
+	}
+	wait := window - float64(elapsed)
+	if base > 0 {
+		wait += window * math.Max(0, 1-need/base)
+	}
+	return time.Duration(wait)
+}
+
+// remaining 返回剩余可用次数
+func (sw *SlidingWindow) remaining(estimated float64) int {
+	return max(0, sw.limit-int(math.Ceil(estimated)))
+}
+
+// windowKey 返回键在指定窗口的计数器键
+func (sw *SlidingWindow) windowKey(key string, index int64) string {
+	return sw.prefix + key + ":" + strconv.FormatInt(index, 10)
+}
+
+// MemoryStore 进程内的计数存储，过期的计数会被定期清理
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// memoryEntry 计数与过期时间
+type memoryEntry struct {
+	value    int64
+	expireAt time.Time
+}
+
+// NewMemoryStore 创建进程内计数存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Incr 实现 Store 接口
+func (s *MemoryStore) Incr(_ context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, e := range s.entries {
+			if !now.Before(e.expireAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expireAt) {
+		e = memoryEntry{expireAt: now.Add(ttl)}
+	}
+	e.value += n
+	s.entries[key] = e
+	return e.value, nil
+}
+
+// Get 实现 Store 接口
+func (s *MemoryStore) Get(_ context.Context, key string) (int64, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expireAt) {
+		return 0, nil
+	}
+	return e.value, nil
+}
+
+// Len 返回当前保存的计数器数量（含尚未清理的过期计数器）
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
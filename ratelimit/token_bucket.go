@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// bucketShardCount 令牌桶的分片数，降低大量键并发访问时的锁竞争
+	bucketShardCount = 32
+	// sweepInterval 清理空闲键的最小间隔
+	sweepInterval = time.Minute
+)
+
+// TokenBucketOptions 令牌桶选项
+type TokenBucketOptions struct {
+	// Rate 每秒补充的令牌数，必须大于 0
+	Rate float64
+	// Burst 桶容量，即允许的最大突发请求数，必须大于 0
+	Burst int
+}
+
+// TokenBucket 进程内的令牌桶限流器，每个键一个桶，初始为满。
+// 允许短时间内突发 Burst 次请求，长期平均速率不超过 Rate。
+// 桶补满后与不存在等价，会被定期清理，键的数量不会无限增长。可并发使用。
+type TokenBucket struct {
+	rate   float64
+	burst  int
+	shards [bucketShardCount]bucketShard
+	now    func() time.Time
+}
+
+// bucketShard 令牌桶分片
+type bucketShard struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket 单个键的令牌桶状态
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket 创建令牌桶限流器，Rate 或 Burst 不大于 0 属于编程错误，会直接 panic
+func NewTokenBucket(opts TokenBucketOptions) *TokenBucket {
+	if opts.Rate <= 0 || opts.Burst <= 0 {
+		panic(fmt.Sprintf("ratelimit: invalid token bucket options: rate=%g, burst=%d", opts.Rate, opts.Burst))
+	}
+	tb := &TokenBucket{rate: opts.Rate, burst: opts.Burst, now: time.Now}
+	for i := range tb.shards {
+		tb.shards[i].buckets = make(map[string]*bucket)
+	}
+	return tb
+}
+
+// Allow 实现 Limiter 接口
+func (tb *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	return tb.AllowN(ctx, key, 1)
+}
+
+// AllowN 实现 Limiter 接口，n 小于 1 时返回 ErrInvalidN，大于 Burst 时返回 ErrExceedsLimit
+func (tb *TokenBucket) AllowN(_ context.Context, key string, n int) (Result, error) {
+	if n < 1 {
+		return Result{Limit: tb.burst}, fmt.Errorf("%w: n=%d", ErrInvalidN, n)
+	}
+	if n > tb.burst {
+		return Result{Limit: tb.burst}, fmt.Errorf("%w: n=%d, burst=%d", ErrExceedsLimit, n, tb.burst)
+	}
+
+	now := tb.now()
+	shard := &tb.shards[shardIndex(key)]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if now.Sub(shard.lastSweep) >= sweepInterval {
+		tb.sweep(shard, now)
+	}
+
+	b, ok := shard.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(tb.burst), last: now}
+		shard.buckets[key] = b
+	}
+	b.tokens = tb.refill(b, now)
+	b.last = now
+
+	res := Result{Limit: tb.burst}
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = tb.durationFor(float64(n) - b.tokens)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = tb.durationFor(float64(tb.burst) - b.tokens)
+	return res, nil
+}
+
+// refill 返回按经过时间补充后的令牌数
+func (tb *TokenBucket) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(float64(tb.burst), b.tokens+elapsed*tb.rate)
+}
+
+// durationFor 返回补充指定数量令牌所需的时间
+func (tb *TokenBucket) durationFor(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / tb.rate * float64(time.Second))
+}
+
+// sweep 清理已补满的桶，调用方需持有分片锁
+func (tb *TokenBucket) sweep(shard *bucketShard, now time.Time) {
+	for key, b := range shard.buckets {
+		if tb.refill(b, now) >= float64(tb.burst) {
+			delete(shard.buckets, key)
+		}
+	}
+	shard.lastSweep = now
+}
+
+// Len 返回当前跟踪的键数量
+func (tb *TokenBucket) Len() int {
+	total := 0
+	for i := range tb.shards {
+		shard := &tb.shards[i]
+		shard.mu.Lock()
+		total += len(shard.buckets)
+		shard.mu.Unlock()
+	}
+	return total
+}
+
+// shardIndex 返回键所在的分片
+func shardIndex(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32() % bucketShardCount
+}
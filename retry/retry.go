@@ -0,0 +1,170 @@
+// Package retry 提供通用的重试与指数退避工具，适用于 HTTP 调用、数据库操作等可重试的场景。
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// 默认重试参数
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+	DefaultMultiplier   = 2.0
+	DefaultJitter       = 0.2
+)
+
+// ErrMaxAttempts 达到最大尝试次数仍然失败，返回的错误同时包装最后一次的错误
+var ErrMaxAttempts = errors.New("retry: max attempts reached")
+
+// Options 重试选项，零值字段使用默认值
+type Options struct {
+	// 总尝试次数（含首次），默认 3；小于 0 表示不限次数，直到成功或 ctx 结束
+	MaxAttempts int
+	// 首次重试前的等待时间，默认 100ms
+	InitialDelay time.Duration
+	// 等待时间上限，默认 10s
+	MaxDelay time.Duration
+	// 每次重试等待时间的增长倍数，默认 2
+	Multiplier float64
+	// 抖动比例，取值 [0, 1]，实际等待时间在 delay*(1±Jitter) 内随机，默认 0.2；
+	// 避免大量客户端同时重试。设为负数关闭抖动
+	Jitter float64
+	// 判断错误是否可重试，为 nil 时除 Permanent 标记的错误外都重试
+	RetryIf func(err error) bool
+	// 每次重试前调用，attempt 为刚失败的尝试序号（从 1 开始），delay 为即将等待的时间
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultOptions 返回默认重试选项
+func DefaultOptions() Options {
+	return Options{
+		MaxAttempts:  DefaultMaxAttempts,
+		InitialDelay: DefaultInitialDelay,
+		MaxDelay:     DefaultMaxDelay,
+		Multiplier:   DefaultMultiplier,
+		Jitter:       DefaultJitter,
+	}
+}
+
+// withDefaults 填充零值字段
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts == 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.InitialDelay <= 0 {
+		o.InitialDelay = DefaultInitialDelay
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = DefaultMaxDelay
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = DefaultMultiplier
+	}
+	if o.Jitter == 0 {
+		o.Jitter = DefaultJitter
+	}
+	if o.Jitter > 1 {
+		o.Jitter = 1
+	}
+	return o
+}
+
+// Delay 返回第 attempt 次失败（从 1 开始）后的等待时间，包含抖动
+// 可单独用于自行实现重试循环的场景
+func (o Options) Delay(attempt int) time.Duration {
+	o = o.withDefaults()
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(o.InitialDelay) * math.Pow(o.Multiplier, float64(attempt-1))
+	if delay > float64(o.MaxDelay) {
+		delay = float64(o.MaxDelay)
+	}
+	if o.Jitter > 0 {
+		delay *= 1 + o.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+// permanentError 标记为不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将错误标记为不可重试，Do 遇到后立即返回原始错误
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 判断错误是否被标记为不可重试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Do 执行 fn，失败时按指数退避重试
+//
+// 返回值：
+//   - 成功时返回 nil
+//   - Permanent 标记或 RetryIf 判定不可重试的错误原样返回（去掉 Permanent 标记）
+//   - 达到最大尝试次数时返回同时包装 ErrMaxAttempts 与最后一次错误的错误
+//   - ctx 在等待期间结束时返回同时包装 ctx.Err() 与最后一次错误的错误
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts Options) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts)
+	return err
+}
+
+// DoValue 同 Do，返回 fn 成功时的结果；失败时返回零值
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts Options) (T, error) {
+	var zero T
+	opts = opts.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		result, err := fn(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		if p, ok := err.(*permanentError); ok {
+			return zero, p.err
+		}
+		if IsPermanent(err) {
+			return zero, err
+		}
+		if opts.RetryIf != nil && !opts.RetryIf(err) {
+			return zero, err
+		}
+		if opts.MaxAttempts > 0 && attempt >= opts.MaxAttempts {
+			return zero, fmt.Errorf("%w (%d attempts): %w", ErrMaxAttempts, attempt, err)
+		}
+
+		delay := opts.Delay(attempt)
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
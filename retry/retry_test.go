@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fastOptions 测试用的短等待选项
+func fastOptions(maxAttempts int) Options {
+	return Options{MaxAttempts: maxAttempts, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestDo_SucceedsAfterRetries(t *testing.T) {
+	calls := 0
+	var retried []int
+	opts := fastOptions(5)
+	opts.OnRetry = func(attempt int, err error, delay time.Duration) {
+		retried = append(retried, attempt)
+	}
+
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, opts)
+	if err != nil {
+		t.Fatalf("Do() = %v", err)
+	}
+	if calls != 3 || len(retried) != 2 || retried[1] != 2 {
+		t.Errorf("calls = %d, retried = %v", calls, retried)
+	}
+}
+
+func TestDo_MaxAttempts(t *testing.T) {
+	cause := errors.New("unavailable")
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return cause
+	}, fastOptions(3))
+
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if !errors.Is(err, ErrMaxAttempts) || !errors.Is(err, cause) {
+		t.Errorf("err = %v, want ErrMaxAttempts wrapping the cause", err)
+	}
+}
+
+func TestDo_StopConditions(t *testing.T) {
+	cause := errors.New("bad request")
+
+	calls := 0
+	err := Do(context.Background(), func(context.Context) error {
+		calls++
+		return Permanent(cause)
+	}, fastOptions(5))
+	if err != cause || calls != 1 {
+		t.Errorf("permanent: err = %v, calls = %d", err, calls)
+	}
+
+	calls = 0
+	opts := fastOptions(5)
+	opts.RetryIf = func(err error) bool { return !errors.Is(err, cause) }
+	err = Do(context.Background(), func(context.Context) error {
+		calls++
+		return cause
+	}, opts)
+	if err != cause || calls != 1 {
+		t.Errorf("RetryIf: err = %v, calls = %d", err, calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	slow := Options{MaxAttempts: -1, InitialDelay: 5 * time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	err = Do(ctx, func(context.Context) error { return cause }, slow)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, cause) {
+		t.Errorf("context: err = %v", err)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	got, err := DoValue(context.Background(), func(context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "partial", errors.New("retry me")
+		}
+		return "ok", nil
+	}, fastOptions(3))
+	if got != "ok" || err != nil {
+		t.Errorf("DoValue() = %q, %v", got, err)
+	}
+}
+
+func TestOptions_Delay(t *testing.T) {
+	opts := Options{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: -1}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, w := range want {
+		if got := opts.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	jittered := Options{InitialDelay: 100 * time.Millisecond, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if d := jittered.Delay(1); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jittered delay %v out of range", d)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iwen-conf/utils-pkg/crypto/macsign"
+)
+
+// 密钥相关错误
+var (
+	ErrUnknownKeyVersion = errors.New("unknown signing key version")
+	ErrInvalidAlgorithm  = errors.New("unsupported signature algorithm")
+)
+
+// SignatureAlgorithm URL 签名算法
+type SignatureAlgorithm int
+
+const (
+	// AlgorithmHMACSHA256 HMAC-SHA256，与未指定密钥版本时的签名一致
+	AlgorithmHMACSHA256 SignatureAlgorithm = iota
+	// AlgorithmHMACSHA512 HMAC-SHA512
+	AlgorithmHMACSHA512
+)
+
+// SigningKey 带版本的签名密钥
+type SigningKey struct {
+	// 版本号，签名时写入 _v 参数并参与签名；
+	// 为空表示不带 _v 的 URL 使用的密钥，用于继续验证引入版本之前签发的 URL
+	Version   string
+	Secret    []byte
+	Algorithm SignatureAlgorithm
+}
+
+// signer 创建密钥对应的签名器
+func (k SigningKey) signer() (*macsign.Signer, error) {
+	if len(k.Secret) == 0 {
+		return nil, ErrEmptySecretKey
+	}
+	var hash macsign.Hash
+	switch k.Algorithm {
+	case AlgorithmHMACSHA256:
+		hash = macsign.HashSHA256
+	case AlgorithmHMACSHA512:
+		hash = macsign.HashSHA512
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrInvalidAlgorithm, k.Algorithm)
+	}
+	return macsign.NewWithHash(k.Secret, macsign.EncodingBase64, hash)
+}
+
+// KeyProvider 签名密钥提供者，用于轮换密钥而不使已签发的 URL 全部失效
+type KeyProvider interface {
+	// CurrentKey 返回签发新 URL 使用的密钥
+	CurrentKey() (SigningKey, error)
+	// Key 返回指定版本的验证密钥，不存在时返回 ErrUnknownKeyVersion
+	Key(version string) (SigningKey, error)
+}
+
+// KeyRing 进程内的 KeyProvider 实现，可并发使用
+// 轮换时先 Rotate 到新密钥，旧密钥保留到其签发的 URL 全部过期后再 Remove
+type KeyRing struct {
+	mu      sync.RWMutex
+	keys    map[string]SigningKey
+	current string
+}
+
+// NewKeyRing 创建密钥环，current 用于签发，previous 只用于验证
+func NewKeyRing(current SigningKey, previous ...SigningKey) (*KeyRing, error) {
+	r := &KeyRing{keys: make(map[string]SigningKey, len(previous)+1)}
+	for _, key := range previous {
+		if err := r.add(key); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.add(current); err != nil {
+		return nil, err
+	}
+	r.current = current.Version
+	return r, nil
+}
+
+// add 校验并保存密钥，同版本的密钥会被替换
+func (r *KeyRing) add(key SigningKey) error {
+	if _, err := key.signer(); err != nil {
+		return fmt.Errorf("key version %q: %w", key.Version, err)
+	}
+	key.Secret = append([]byte(nil), key.Secret...)
+	r.keys[key.Version] = key
+	return nil
+}
+
+// CurrentKey 实现 KeyProvider 接口
+func (r *KeyRing) CurrentKey() (SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[r.current], nil
+}
+
+// Key 实现 KeyProvider 接口
+func (r *KeyRing) Key(version string) (SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[version]
+	if !ok {
+		return SigningKey{}, fmt.Errorf("%w: %q", ErrUnknownKeyVersion, version)
+	}
+	return key, nil
+}
+
+// Rotate 添加新密钥并用于之后的签发，原密钥继续用于验证
+func (r *KeyRing) Rotate(key SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.add(key); err != nil {
+		return err
+	}
+	r.current = key.Version
+	return nil
+}
+
+// Remove 移除不再需要验证的旧密钥，不能移除当前密钥
+func (r *KeyRing) Remove(version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if version == r.current {
+		return fmt.Errorf("cannot remove current key version %q", version)
+	}
+	delete(r.keys, version)
+	return nil
+}
+
+// ValidateSignatureWithKeys 按 URL 中的 _v 参数选择密钥与算法验证签名
+// 不带 _v 的 URL 使用版本为空的密钥验证；版本不存在时返回 ErrUnknownKeyVersion
+func ValidateSignatureWithKeys(rawURL string, keys KeyProvider, maxAgeSeconds int64) (bool, error) {
+	if keys == nil {
+		return false, ErrEmptySecretKey
+	}
+	return validateSignature(rawURL, maxAgeSeconds, func(version string) (*macsign.Signer, error) {
+		key, err := keys.Key(version)
+		if err != nil {
+			return nil, err
+		}
+		return key.signer()
+	})
+}
@@ -0,0 +1,104 @@
+package url
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyRing_Rotation(t *testing.T) {
+	ring, err := NewKeyRing(SigningKey{Version: "k1", Secret: []byte("first-secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldURL, err := NewURLBuilderWithKeys("https://example.com/file", ring).AddParam("id", "1").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(oldURL, "_v=k1") {
+		t.Errorf("signed URL should carry the key version: %s", oldURL)
+	}
+
+	// 轮换到 SHA-512 新密钥，旧 URL 仍然有效
+	if err := ring.Rotate(SigningKey{Version: "k2", Secret: []byte("second-secret"), Algorithm: AlgorithmHMACSHA512}); err != nil {
+		t.Fatal(err)
+	}
+	newURL, _ := NewURLBuilderWithKeys("https://example.com/file", ring).AddParam("id", "1").Build()
+	if !strings.Contains(newURL, "_v=k2") {
+		t.Errorf("new URL should use k2: %s", newURL)
+	}
+	for _, u := range []string{oldURL, newURL} {
+		if ok, err := ValidateSignatureWithKeys(u, ring, 0); !ok {
+			t.Errorf("%s: %v", u, err)
+		}
+	}
+
+	// 移除旧密钥后旧 URL 失效
+	if err := ring.Remove("k2"); err == nil {
+		t.Error("removing the current key should fail")
+	}
+	if err := ring.Remove("k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateSignatureWithKeys(oldURL, ring, 0); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Errorf("error = %v, want ErrUnknownKeyVersion", err)
+	}
+}
+
+func TestValidateSignatureWithKeys_Tampering(t *testing.T) {
+	ring, _ := NewKeyRing(
+		SigningKey{Version: "k2", Secret: []byte("second-secret"), Algorithm: AlgorithmHMACSHA512},
+		SigningKey{Version: "k1", Secret: []byte("first-secret")},
+	)
+	signed, _ := NewURLBuilderWithKeys("https://example.com/file", ring).AddParam("id", "1").Build()
+
+	// 改写版本号会改变签名串，使用另一把密钥也无法通过
+	if _, err := ValidateSignatureWithKeys(strings.Replace(signed, "_v=k2", "_v=k1", 1), ring, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("version swap error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := ValidateSignatureWithKeys(strings.Replace(signed, "id=1", "id=2", 1), ring, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered param error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := ValidateSignatureWithKeys(signed, nil, 0); !errors.Is(err, ErrEmptySecretKey) {
+		t.Errorf("nil provider error = %v", err)
+	}
+}
+
+func TestValidateSignatureWithKeys_Legacy(t *testing.T) {
+	const secret = "legacy-secret"
+	legacyURL, _ := CreateSignedURL("https://example.com/file", secret, map[string]string{"id": "1"}, 600)
+
+	// 版本为空的密钥用于验证引入版本之前签发的 URL
+	ring, _ := NewKeyRing(
+		SigningKey{Version: "k1", Secret: []byte("new-secret")},
+		SigningKey{Secret: []byte(secret)},
+	)
+	if ok, err := ValidateSignatureWithKeys(legacyURL, ring, 0); !ok {
+		t.Errorf("legacy URL rejected: %v", err)
+	}
+
+	// 当前密钥版本为空时输出与 NewURLBuilder 相同的 URL，旧的验证方式仍可使用
+	legacyRing, _ := NewKeyRing(SigningKey{Secret: []byte(secret)})
+	unversioned, _ := NewURLBuilderWithKeys("https://example.com/file", legacyRing).Build()
+	if strings.Contains(unversioned, "_v=") {
+		t.Errorf("unversioned key should not add _v: %s", unversioned)
+	}
+	if ok, err := ValidateSignature(unversioned, secret, 0); !ok {
+		t.Errorf("ValidateSignature() = %v", err)
+	}
+
+	ringWithoutLegacy, _ := NewKeyRing(SigningKey{Version: "k1", Secret: []byte("new-secret")})
+	if _, err := ValidateSignatureWithKeys(legacyURL, ringWithoutLegacy, 0); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Errorf("error = %v, want ErrUnknownKeyVersion", err)
+	}
+}
+
+func TestNewKeyRing_InvalidKeys(t *testing.T) {
+	if _, err := NewKeyRing(SigningKey{Version: "k1"}); !errors.Is(err, ErrEmptySecretKey) {
+		t.Errorf("empty secret error = %v", err)
+	}
+	if _, err := NewKeyRing(SigningKey{Version: "k1", Secret: []byte("s"), Algorithm: 9}); !errors.Is(err, ErrInvalidAlgorithm) {
+		t.Errorf("bad algorithm error = %v", err)
+	}
+}
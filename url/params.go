@@ -0,0 +1,265 @@
+package url
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidParamKey 层级查询参数的键格式错误，或同一路径同时出现标量与对象
+var ErrInvalidParamKey = errors.New("invalid hierarchical query parameter")
+
+// maxParamDepth 解析层级参数时允许的最大嵌套层数
+const maxParamDepth = 10
+
+// ParamStyle 层级查询参数的编码风格
+// 对象总是以方括号展开（filter[status]=active），各风格只在标量数组的写法上不同；
+// 元素为对象或数组的数组总是使用下标（items[0][sku]=x）
+type ParamStyle int
+
+const (
+	// StyleDeepObject 数组重复同一个键：filter[tags]=a&filter[tags]=b
+	// 只有一个元素的数组解析后为标量
+	StyleDeepObject ParamStyle = iota
+	// StyleBracket 数组以 [] 结尾：filter[tags][]=a&filter[tags][]=b
+	StyleBracket
+	// StyleComma 数组以逗号连接：filter[tags]=a,b
+	// 解析时所有含逗号的值都会被拆分为数组，元素本身不能包含逗号
+	StyleComma
+)
+
+// serializeHierarchical 按指定风格把参数编码为层级查询字符串，键按字典序输出
+func serializeHierarchical(params map[string]interface{}, style ParamStyle) string {
+	var pairs []string
+	for _, k := range sortedKeys(params) {
+		encodeParam(&pairs, url.QueryEscape(k), normalizeParam(params[k]), style)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// encodeParam 递归输出一个已归一化的值
+func encodeParam(pairs *[]string, key string, v interface{}, style ParamStyle) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			encodeParam(pairs, key+"["+url.QueryEscape(k)+"]", val[k], style)
+		}
+	case []interface{}:
+		if !isScalarList(val) {
+			for i, item := range val {
+				encodeParam(pairs, key+"["+strconv.Itoa(i)+"]", item, style)
+			}
+			return
+		}
+		switch style {
+		case StyleBracket:
+			for _, item := range val {
+				*pairs = append(*pairs, key+"[]="+url.QueryEscape(item.(string)))
+			}
+		case StyleComma:
+			if len(val) > 0 {
+				items := make([]string, len(val))
+				for i, item := range val {
+					items[i] = url.QueryEscape(item.(string))
+				}
+				*pairs = append(*pairs, key+"="+strings.Join(items, ","))
+			}
+		default:
+			for _, item := range val {
+				*pairs = append(*pairs, key+"="+url.QueryEscape(item.(string)))
+			}
+		}
+	case string:
+		*pairs = append(*pairs, key+"="+url.QueryEscape(val))
+	}
+}
+
+// normalizeParam 把参数值转换为 string、map[string]interface{} 或 []interface{}
+// 常见类型直接转换，其他类型（结构体、其他 map 与切片）经 JSON 编解码后再转换，无法编码的值被忽略
+func normalizeParam(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return fmt.Sprint(val)
+	case []string:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = item
+		}
+		return items
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = normalizeParam(item)
+		}
+		return items
+	case map[string]string:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = item
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = normalizeParam(item)
+		}
+		return m
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return nil
+	}
+	return normalizeParam(decoded)
+}
+
+// isScalarList 判断数组元素是否都是标量
+func isScalarList(items []interface{}) bool {
+	for _, item := range items {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// deserializeHierarchical 按指定风格解析层级查询字符串
+// 叶子值均为字符串，数组为 []interface{}，下标连续（从 0 开始）的对象会被还原为数组
+func deserializeHierarchical(values url.Values, style ParamStyle) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	for _, key := range sortedKeys(values) {
+		segments, err := splitParamKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := insertParam(root, segments, values[key], style, key); err != nil {
+			return nil, err
+		}
+	}
+	// 顶层始终保持为对象，即使参数名恰好是 0..n-1
+	for k, v := range root {
+		root[k] = restoreLists(v)
+	}
+	return root, nil
+}
+
+// splitParamKey 把 a[b][] 拆分为 ["a", "b", ""]，不含方括号的键原样返回
+func splitParamKey(key string) ([]string, error) {
+	open := strings.IndexByte(key, '[')
+	if open <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}, nil
+	}
+
+	segments := []string{key[:open]}
+	rest := key[open:]
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return nil, fmt.Errorf("%w: malformed key %q", ErrInvalidParamKey, key)
+		}
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+	}
+	if len(segments) > maxParamDepth {
+		return nil, fmt.Errorf("%w: key %q exceeds %d levels", ErrInvalidParamKey, key, maxParamDepth)
+	}
+	for i, seg := range segments[:len(segments)-1] {
+		if seg == "" && i > 0 {
+			return nil, fmt.Errorf("%w: [] must be the last segment in %q", ErrInvalidParamKey, key)
+		}
+	}
+	return segments, nil
+}
+
+// insertParam 把一个键的所有值写入树中
+func insertParam(node map[string]interface{}, segments, values []string, style ParamStyle, key string) error {
+	name := segments[0]
+	if len(segments) == 1 || (len(segments) == 2 && segments[1] == "") {
+		if _, exists := node[name]; exists {
+			return fmt.Errorf("%w: %q conflicts with another parameter", ErrInvalidParamKey, key)
+		}
+		node[name] = leafValue(values, style, len(segments) == 2)
+		return nil
+	}
+
+	child, exists := node[name]
+	if !exists {
+		child = make(map[string]interface{})
+		node[name] = child
+	}
+	m, ok := child.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: %q conflicts with another parameter", ErrInvalidParamKey, key)
+	}
+	return insertParam(m, segments[1:], values, style, key)
+}
+
+// leafValue 把一个键的值转换为字符串或数组
+func leafValue(values []string, style ParamStyle, forceList bool) interface{} {
+	var items []interface{}
+	for _, v := range values {
+		if style == StyleComma && strings.Contains(v, ",") {
+			for _, part := range strings.Split(v, ",") {
+				items = append(items, part)
+			}
+			forceList = true
+			continue
+		}
+		items = append(items, v)
+	}
+	if len(items) == 1 && !forceList {
+		return items[0]
+	}
+	return items
+}
+
+// restoreLists 把键为 0..n-1 的对象还原为数组
+func restoreLists(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for k, item := range m {
+		m[k] = restoreLists(item)
+	}
+
+	items := make([]interface{}, len(m))
+	for k, item := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		items[i] = item
+	}
+	if len(items) == 0 {
+		return m
+	}
+	return items
+}
+
+// sortedKeys 返回排序后的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,173 @@
+package url
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testFilter struct {
+	Status string   `json:"status"`
+	Tags   []string `json:"tags"`
+}
+
+func TestSerializeParams_Styles(t *testing.T) {
+	params := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"status": "active",
+			"tags":   []string{"a", "b c"},
+		},
+		"page": 2,
+	}
+
+	tests := []struct {
+		style ParamStyle
+		want  string
+	}{
+		{StyleDeepObject, "filter[status]=active&filter[tags]=a&filter[tags]=b+c&page=2"},
+		{StyleBracket, "filter[status]=active&filter[tags][]=a&filter[tags][]=b+c&page=2"},
+		{StyleComma, "filter[status]=active&filter[tags]=a,b+c&page=2"},
+	}
+	for _, tt := range tests {
+		if got := SerializeParams(params, tt.style); got != tt.want {
+			t.Errorf("style %d: got %s, want %s", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestSerializeParams_NestedValues(t *testing.T) {
+	params := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"sku": "x1", "qty": 2},
+			map[string]string{"sku": "x&2"},
+		},
+		"filter": testFilter{Status: "open", Tags: []string{"t"}},
+		"empty":  nil,
+	}
+
+	want := "empty=&filter[status]=open&filter[tags][]=t&items[0][qty]=2&items[0][sku]=x1&items[1][sku]=x%262"
+	if got := SerializeParams(params, StyleBracket); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDeserializeParams_Styles(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		style ParamStyle
+		want  map[string]interface{}
+	}{
+		{
+			"deep object",
+			"filter[status]=active&filter[tags]=a&filter[tags]=b&page=2",
+			StyleDeepObject,
+			map[string]interface{}{
+				"filter": map[string]interface{}{"status": "active", "tags": []interface{}{"a", "b"}},
+				"page":   "2",
+			},
+		},
+		{
+			"bracket keeps single element arrays",
+			"filter[tags][]=a&filter%5Bstatus%5D=active",
+			StyleBracket,
+			map[string]interface{}{
+				"filter": map[string]interface{}{"status": "active", "tags": []interface{}{"a"}},
+			},
+		},
+		{
+			"comma",
+			"filter[tags]=a,b&q=x",
+			StyleComma,
+			map[string]interface{}{
+				"filter": map[string]interface{}{"tags": []interface{}{"a", "b"}},
+				"q":      "x",
+			},
+		},
+		{
+			"indexed arrays",
+			"items[0][sku]=x1&items[1][sku]=x2&items[1][qty]=3&ids[1]=b&ids[0]=a&sparse[2]=z",
+			StyleBracket,
+			map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"sku": "x1"},
+					map[string]interface{}{"sku": "x2", "qty": "3"},
+				},
+				"ids":    []interface{}{"a", "b"},
+				"sparse": map[string]interface{}{"2": "z"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeserializeParams(tt.query, tt.style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeserializeParams_RoundTrip(t *testing.T) {
+	params := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"status": "active",
+			"tags":   []interface{}{"a", "b"},
+			"range":  map[string]interface{}{"min": "1", "max": "9"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "x1"},
+		},
+	}
+
+	for _, style := range []ParamStyle{StyleDeepObject, StyleBracket, StyleComma} {
+		got, err := DeserializeParams(SerializeParams(params, style), style)
+		if err != nil {
+			t.Fatalf("style %d: %v", style, err)
+		}
+		if !reflect.DeepEqual(got, params) {
+			t.Errorf("style %d: got %#v, want %#v", style, got, params)
+		}
+	}
+}
+
+func TestDeserializeParams_InvalidKeys(t *testing.T) {
+	for _, query := range []string{
+		"a=1&a[b]=2",
+		"a[]=1&a=2",
+		"a[b]c]=1",
+		"a[][b]=1",
+		"a[1][2][3][4][5][6][7][8][9][10]=x",
+	} {
+		if _, err := DeserializeParams(query, StyleBracket); !errors.Is(err, ErrInvalidParamKey) {
+			t.Errorf("%s: error = %v, want ErrInvalidParamKey", query, err)
+		}
+	}
+
+	// 不指定风格时保持原有行为
+	got, err := DeserializeParams("a[b]=1")
+	if err != nil || got["a[b]"] != "1" {
+		t.Errorf("flat DeserializeParams() = %v, %v", got, err)
+	}
+}
+
+func TestDeserializeParams_NumericTopLevelKeys(t *testing.T) {
+	for _, style := range []ParamStyle{StyleDeepObject, StyleBracket, StyleComma} {
+		got, err := DeserializeParams("0=a&1=b&2[0]=c&2[1]=d", style)
+		if err != nil {
+			t.Fatalf("style %d: %v", style, err)
+		}
+		want := map[string]interface{}{
+			"0": "a",
+			"1": "b",
+			"2": []interface{}{"c", "d"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("style %d: got %#v, want %#v", style, got, want)
+		}
+	}
+}
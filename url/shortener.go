@@ -0,0 +1,230 @@
+package url
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 短链接相关错误
+var (
+	ErrShortLinkNotFound = errors.New("short link not found")
+	ErrShortLinkExpired  = errors.New("short link has expired")
+	// ErrShortCodeExists 存储中已存在相同短码，由 ShortLinkStore.Create 返回
+	ErrShortCodeExists = errors.New("short code already exists")
+	// ErrShortCodeExhausted 多次生成的短码均已被占用，应增加长度或扩大字符集
+	ErrShortCodeExhausted = errors.New("could not generate a unique short code")
+	ErrInvalidAlphabet    = errors.New("invalid short code alphabet")
+)
+
+// DefaultShortCodeAlphabet 默认短码字符集（Base62）
+const DefaultShortCodeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ShortLink 短链接映射
+type ShortLink struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`                  // 原始 URL，可以是带签名的 URL
+	CreatedAt time.Time `json:"created_at"`           // 创建时间
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // 过期时间，零值表示永不过期
+	Clicks    int64     `json:"clicks"`               // 解析次数
+}
+
+// Expired 判断短链接在 now 时是否已过期
+func (l *ShortLink) Expired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && !now.Before(l.ExpiresAt)
+}
+
+// ShortenerOptions 短链接生成器选项
+type ShortenerOptions struct {
+	// 短码字符集，为空时使用 DefaultShortCodeAlphabet；至少 2 个互不相同的可见 ASCII 字符，不能包含 / ? # %
+	Alphabet string
+	// 短码长度，默认 7（Base62 约 3.5 万亿种组合）
+	Length int
+	// 短码冲突时的最大尝试次数，默认 5
+	MaxAttempts int
+	// 短链接前缀，如 "https://s.example.com/"，ShortURL 将其与短码拼接
+	BaseURL string
+	// URL 签名密钥，ShortenSigned 使用
+	SecretKey string
+}
+
+// URLShortener 短链接生成器
+// 短码由 crypto/rand 均匀生成，冲突时重新生成；映射保存在可插拔的 ShortLinkStore 中
+type URLShortener struct {
+	store       ShortLinkStore
+	alphabet    string
+	charset     [256]bool
+	length      int
+	maxAttempts int
+	baseURL     string
+	secretKey   string
+}
+
+// NewURLShortener 创建短链接生成器
+func NewURLShortener(store ShortLinkStore, options ...*ShortenerOptions) (*URLShortener, error) {
+	if store == nil {
+		return nil, errors.New("short link store cannot be nil")
+	}
+	opts := &ShortenerOptions{}
+	if len(options) > 0 && options[0] != nil {
+		opts = options[0]
+	}
+
+	s := &URLShortener{
+		store:       store,
+		alphabet:    opts.Alphabet,
+		length:      opts.Length,
+		maxAttempts: opts.MaxAttempts,
+		baseURL:     opts.BaseURL,
+		secretKey:   opts.SecretKey,
+	}
+	if s.alphabet == "" {
+		s.alphabet = DefaultShortCodeAlphabet
+	}
+	if s.length <= 0 {
+		s.length = 7
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = 5
+	}
+
+	if len(s.alphabet) < 2 {
+		return nil, fmt.Errorf("%w: must contain at least 2 characters", ErrInvalidAlphabet)
+	}
+	for i := 0; i < len(s.alphabet); i++ {
+		c := s.alphabet[i]
+		if c >= 0x7f || c <= ' ' || c == '/' || c == '?' || c == '#' || c == '%' {
+			return nil, fmt.Errorf("%w: character %q is not allowed", ErrInvalidAlphabet, c)
+		}
+		if s.charset[c] {
+			return nil, fmt.Errorf("%w: duplicate character %q", ErrInvalidAlphabet, c)
+		}
+		s.charset[c] = true
+	}
+	return s, nil
+}
+
+// Shorten 为目标 URL 生成短链接，ttl 为 0 时永不过期
+func (s *URLShortener) Shorten(ctx context.Context, target string, ttl time.Duration) (*ShortLink, error) {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidBaseURL, target)
+	}
+
+	now := time.Now()
+	link := &ShortLink{URL: target, CreatedAt: now}
+	if ttl > 0 {
+		link.ExpiresAt = now.Add(ttl)
+	}
+
+	for attempt := 0; attempt < s.maxAttempts; attempt++ {
+		code, err := s.generateCode()
+		if err != nil {
+			return nil, err
+		}
+		link.Code = code
+		err = s.store.Create(ctx, link)
+		if err == nil {
+			return link, nil
+		}
+		if !errors.Is(err, ErrShortCodeExists) {
+			return nil, err
+		}
+	}
+	return nil, ErrShortCodeExhausted
+}
+
+// ShortenSigned 创建带签名的 URL 并生成短链接，短链接与签名同时过期
+// 需要在选项中设置 SecretKey；expireSeconds 为 0 时签名使用默认有效期（1 小时）
+func (s *URLShortener) ShortenSigned(ctx context.Context, baseURL string, params map[string]string, expireSeconds int64) (*ShortLink, error) {
+	if expireSeconds <= 0 {
+		expireSeconds = 3600
+	}
+	signed, err := CreateSignedURL(baseURL, s.secretKey, params, expireSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return s.Shorten(ctx, signed, time.Duration(expireSeconds)*time.Second)
+}
+
+// Resolve 解析短码并返回原始 URL，同时累加点击次数
+func (s *URLShortener) Resolve(ctx context.Context, code string) (string, error) {
+	link, err := s.Stats(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.store.IncrementClicks(ctx, code); err != nil {
+		return "", err
+	}
+	return link.URL, nil
+}
+
+// Stats 返回短链接信息而不计入点击，已过期时返回 ErrShortLinkExpired
+func (s *URLShortener) Stats(ctx context.Context, code string) (*ShortLink, error) {
+	// 不属于字符集的短码不可能存在，无需查询存储
+	if !s.validCode(code) {
+		return nil, ErrShortLinkNotFound
+	}
+	link, err := s.store.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if link.Expired(time.Now()) {
+		return nil, ErrShortLinkExpired
+	}
+	return link, nil
+}
+
+// Delete 删除短链接
+func (s *URLShortener) Delete(ctx context.Context, code string) error {
+	return s.store.Delete(ctx, code)
+}
+
+// ShortURL 返回短码对应的完整短链接
+func (s *URLShortener) ShortURL(code string) string {
+	if s.baseURL == "" {
+		return code
+	}
+	return strings.TrimSuffix(s.baseURL, "/") + "/" + code
+}
+
+// generateCode 使用拒绝采样从字符集中均匀生成短码
+func (s *URLShortener) generateCode() (string, error) {
+	n := len(s.alphabet)
+	// 大于等于 limit 的随机字节会导致取模偏差，直接丢弃
+	limit := 256 - 256%n
+	code := make([]byte, 0, s.length)
+	buf := make([]byte, s.length*2)
+	for len(code) < s.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate short code: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, s.alphabet[int(b)%n])
+			if len(code) == s.length {
+				break
+			}
+		}
+	}
+	return string(code), nil
+}
+
+// validCode 判断短码是否只包含字符集中的字符；不校验长度，调整 Length 后旧短码仍可解析
+func (s *URLShortener) validCode(code string) bool {
+	if code == "" {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if !s.charset[code[i]] {
+			return false
+		}
+	}
+	return true
+}
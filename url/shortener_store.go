@@ -0,0 +1,325 @@
+package url
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShortLinkStore 短链接映射存储接口
+// 默认提供进程内、Redis 与 database/sql（PostgreSQL）实现；多实例部署时应使用共享存储
+type ShortLinkStore interface {
+	// Create 保存新的短链接，短码已存在（且未过期）时返回 ErrShortCodeExists
+	Create(ctx context.Context, link *ShortLink) error
+	// Get 返回短链接，不存在时返回 ErrShortLinkNotFound；是否过期由调用方判断
+	Get(ctx context.Context, code string) (*ShortLink, error)
+	// IncrementClicks 将点击次数加一并返回新值，不存在时返回 ErrShortLinkNotFound
+	IncrementClicks(ctx context.Context, code string) (int64, error)
+	// Delete 删除短链接，不存在时不报错
+	Delete(ctx context.Context, code string) error
+}
+
+// MemoryShortLinkStore 进程内短链接存储，适用于单实例部署或测试
+type MemoryShortLinkStore struct {
+	mu    sync.RWMutex
+	links map[string]*ShortLink
+}
+
+// NewMemoryShortLinkStore 创建进程内短链接存储
+func NewMemoryShortLinkStore() *MemoryShortLinkStore {
+	return &MemoryShortLinkStore{links: make(map[string]*ShortLink)}
+}
+
+// Create 实现 ShortLinkStore 接口，已过期的短码可被复用
+func (s *MemoryShortLinkStore) Create(_ context.Context, link *ShortLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.links[link.Code]; ok && !existing.Expired(time.Now()) {
+		return ErrShortCodeExists
+	}
+	stored := *link
+	s.links[link.Code] = &stored
+	return nil
+}
+
+// Get 实现 ShortLinkStore 接口
+func (s *MemoryShortLinkStore) Get(_ context.Context, code string) (*ShortLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	link, ok := s.links[code]
+	if !ok {
+		return nil, ErrShortLinkNotFound
+	}
+	result := *link
+	return &result, nil
+}
+
+// IncrementClicks 实现 ShortLinkStore 接口
+func (s *MemoryShortLinkStore) IncrementClicks(_ context.Context, code string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[code]
+	if !ok {
+		return 0, ErrShortLinkNotFound
+	}
+	link.Clicks++
+	return link.Clicks, nil
+}
+
+// Delete 实现 ShortLinkStore 接口
+func (s *MemoryShortLinkStore) Delete(_ context.Context, code string) error {
+	s.mu.Lock()
+	delete(s.links, code)
+	s.mu.Unlock()
+	return nil
+}
+
+// Cleanup 删除已过期的短链接，返回删除数量
+func (s *MemoryShortLinkStore) Cleanup(_ context.Context) (int, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cleaned := 0
+	for code, link := range s.links {
+		if link.Expired(now) {
+			delete(s.links, code)
+			cleaned++
+		}
+	}
+	return cleaned, nil
+}
+
+// ShortLinkRedisClient RedisShortLinkStore 所需的最小 Redis 客户端接口
+// 不直接依赖具体的 Redis 库，可用几行代码适配 go-redis 等客户端：
+//
+//	type redisAdapter struct{ c *redis.Client }
+//
+//	func (a redisAdapter) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+//		return a.c.SetNX(ctx, key, value, ttl).Result()
+//	}
+//	func (a redisAdapter) Get(ctx context.Context, key string) (string, bool, error) {
+//		v, err := a.c.Get(ctx, key).Result()
+//		if err == redis.Nil {
+//			return "", false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//	func (a redisAdapter) IncrXX(ctx context.Context, key string) (int64, bool, error) {
+//		// 只在键存在时自增，避免为已过期的短链接重新创建计数键
+//		v, err := a.c.Eval(ctx, `if redis.call("EXISTS", KEYS[1]) == 1 then return redis.call("INCR", KEYS[1]) end return -1`, []string{key}).Int64()
+//		return v, err == nil && v >= 0, err
+//	}
+//	func (a redisAdapter) Del(ctx context.Context, keys ...string) error {
+//		return a.c.Del(ctx, keys...).Err()
+//	}
+type ShortLinkRedisClient interface {
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	IncrXX(ctx context.Context, key string) (value int64, found bool, err error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+// RedisShortLinkStore 基于 Redis 的短链接存储
+// 映射以 JSON 保存在 prefix + 短码，点击次数保存在 prefix + 短码 + ":clicks"，两者 TTL 与短链接有效期一致
+type RedisShortLinkStore struct {
+	client ShortLinkRedisClient
+	prefix string
+}
+
+// NewRedisShortLinkStore 创建 Redis 短链接存储，prefix 为空时使用 "url:short:"
+func NewRedisShortLinkStore(client ShortLinkRedisClient, prefix string) *RedisShortLinkStore {
+	if prefix == "" {
+		prefix = "url:short:"
+	}
+	return &RedisShortLinkStore{client: client, prefix: prefix}
+}
+
+func (s *RedisShortLinkStore) clicksKey(code string) string {
+	return s.prefix + code + ":clicks"
+}
+
+// Create 实现 ShortLinkStore 接口
+func (s *RedisShortLinkStore) Create(ctx context.Context, link *ShortLink) error {
+	var ttl time.Duration
+	if !link.ExpiresAt.IsZero() {
+		if ttl = time.Until(link.ExpiresAt); ttl <= 0 {
+			return ErrShortLinkExpired
+		}
+	}
+	data, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	ok, err := s.client.SetNX(ctx, s.prefix+link.Code, string(data), ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrShortCodeExists
+	}
+	// 计数键先于首次点击创建并带上相同的 TTL，INCR 不会改变 TTL
+	_, err = s.client.SetNX(ctx, s.clicksKey(link.Code), strconv.FormatInt(link.Clicks, 10), ttl)
+	return err
+}
+
+// Get 实现 ShortLinkStore 接口
+func (s *RedisShortLinkStore) Get(ctx context.Context, code string) (*ShortLink, error) {
+	value, found, err := s.client.Get(ctx, s.prefix+code)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrShortLinkNotFound
+	}
+	var link ShortLink
+	if err := json.Unmarshal([]byte(value), &link); err != nil {
+		return nil, fmt.Errorf("invalid short link value for %q: %w", code, err)
+	}
+
+	clicks, found, err := s.client.Get(ctx, s.clicksKey(code))
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if link.Clicks, err = strconv.ParseInt(clicks, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid click count for %q: %w", code, err)
+		}
+	}
+	return &link, nil
+}
+
+// IncrementClicks 实现 ShortLinkStore 接口
+func (s *RedisShortLinkStore) IncrementClicks(ctx context.Context, code string) (int64, error) {
+	clicks, found, err := s.client.IncrXX(ctx, s.clicksKey(code))
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, ErrShortLinkNotFound
+	}
+	return clicks, nil
+}
+
+// Delete 实现 ShortLinkStore 接口
+func (s *RedisShortLinkStore) Delete(ctx context.Context, code string) error {
+	return s.client.Del(ctx, s.prefix+code, s.clicksKey(code))
+}
+
+// sqlIdentifier 限制表名只能包含字母、数字、下划线和点（schema.table）
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLShortLinkStore 基于 database/sql 的短链接存储，SQL 语法面向 PostgreSQL
+// 使用 pgx 时可通过 github.com/jackc/pgx/v5/stdlib 获得 *sql.DB，表结构见 CreateTable
+type SQLShortLinkStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLShortLinkStore 创建数据库短链接存储，table 为空时使用 "short_links"
+func NewSQLShortLinkStore(db *sql.DB, table string) (*SQLShortLinkStore, error) {
+	if db == nil {
+		return nil, errors.New("db cannot be nil")
+	}
+	if table == "" {
+		table = "short_links"
+	}
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	return &SQLShortLinkStore{db: db, table: table}, nil
+}
+
+// CreateTable 创建短链接表及过期时间索引（已存在时跳过）
+func (s *SQLShortLinkStore) CreateTable(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	code       VARCHAR(64) PRIMARY KEY,
+	url        TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ,
+	clicks     BIGINT NOT NULL DEFAULT 0
+)`); err != nil {
+		return err
+	}
+	index := strings.ReplaceAll(s.table, ".", "_") + "_expires_at_idx"
+	_, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS `+index+` ON `+s.table+` (expires_at)`)
+	return err
+}
+
+// Create 实现 ShortLinkStore 接口，已过期的短码会被新映射覆盖
+func (s *SQLShortLinkStore) Create(ctx context.Context, link *ShortLink) error {
+	var expireAt sql.NullTime
+	if !link.ExpiresAt.IsZero() {
+		expireAt = sql.NullTime{Time: link.ExpiresAt, Valid: true}
+	}
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO `+s.table+` (code, url, created_at, expires_at, clicks) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (code) DO UPDATE SET url = EXCLUDED.url, created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at, clicks = EXCLUDED.clicks
+WHERE `+s.table+`.expires_at IS NOT NULL AND `+s.table+`.expires_at <= $3`,
+		link.Code, link.URL, link.CreatedAt, expireAt, link.Clicks)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrShortCodeExists
+	}
+	return nil
+}
+
+// Get 实现 ShortLinkStore 接口
+func (s *SQLShortLinkStore) Get(ctx context.Context, code string) (*ShortLink, error) {
+	link := ShortLink{Code: code}
+	var expireAt sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT url, created_at, expires_at, clicks FROM `+s.table+` WHERE code = $1`, code,
+	).Scan(&link.URL, &link.CreatedAt, &expireAt, &link.Clicks)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShortLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if expireAt.Valid {
+		link.ExpiresAt = expireAt.Time
+	}
+	return &link, nil
+}
+
+// IncrementClicks 实现 ShortLinkStore 接口
+func (s *SQLShortLinkStore) IncrementClicks(ctx context.Context, code string) (int64, error) {
+	var clicks int64
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE `+s.table+` SET clicks = clicks + 1 WHERE code = $1 RETURNING clicks`, code,
+	).Scan(&clicks)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrShortLinkNotFound
+	}
+	return clicks, err
+}
+
+// Delete 实现 ShortLinkStore 接口
+func (s *SQLShortLinkStore) Delete(ctx context.Context, code string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE code = $1`, code)
+	return err
+}
+
+// Cleanup 删除已过期的短链接，返回删除数量
+func (s *SQLShortLinkStore) Cleanup(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM `+s.table+` WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,217 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeShortLinkRedis 模拟 ShortLinkRedisClient，不处理 TTL
+type fakeShortLinkRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+	ttls map[string]time.Duration
+}
+
+func newFakeShortLinkRedis() *fakeShortLinkRedis {
+	return &fakeShortLinkRedis{data: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (r *fakeShortLinkRedis) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.data[key]; ok {
+		return false, nil
+	}
+	r.data[key], r.ttls[key] = value, ttl
+	return true, nil
+}
+
+func (r *fakeShortLinkRedis) Get(_ context.Context, key string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.data[key]
+	return v, ok, nil
+}
+
+func (r *fakeShortLinkRedis) IncrXX(_ context.Context, key string) (int64, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v, ok := r.data[key]
+	if !ok {
+		return 0, false, nil
+	}
+	n, _ := strconv.ParseInt(v, 10, 64)
+	n++
+	r.data[key] = strconv.FormatInt(n, 10)
+	return n, true, nil
+}
+
+func (r *fakeShortLinkRedis) Del(_ context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.data, key)
+	}
+	return nil
+}
+
+// collidingStore 前几次 Create 返回冲突，用于测试重试
+type collidingStore struct {
+	*MemoryShortLinkStore
+	collisions int
+}
+
+func (s *collidingStore) Create(ctx context.Context, link *ShortLink) error {
+	if s.collisions > 0 {
+		s.collisions--
+		return ErrShortCodeExists
+	}
+	return s.MemoryShortLinkStore.Create(ctx, link)
+}
+
+func TestURLShortener_Stores(t *testing.T) {
+	stores := map[string]ShortLinkStore{
+		"memory": NewMemoryShortLinkStore(),
+		"redis":  NewRedisShortLinkStore(newFakeShortLinkRedis(), ""),
+	}
+	ctx := context.Background()
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewURLShortener(store, &ShortenerOptions{BaseURL: "https://s.example.com/"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			target := "https://example.com/articles/42?ref=mail"
+			link, err := s.Shorten(ctx, target, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(link.Code) != 7 {
+				t.Errorf("code length = %d, want 7", len(link.Code))
+			}
+			if got := s.ShortURL(link.Code); got != "https://s.example.com/"+link.Code {
+				t.Errorf("ShortURL() = %s", got)
+			}
+
+			for i := 0; i < 3; i++ {
+				got, err := s.Resolve(ctx, link.Code)
+				if err != nil || got != target {
+					t.Fatalf("Resolve() = %q, %v", got, err)
+				}
+			}
+			stats, err := s.Stats(ctx, link.Code)
+			if err != nil || stats.Clicks != 3 || stats.URL != target {
+				t.Errorf("Stats() = %+v, %v", stats, err)
+			}
+
+			if err := s.Delete(ctx, link.Code); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Resolve(ctx, link.Code); !errors.Is(err, ErrShortLinkNotFound) {
+				t.Errorf("Resolve() after Delete error = %v", err)
+			}
+		})
+	}
+}
+
+func TestURLShortener_Expiry(t *testing.T) {
+	store := NewMemoryShortLinkStore()
+	s, _ := NewURLShortener(store)
+	ctx := context.Background()
+
+	link, err := s.Shorten(ctx, "https://example.com/a", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 直接改写存储中的过期时间，模拟时间流逝
+	store.links[link.Code].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := s.Resolve(ctx, link.Code); !errors.Is(err, ErrShortLinkExpired) {
+		t.Errorf("error = %v, want ErrShortLinkExpired", err)
+	}
+	if n, _ := store.Cleanup(ctx); n != 1 {
+		t.Errorf("Cleanup() = %d, want 1", n)
+	}
+
+	permanent, _ := s.Shorten(ctx, "https://example.com/b", 0)
+	if !permanent.ExpiresAt.IsZero() {
+		t.Error("ttl 0 should never expire")
+	}
+}
+
+func TestURLShortener_ShortenSigned(t *testing.T) {
+	const secret = "short-link-secret"
+	s, _ := NewURLShortener(NewMemoryShortLinkStore(), &ShortenerOptions{SecretKey: secret})
+	ctx := context.Background()
+
+	link, err := s.ShortenSigned(ctx, "https://example.com/download", map[string]string{"file": "report.pdf"}, 600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(link.ExpiresAt); d <= 0 || d > 600*time.Second {
+		t.Errorf("link expiry = %v, want about 10 minutes", d)
+	}
+	signed, err := s.Resolve(ctx, link.Code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ValidateSignature(signed, secret, 0); !ok {
+		t.Errorf("resolved URL signature invalid: %v", err)
+	}
+
+	noKey, _ := NewURLShortener(NewMemoryShortLinkStore())
+	if _, err := noKey.ShortenSigned(ctx, "https://example.com", nil, 0); !errors.Is(err, ErrEmptySecretKey) {
+		t.Errorf("error = %v, want ErrEmptySecretKey", err)
+	}
+}
+
+func TestURLShortener_Codes(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := NewURLShortener(NewMemoryShortLinkStore(), &ShortenerOptions{Alphabet: "abc", Length: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		link, err := s.Shorten(ctx, "https://example.com", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(link.Code) != 12 || strings.Trim(link.Code, "abc") != "" {
+			t.Errorf("code %q does not match alphabet or length", link.Code)
+		}
+		if seen[link.Code] {
+			t.Errorf("duplicate code %q", link.Code)
+		}
+		seen[link.Code] = true
+	}
+	if _, err := s.Resolve(ctx, "abd"); !errors.Is(err, ErrShortLinkNotFound) {
+		t.Errorf("code outside alphabet error = %v", err)
+	}
+
+	// 冲突时重试，超过次数返回 ErrShortCodeExhausted
+	retry, _ := NewURLShortener(&collidingStore{NewMemoryShortLinkStore(), 2}, &ShortenerOptions{MaxAttempts: 3})
+	if _, err := retry.Shorten(ctx, "https://example.com", 0); err != nil {
+		t.Errorf("Shorten() with retries: %v", err)
+	}
+	exhausted, _ := NewURLShortener(&collidingStore{NewMemoryShortLinkStore(), 3}, &ShortenerOptions{MaxAttempts: 3})
+	if _, err := exhausted.Shorten(ctx, "https://example.com", 0); !errors.Is(err, ErrShortCodeExhausted) {
+		t.Errorf("error = %v, want ErrShortCodeExhausted", err)
+	}
+
+	for _, alphabet := range []string{"a", "aba", "ab/", "ab c", "abé"} {
+		if _, err := NewURLShortener(NewMemoryShortLinkStore(), &ShortenerOptions{Alphabet: alphabet}); !errors.Is(err, ErrInvalidAlphabet) {
+			t.Errorf("alphabet %q: error = %v, want ErrInvalidAlphabet", alphabet, err)
+		}
+	}
+	if _, err := s.Shorten(ctx, "/relative/path", 0); !errors.Is(err, ErrInvalidBaseURL) {
+		t.Errorf("relative target error = %v, want ErrInvalidBaseURL", err)
+	}
+}
@@ -1,10 +1,6 @@
 package url
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/iwen-conf/utils-pkg/crypto/macsign"
 )
 
 // 预定义错误类型
@@ -32,13 +30,14 @@ const allowedTimeDrift = 300 // 5分钟
 
 // URLBuilder URL 构建器
 type URLBuilder struct {
-	baseURL    string     // 基础URL
-	params     url.Values // 使用url.Values替代map以避免类型转换
-	fragment   string     // URL片段
-	sortParams bool       // 是否排序参数
-	secretKey  string     // 密钥
-	timestamp  int64      // 时间戳
-	expiration int64      // 过期时间（秒）
+	baseURL    string      // 基础URL
+	params     url.Values  // 使用url.Values替代map以避免类型转换
+	fragment   string      // URL片段
+	sortParams bool        // 是否排序参数
+	secretKey  string      // 密钥
+	timestamp  int64       // 时间戳
+	expiration int64       // 过期时间（秒）
+	keys       KeyProvider // 带版本的密钥，设置后优先于 secretKey
 }
 
 // NewURLBuilder 创建新的 URL 构建器
@@ -53,12 +52,20 @@ func NewURLBuilder(baseURL string, secretKey string) *URLBuilder {
 	}
 }
 
+// NewURLBuilderWithKeys 创建使用带版本密钥签名的 URL 构建器
+// 签名使用 keys 的当前密钥，密钥版本写入 _v 参数，验证时使用 ValidateSignatureWithKeys
+func NewURLBuilderWithKeys(baseURL string, keys KeyProvider) *URLBuilder {
+	b := NewURLBuilder(baseURL, "")
+	b.keys = keys
+	return b
+}
+
 // Validate 验证构建器的基本参数
 func (b *URLBuilder) Validate() error {
 	if b.baseURL == "" {
 		return ErrInvalidBaseURL
 	}
-	if b.secretKey == "" {
+	if b.secretKey == "" && b.keys == nil {
 		return ErrEmptySecretKey
 	}
 	if _, err := url.Parse(b.baseURL); err != nil {
@@ -105,17 +112,27 @@ func (b *URLBuilder) SetExpiration(seconds int64) *URLBuilder {
 	return b
 }
 
-// generateSignature 生成签名
-func (b *URLBuilder) generateSignature(queryString string) string {
-	// 组合待签名字符串：时间戳 + 查询字符串
-	signStr := fmt.Sprintf("%d%s", b.timestamp, queryString)
-
-	// 使用 HMAC-SHA256 算法生成签名
-	h := hmac.New(sha256.New, []byte(b.secretKey))
-	h.Write([]byte(signStr))
-	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+// newSigner 创建 URL 签名器：HMAC-SHA256，标准 Base64 编码
+func newSigner(secretKey string) (*macsign.Signer, error) {
+	signer, err := macsign.New([]byte(secretKey), macsign.EncodingBase64)
+	if err != nil {
+		return nil, ErrEmptySecretKey
+	}
+	return signer, nil
+}
 
-	return signature
+// signer 返回签名器与密钥版本，未设置密钥提供者时版本为空
+func (b *URLBuilder) signer() (*macsign.Signer, string, error) {
+	if b.keys == nil {
+		signer, err := newSigner(b.secretKey)
+		return signer, "", err
+	}
+	key, err := b.keys.CurrentKey()
+	if err != nil {
+		return nil, "", err
+	}
+	signer, err := key.signer()
+	return signer, key.Version, err
 }
 
 // Build 构建完整的 URL
@@ -168,12 +185,15 @@ func (b *URLBuilder) Build() (string, error) {
 		query.Set("_exp", fmt.Sprintf("%d", b.expiration))
 	}
 
-	// 生成查询字符串
-	queryStr := query.Encode()
-
-	// 生成并添加签名
-	signature := b.generateSignature(queryStr)
-	query.Set("_sign", signature)
+	// 生成并添加签名，待签名字符串为：时间戳 + 查询字符串；密钥版本参与签名
+	signer, version, err := b.signer()
+	if err != nil {
+		return "", err
+	}
+	if version != "" {
+		query.Set("_v", version)
+	}
+	query.Set("_sign", signer.SignTimestamped(b.timestamp, []byte(macsign.CanonicalQuery(query))))
 
 	// 构建最终URL
 	var sb strings.Builder
@@ -229,10 +249,15 @@ func ParseURL(rawURL string) (map[string]interface{}, error) {
 }
 
 // SerializeParams 序列化参数为 URL 查询字符串
-func SerializeParams(params map[string]interface{}) string {
+// 不指定 style 时对象以 JSON 字符串作为参数值；指定 style 时按层级格式展开，
+// 如 filter[status]=active&filter[tags][]=a（StyleBracket），详见 ParamStyle
+func SerializeParams(params map[string]interface{}, style ...ParamStyle) string {
 	if len(params) == 0 {
 		return ""
 	}
+	if len(style) > 0 {
+		return serializeHierarchical(params, style[0])
+	}
 
 	values := url.Values{}
 	for k, v := range params {
@@ -259,10 +284,17 @@ func SerializeParams(params map[string]interface{}) string {
 
 // ValidateSignature 验证 URL 签名
 func ValidateSignature(rawURL string, secretKey string, maxAgeSeconds int64) (bool, error) {
-	if secretKey == "" {
-		return false, ErrEmptySecretKey
+	signer, err := newSigner(secretKey)
+	if err != nil {
+		return false, err
 	}
+	return validateSignature(rawURL, maxAgeSeconds, func(string) (*macsign.Signer, error) {
+		return signer, nil
+	})
+}
 
+// validateSignature 校验时间戳与签名，signerFor 按 _v 参数返回签名器
+func validateSignature(rawURL string, maxAgeSeconds int64, signerFor func(version string) (*macsign.Signer, error)) (bool, error) {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		return false, fmt.Errorf("无效的URL: %w", err)
@@ -314,17 +346,14 @@ func ValidateSignature(rawURL string, secretKey string, maxAgeSeconds int64) (bo
 		return false, ErrExpiredURL
 	}
 
-	// 移除签名参数后重新生成签名
-	query.Del("_sign")
-	queryStr := query.Encode()
-
-	// 使用相同的算法生成签名
-	h := hmac.New(sha256.New, []byte(secretKey))
-	h.Write([]byte(fmt.Sprintf("%d%s", ts, queryStr)))
-	expectedSignature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	signer, err := signerFor(query.Get("_v"))
+	if err != nil {
+		return false, err
+	}
 
-	// 使用恒定时间比较签名
-	if subtle.ConstantTimeCompare([]byte(signature), []byte(expectedSignature)) != 1 {
+	// 移除签名参数后按相同规则重新计算并以恒定时间比较签名
+	queryStr := macsign.CanonicalQuery(query, "_sign")
+	if !signer.VerifyTimestamped(ts, []byte(queryStr), signature) {
 		return false, ErrInvalidSignature
 	}
 
@@ -344,7 +373,10 @@ func BatchValidateSignatures(urls []string, secretKey string, maxAgeSeconds int6
 }
 
 // DeserializeParams 反序列化 URL 查询字符串为参数映射
-func DeserializeParams(queryString string) (map[string]interface{}, error) {
+// 不指定 style 时键原样保留，多值参数为 []string；
+// 指定 style 时按层级格式还原为嵌套的 map[string]interface{} 与 []interface{}，
+// 键格式错误或路径冲突时返回 ErrInvalidParamKey
+func DeserializeParams(queryString string, style ...ParamStyle) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	if queryString == "" {
 		return result, nil
@@ -357,6 +389,9 @@ func DeserializeParams(queryString string) (map[string]interface{}, error) {
 	if err != nil {
 		return nil, fmt.Errorf("无效的查询字符串: %w", err)
 	}
+	if len(style) > 0 {
+		return deserializeHierarchical(values, style[0])
+	}
 
 	for k, v := range values {
 		if len(v) == 1 {
@@ -0,0 +1,282 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DeviceType 设备类型
+type DeviceType string
+
+const (
+	DeviceUnknown DeviceType = ""
+	DeviceDesktop DeviceType = "desktop"
+	DeviceMobile  DeviceType = "mobile"
+	DeviceTablet  DeviceType = "tablet"
+	DeviceTV      DeviceType = "tv"
+	DeviceBot     DeviceType = "bot"
+)
+
+// OSInfo 操作系统信息
+type OSInfo struct {
+	Name    string // 系统名称，如 "Windows"、"macOS"、"iOS"、"Android"、"Linux"
+	Version string // 系统版本，Windows 为 "10"、"7" 等发行名，其他系统为版本号
+}
+
+// DeviceInfo 设备信息
+type DeviceInfo struct {
+	Type  DeviceType // 设备类型
+	Brand string     // 设备品牌，如 "Apple"、"Samsung"、"Xiaomi"
+	Model string     // 设备型号，如 "iPhone"、"SM-G991B"、"Pixel 7"
+}
+
+// ClientInfo 客户端的完整识别结果
+type ClientInfo struct {
+	IsBot   bool        // 是否为爬虫
+	Browser BrowserInfo // 浏览器信息，与 GetBrowserInfo 一致
+	OS      OSInfo      // 操作系统信息
+	Device  DeviceInfo  // 设备信息
+}
+
+// osRule 操作系统识别规则，按顺序匹配，regex 的第一个捕获组为版本号
+type osRule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+var (
+	osRules = []osRule{
+		{"Windows Phone", regexp.MustCompile(`(?i)windows phone(?: os)? ([\d.]+)`)},
+		{"Windows", regexp.MustCompile(`(?i)windows nt ([\d.]+)`)},
+		{"iOS", regexp.MustCompile(`(?i)(?:iphone|ipad|ipod).*? os ([\d_]+)`)},
+		{"HarmonyOS", regexp.MustCompile(`(?i)(?:openharmony|harmonyos)(?:[ /]([\d.]+))?`)},
+		{"Android", regexp.MustCompile(`(?i)android(?: ([\d.]+))?`)},
+		{"Chrome OS", regexp.MustCompile(`(?i)cros \S+ ([\d.]+)`)},
+		{"Tizen", regexp.MustCompile(`(?i)tizen(?: ([\d.]+))?`)},
+		{"webOS", regexp.MustCompile(`(?i)(?:web0s|webos)(?:/([\d.]+))?`)},
+		{"macOS", regexp.MustCompile(`(?i)mac os x(?: ([\d_.]+))?`)},
+		{"Linux", regexp.MustCompile(`(?i)linux`)},
+	}
+
+	// windowsVersions Windows NT 内核版本与发行名的对应关系（Windows 11 的 UA 仍为 NT 10.0）
+	windowsVersions = map[string]string{
+		"10.0": "10",
+		"6.3":  "8.1",
+		"6.2":  "8",
+		"6.1":  "7",
+		"6.0":  "Vista",
+		"5.2":  "XP",
+		"5.1":  "XP",
+	}
+
+	// tvRegex 智能电视与电视盒子的标识
+	tvRegex = regexp.MustCompile(`(?i)smart-?tv|googletv|android tv|appletv|hbbtv|netcast|web0s|webos.+tv|tizen.+tv|crkey|roku|bravia|\baft[a-z]`)
+	// tabletRegex 平板标识；不含 Mobile 的 Android 设备也视为平板
+	tabletRegex = regexp.MustCompile(`(?i)ipad|tablet|kindle|silk/|playbook|\bsm-t\d|\bkf[a-z]{2,}`)
+	// mobileRegex 手机标识
+	mobileRegex = regexp.MustCompile(`(?i)mobile|iphone|ipod|windows phone|blackberry|opera mini`)
+
+	// localeRegex UA 中的语言标记，如 "en-us"、"zh_CN"
+	localeRegex = regexp.MustCompile(`^[a-zA-Z]{2}[-_][a-zA-Z]{2}$`)
+
+	// androidSkipTokens 型号所在括号中不属于型号的标记（小写）
+	androidSkipTokens = map[string]bool{
+		"linux": true, "u": true, "k": true, "wv": true, "mobile": true, "harmonyos": true,
+	}
+
+	// brandPrefixes 按型号前缀识别品牌，不区分大小写
+	brandPrefixes = []struct{ prefix, brand string }{
+		{"samsung", "Samsung"},
+		{"sm-", "Samsung"},
+		{"gt-", "Samsung"},
+		{"pixel", "Google"},
+		{"nexus", "Google"},
+		{"redmi", "Xiaomi"},
+		{"poco", "Xiaomi"},
+		{"xiaomi", "Xiaomi"},
+		{"mi ", "Xiaomi"},
+		{"m2", "Xiaomi"},
+		{"huawei", "Huawei"},
+		{"honor", "Honor"},
+		{"oppo", "OPPO"},
+		{"cph", "OPPO"},
+		{"vivo", "vivo"},
+		{"rmx", "realme"},
+		{"oneplus", "OnePlus"},
+		{"moto", "Motorola"},
+		{"nokia", "Nokia"},
+		{"lg-", "LG"},
+		{"lm-", "LG"},
+		{"lenovo", "Lenovo"},
+		{"kf", "Amazon"},
+		{"aft", "Amazon"},
+	}
+
+	// tvBrands 电视 UA 中的品牌标识
+	tvBrands = []struct{ marker, brand string }{
+		{"samsung", "Samsung"},
+		{"web0s", "LG"},
+		{"webos", "LG"},
+		{"netcast", "LG"},
+		{"bravia", "Sony"},
+		{"crkey", "Google"},
+		{"appletv", "Apple"},
+		{"roku", "Roku"},
+		{"aft", "Amazon"},
+	}
+)
+
+// clientInfoCache 完整识别结果缓存，与浏览器识别结果缓存的配置相同
+var clientInfoCache = newResultCache[ClientInfo]()
+
+// GetClientInfo 识别浏览器、操作系统与设备
+// 爬虫的设备类型为 DeviceBot；iPadOS 13 起默认请求桌面版网页，其 UA 与 macOS 相同，会被识别为 Mac
+func GetClientInfo(userAgent string) ClientInfo {
+	if userAgent == "" {
+		return ClientInfo{}
+	}
+	reg := currentRegistry.Load()
+	if result, ok := clientInfoCache.Get(reg, userAgent); ok {
+		return result
+	}
+
+	ua := strings.ToLower(userAgent)
+	result := ClientInfo{
+		IsBot:   reg.fastBotCheck(ua),
+		Browser: getBrowserInfo(reg, userAgent),
+		OS:      detectOS(userAgent),
+	}
+	if result.IsBot {
+		result.Device = DeviceInfo{Type: DeviceBot}
+	} else {
+		result.Device = detectDevice(userAgent, ua, result.OS.Name)
+	}
+
+	clientInfoCache.Set(reg, userAgent, result)
+	return result
+}
+
+// GetOSInfo 识别操作系统
+func GetOSInfo(userAgent string) OSInfo {
+	return GetClientInfo(userAgent).OS
+}
+
+// GetDeviceInfo 识别设备类型、品牌与型号
+func GetDeviceInfo(userAgent string) DeviceInfo {
+	return GetClientInfo(userAgent).Device
+}
+
+// detectOS 按规则顺序识别操作系统
+func detectOS(userAgent string) OSInfo {
+	for _, rule := range osRules {
+		match := rule.regex.FindStringSubmatch(userAgent)
+		if match == nil {
+			continue
+		}
+		info := OSInfo{Name: rule.name}
+		if len(match) > 1 {
+			info.Version = strings.ReplaceAll(match[1], "_", ".")
+		}
+		if rule.name == "Windows" {
+			if name, ok := windowsVersions[info.Version]; ok {
+				info.Version = name
+			}
+		}
+		return info
+	}
+	return OSInfo{}
+}
+
+// detectDevice 识别设备类型、品牌与型号，ua 为小写的 User-Agent
+func detectDevice(userAgent, ua, osName string) DeviceInfo {
+	var info DeviceInfo
+	switch {
+	case tvRegex.MatchString(userAgent):
+		info.Type = DeviceTV
+		for _, b := range tvBrands {
+			if strings.Contains(ua, b.marker) {
+				info.Brand = b.brand
+				break
+			}
+		}
+	case tabletRegex.MatchString(userAgent):
+		info.Type = DeviceTablet
+	case mobileRegex.MatchString(userAgent):
+		info.Type = DeviceMobile
+	case osName == "Android" || osName == "HarmonyOS":
+		info.Type = DeviceTablet
+	case osName == "Windows" || osName == "macOS" || osName == "Linux" || osName == "Chrome OS":
+		info.Type = DeviceDesktop
+	}
+
+	switch {
+	case strings.Contains(ua, "iphone"):
+		info.Brand, info.Model = "Apple", "iPhone"
+	case strings.Contains(ua, "ipad"):
+		info.Brand, info.Model = "Apple", "iPad"
+	case strings.Contains(ua, "ipod"):
+		info.Brand, info.Model = "Apple", "iPod touch"
+	case strings.Contains(ua, "macintosh"):
+		info.Brand, info.Model = "Apple", "Mac"
+	case osName == "Android" || osName == "HarmonyOS":
+		if model := androidModel(userAgent, ua); model != "" {
+			info.Model = model
+			if info.Brand == "" {
+				info.Brand = brandOf(model)
+			}
+		}
+		// HarmonyOS 设备的型号多为 "ELS-AN00" 这类代号，无法按前缀识别
+		if info.Brand == "" && osName == "HarmonyOS" {
+			info.Brand = "Huawei"
+		}
+	}
+	return info
+}
+
+// androidModel 从包含 Android 的括号段中提取设备型号，如 "(Linux; Android 13; Pixel 7 Build/TQ3A)" 中的 "Pixel 7"
+// Android 10 起部分浏览器精简 UA，型号固定为 "K"，此时视为未知
+func androidModel(userAgent, ua string) string {
+	if len(ua) != len(userAgent) {
+		// 大小写转换改变了字节长度时，下标只对小写形式有效
+		userAgent = ua
+	}
+	idx := strings.Index(ua, "android")
+	if idx < 0 {
+		idx = strings.Index(ua, "harmony")
+	}
+	if idx < 0 {
+		return ""
+	}
+	start := strings.LastIndexByte(ua[:idx], '(') + 1
+	end := strings.IndexByte(ua[idx:], ')')
+	if end < 0 {
+		end = len(ua) - idx
+	}
+
+	for _, part := range strings.Split(userAgent[start:idx+end], ";") {
+		part = strings.TrimSpace(part)
+		if i := strings.Index(strings.ToLower(part), " build/"); i >= 0 {
+			part = part[:i]
+		}
+		lower := strings.ToLower(part)
+		switch {
+		case part == "", androidSkipTokens[lower], localeRegex.MatchString(part),
+			strings.HasPrefix(lower, "android"), strings.HasPrefix(lower, "hmscore"),
+			strings.HasPrefix(lower, "openharmony"):
+			continue
+		}
+		return part
+	}
+	return ""
+}
+
+// brandOf 按型号前缀识别品牌
+func brandOf(model string) string {
+	lower := strings.ToLower(model)
+	for _, b := range brandPrefixes {
+		if strings.HasPrefix(lower, b.prefix) {
+			return b.brand
+		}
+	}
+	return ""
+}
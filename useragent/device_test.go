@@ -0,0 +1,185 @@
+package useragent
+
+import "testing"
+
+func TestGetClientInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		os        OSInfo
+		device    DeviceInfo
+		browser   string
+	}{
+		{
+			name:      "Windows Chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			os:        OSInfo{"Windows", "10"},
+			device:    DeviceInfo{Type: DeviceDesktop},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Windows 7 Firefox",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; WOW64; rv:52.0) Gecko/20100101 Firefox/52.0",
+			os:        OSInfo{"Windows", "7"},
+			device:    DeviceInfo{Type: DeviceDesktop},
+			browser:   "Firefox",
+		},
+		{
+			name:      "macOS Safari",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			os:        OSInfo{"macOS", "10.15.7"},
+			device:    DeviceInfo{DeviceDesktop, "Apple", "Mac"},
+			browser:   "Safari",
+		},
+		{
+			name:      "Linux Firefox",
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			os:        OSInfo{"Linux", ""},
+			device:    DeviceInfo{Type: DeviceDesktop},
+			browser:   "Firefox",
+		},
+		{
+			name:      "Chrome OS",
+			userAgent: "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			os:        OSInfo{"Chrome OS", "14541.0.0"},
+			device:    DeviceInfo{Type: DeviceDesktop},
+			browser:   "Chrome",
+		},
+		{
+			name:      "iPhone Safari",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			os:        OSInfo{"iOS", "17.1.2"},
+			device:    DeviceInfo{DeviceMobile, "Apple", "iPhone"},
+			browser:   "Safari",
+		},
+		{
+			name:      "iPad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			os:        OSInfo{"iOS", "16.6"},
+			device:    DeviceInfo{DeviceTablet, "Apple", "iPad"},
+			browser:   "Safari",
+		},
+		{
+			name:      "Samsung phone",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			os:        OSInfo{"Android", "13"},
+			device:    DeviceInfo{DeviceMobile, "Samsung", "SM-S918B"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Pixel WebView",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/UD1A.230803.041; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.0.0 Mobile Safari/537.36",
+			os:        OSInfo{"Android", "14"},
+			device:    DeviceInfo{DeviceMobile, "Google", "Pixel 8"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Xiaomi with locale",
+			userAgent: "Mozilla/5.0 (Linux; U; Android 11; zh-cn; Redmi Note 10 Pro Build/RKQ1.200826.002) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.116 Mobile Safari/537.36 XiaoMi/MiuiBrowser/16.0.18",
+			os:        OSInfo{"Android", "11"},
+			device:    DeviceInfo{DeviceMobile, "Xiaomi", "Redmi Note 10 Pro"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "reduced Android UA",
+			userAgent: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			os:        OSInfo{"Android", "10"},
+			device:    DeviceInfo{Type: DeviceMobile},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Android tablet",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			os:        OSInfo{"Android", "13"},
+			device:    DeviceInfo{DeviceTablet, "Samsung", "SM-X710"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "HarmonyOS",
+			userAgent: "Mozilla/5.0 (Linux; Android 10; HarmonyOS; ELS-AN00; HMSCore 6.11.0.302) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.88 HuaweiBrowser/14.0.0.322 Mobile Safari/537.36",
+			os:        OSInfo{"HarmonyOS", ""},
+			device:    DeviceInfo{DeviceMobile, "Huawei", "ELS-AN00"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Samsung TV",
+			userAgent: "Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36",
+			os:        OSInfo{"Tizen", "6.0"},
+			device:    DeviceInfo{Type: DeviceTV},
+			browser:   "Safari",
+		},
+		{
+			name:      "LG webOS TV",
+			userAgent: "Mozilla/5.0 (Web0S; Linux/SmartTV) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.79 Safari/537.36 WebAppManager",
+			os:        OSInfo{"webOS", ""},
+			device:    DeviceInfo{Type: DeviceTV, Brand: "LG"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Fire TV",
+			userAgent: "Mozilla/5.0 (Linux; Android 9; AFTMM Build/PS7233) AppleWebKit/537.36 (KHTML, like Gecko) Silk/98.2.1 like Chrome/98.0.4758.101 Safari/537.36",
+			os:        OSInfo{"Android", "9"},
+			device:    DeviceInfo{DeviceTV, "Amazon", "AFTMM"},
+			browser:   "Chrome",
+		},
+		{
+			name:      "Windows Phone",
+			userAgent: "Mozilla/5.0 (Mobile; Windows Phone 8.1; Android 4.0; ARM; Trident/7.0; Touch; rv:11.0; IEMobile/11.0; NOKIA; Lumia 635) like iPhone OS 7_0_3 Mac OS X AppleWebKit/537 (KHTML, like Gecko) Mobile Safari/537",
+			os:        OSInfo{"Windows Phone", "8.1"},
+			device:    DeviceInfo{DeviceMobile, "Apple", "iPhone"},
+			browser:   "Internet Explorer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := GetClientInfo(tt.userAgent)
+			if info.IsBot {
+				t.Error("IsBot = true")
+			}
+			if info.OS != tt.os {
+				t.Errorf("OS = %+v, want %+v", info.OS, tt.os)
+			}
+			if info.Device != tt.device {
+				t.Errorf("Device = %+v, want %+v", info.Device, tt.device)
+			}
+			if info.Browser.Name != tt.browser {
+				t.Errorf("Browser = %q, want %q", info.Browser.Name, tt.browser)
+			}
+			// 第二次调用命中缓存，结果一致
+			if cached := GetClientInfo(tt.userAgent); cached != info {
+				t.Errorf("cached result = %+v, want %+v", cached, info)
+			}
+		})
+	}
+}
+
+func TestGetClientInfo_Bot(t *testing.T) {
+	info := GetClientInfo("Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	if !info.IsBot || info.Device.Type != DeviceBot {
+		t.Errorf("expected bot, got %+v", info)
+	}
+	if info.OS.Name != "Android" {
+		t.Errorf("OS = %+v", info.OS)
+	}
+
+	if info := GetClientInfo(""); info != (ClientInfo{}) {
+		t.Errorf("empty user agent = %+v", info)
+	}
+}
+
+func TestGetClientInfo_RegistryClearsCache(t *testing.T) {
+	defer ResetDefinitions()
+
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 AcmeMonitor/1.0"
+	if GetClientInfo(ua).IsBot {
+		t.Fatal("should not be a bot before registration")
+	}
+	RegisterBot("acmemonitor")
+	if info := GetClientInfo(ua); !info.IsBot || info.Device.Type != DeviceBot {
+		t.Errorf("expected bot after RegisterBot, got %+v", info)
+	}
+	if GetDeviceInfo(ua).Type != DeviceBot || GetOSInfo(ua).Name != "Windows" {
+		t.Error("GetDeviceInfo/GetOSInfo should match GetClientInfo")
+	}
+}
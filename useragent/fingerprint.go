@@ -0,0 +1,149 @@
+package useragent
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Fingerprint 调用方提供的网络层指纹，例如由网关或 TLS 终端计算得到
+type Fingerprint struct {
+	JA3   string // TLS ClientHello 的 JA3 哈希
+	JA4   string // JA4 指纹
+	HTTP2 string // Akamai HTTP/2 指纹
+}
+
+// IsZero 判断是否未提供任何指纹
+func (f Fingerprint) IsZero() bool {
+	return f.JA3 == "" && f.JA4 == "" && f.HTTP2 == ""
+}
+
+// FingerprintMatch 指纹识别结果
+type FingerprintMatch struct {
+	Client    string // 指纹对应的客户端，如 "Chrome"、"curl"、"python-requests"
+	IsBrowser bool   // 该客户端是否为真实浏览器
+}
+
+// FingerprintClassifier 根据网络指纹识别客户端，未识别时返回 false
+type FingerprintClassifier interface {
+	Classify(fp Fingerprint) (FingerprintMatch, bool)
+}
+
+// FingerprintClassifierFunc 函数适配器
+type FingerprintClassifierFunc func(fp Fingerprint) (FingerprintMatch, bool)
+
+// Classify 实现 FingerprintClassifier 接口
+func (f FingerprintClassifierFunc) Classify(fp Fingerprint) (FingerprintMatch, bool) {
+	return f(fp)
+}
+
+// FingerprintDB 基于已知指纹哈希表的识别器，按 JA4、JA3、HTTP2 的顺序查找
+type FingerprintDB struct {
+	mu     sync.RWMutex
+	hashes map[string]FingerprintMatch
+}
+
+// NewFingerprintDB 创建空的指纹库
+func NewFingerprintDB() *FingerprintDB {
+	return &FingerprintDB{hashes: make(map[string]FingerprintMatch)}
+}
+
+// Add 登记一个已知指纹哈希（JA3/JA4/HTTP2 均可）
+func (db *FingerprintDB) Add(hash string, match FingerprintMatch) {
+	if hash == "" {
+		return
+	}
+	db.mu.Lock()
+	db.hashes[hash] = match
+	db.mu.Unlock()
+}
+
+// Classify 实现 FingerprintClassifier 接口
+func (db *FingerprintDB) Classify(fp Fingerprint) (FingerprintMatch, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for _, hash := range []string{fp.JA4, fp.JA3, fp.HTTP2} {
+		if hash == "" {
+			continue
+		}
+		if match, ok := db.hashes[hash]; ok {
+			return match, true
+		}
+	}
+	return FingerprintMatch{}, false
+}
+
+// classifierHolder 包装接口值以便原子替换
+type classifierHolder struct {
+	classifier FingerprintClassifier
+}
+
+var fingerprintClassifier atomic.Pointer[classifierHolder]
+
+// SetFingerprintClassifier 设置全局指纹识别器，传入 nil 关闭指纹关联
+func SetFingerprintClassifier(c FingerprintClassifier) {
+	if c == nil {
+		fingerprintClassifier.Store(nil)
+		return
+	}
+	fingerprintClassifier.Store(&classifierHolder{classifier: c})
+}
+
+// BotInfo 结合 User-Agent 与网络指纹的识别结果
+type BotInfo struct {
+	IsBot       bool        // 判定为爬虫或自动化客户端（包括疑似伪造的请求）
+	Browser     BrowserInfo // User-Agent 声明的浏览器信息
+	Fingerprint string      // 指纹识别出的客户端，未识别时为空
+	Spoofed     bool        // User-Agent 与网络指纹不一致，疑似伪造
+	Reason      string      // 判定原因，便于风控审计
+}
+
+// Analyze 解析 User-Agent，并在设置了指纹识别器时与网络指纹交叉校验
+// 例如 User-Agent 声称是 Chrome，但 TLS 指纹属于 curl，则标记为疑似伪造并视为爬虫
+func Analyze(userAgent string, fp Fingerprint) BotInfo {
+	reg := currentRegistry.Load()
+	info := BotInfo{Browser: getBrowserInfo(reg, userAgent)}
+
+	switch {
+	case userAgent == "":
+		info.IsBot, info.Reason = true, "empty user agent"
+	case reg.fastBotCheck(strings.ToLower(userAgent)):
+		info.IsBot, info.Reason = true, "bot identifier in user agent"
+	case !info.Browser.IsBrowser:
+		info.IsBot, info.Reason = true, "user agent is not a known browser"
+	}
+
+	holder := fingerprintClassifier.Load()
+	if holder == nil || fp.IsZero() {
+		return info
+	}
+	match, ok := holder.classifier.Classify(fp)
+	if !ok {
+		return info
+	}
+	info.Fingerprint = match.Client
+
+	if !info.Browser.IsBrowser {
+		return info
+	}
+	switch {
+	case !match.IsBrowser:
+		info.Spoofed = true
+		info.Reason = "user agent claims " + browserLabel(info.Browser) + " but fingerprint matches " + match.Client
+	case match.Client != "" && info.Browser.Name != "" && !strings.EqualFold(match.Client, info.Browser.Name):
+		info.Spoofed = true
+		info.Reason = "user agent claims " + browserLabel(info.Browser) + " but fingerprint matches browser " + match.Client
+	}
+	if info.Spoofed {
+		info.IsBot = true
+	}
+	return info
+}
+
+// browserLabel 返回用于说明的浏览器名称
+func browserLabel(b BrowserInfo) string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return "a browser"
+}
@@ -0,0 +1,59 @@
+package useragent
+
+import "testing"
+
+func TestAnalyze_FingerprintCorrelation(t *testing.T) {
+	const chromeUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	db := NewFingerprintDB()
+	db.Add("ja3-chrome", FingerprintMatch{Client: "Chrome", IsBrowser: true})
+	db.Add("ja3-firefox", FingerprintMatch{Client: "Firefox", IsBrowser: true})
+	db.Add("ja3-curl", FingerprintMatch{Client: "curl"})
+	SetFingerprintClassifier(db)
+	defer SetFingerprintClassifier(nil)
+
+	tests := []struct {
+		name      string
+		ua        string
+		fp        Fingerprint
+		wantBot   bool
+		wantSpoof bool
+		wantFP    string
+	}{
+		{"consistent chrome", chromeUA, Fingerprint{JA3: "ja3-chrome"}, false, false, "Chrome"},
+		{"chrome ua with curl tls", chromeUA, Fingerprint{JA3: "ja3-curl"}, true, true, "curl"},
+		{"chrome ua with firefox tls", chromeUA, Fingerprint{JA3: "ja3-firefox"}, true, true, "Firefox"},
+		{"unknown fingerprint", chromeUA, Fingerprint{JA3: "unknown"}, false, false, ""},
+		{"no fingerprint", chromeUA, Fingerprint{}, false, false, ""},
+		{"declared bot", "Googlebot/2.1", Fingerprint{JA3: "ja3-curl"}, true, false, "curl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := Analyze(tt.ua, tt.fp)
+			if info.IsBot != tt.wantBot || info.Spoofed != tt.wantSpoof || info.Fingerprint != tt.wantFP {
+				t.Errorf("Analyze() = %+v, want bot=%v spoofed=%v fingerprint=%q", info, tt.wantBot, tt.wantSpoof, tt.wantFP)
+			}
+			if info.Spoofed && info.Reason == "" {
+				t.Error("spoofed result should carry a reason")
+			}
+		})
+	}
+}
+
+func TestAnalyze_WithoutClassifier(t *testing.T) {
+	SetFingerprintClassifier(nil)
+	info := Analyze("curl/8.0", Fingerprint{JA3: "ja3-curl"})
+	if !info.IsBot || info.Spoofed {
+		t.Errorf("Analyze() = %+v, want bot without spoofing", info)
+	}
+
+	SetFingerprintClassifier(FingerprintClassifierFunc(func(fp Fingerprint) (FingerprintMatch, bool) {
+		return FingerprintMatch{Client: "python-requests"}, fp.HTTP2 != ""
+	}))
+	defer SetFingerprintClassifier(nil)
+	info = Analyze("Mozilla/5.0 (Macintosh) Firefox/121.0", Fingerprint{HTTP2: "h2"})
+	if !info.Spoofed {
+		t.Errorf("expected spoofing via func classifier, got %+v", info)
+	}
+}
@@ -0,0 +1,193 @@
+package useragent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// browserRule 自定义浏览器识别规则
+type browserRule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// registry 识别规则集合，创建后只读，修改时整体复制替换（copy-on-write）
+// generation 每次替换时递增，识别结果缓存按它丢弃旧规则下算出的结果
+type registry struct {
+	bots       map[string]bool
+	browsers   map[string]bool
+	rules      []browserRule
+	generation uint64
+}
+
+var (
+	// currentRegistry 当前生效的规则，读取无需加锁
+	currentRegistry atomic.Pointer[registry]
+	// registryMu 串行化写操作，避免并发注册互相覆盖
+	registryMu sync.Mutex
+)
+
+func init() {
+	currentRegistry.Store(defaultRegistry())
+}
+
+// defaultRegistry 使用内置标识创建规则集合
+func defaultRegistry() *registry {
+	reg := &registry{
+		bots:     make(map[string]bool, len(botIdentifiers)),
+		browsers: make(map[string]bool, len(commonBrowserIdentifiers)),
+	}
+	for k := range botIdentifiers {
+		reg.bots[k] = true
+	}
+	for k := range commonBrowserIdentifiers {
+		reg.browsers[k] = true
+	}
+	return reg
+}
+
+// clone 复制规则集合用于修改
+func (r *registry) clone() *registry {
+	c := &registry{
+		bots:     make(map[string]bool, len(r.bots)),
+		browsers: make(map[string]bool, len(r.browsers)),
+		rules:    append([]browserRule(nil), r.rules...),
+	}
+	for k := range r.bots {
+		c.bots[k] = true
+	}
+	for k := range r.browsers {
+		c.browsers[k] = true
+	}
+	return c
+}
+
+// updateRegistry 以 copy-on-write 方式修改规则，并清空识别结果缓存
+func updateRegistry(fn func(reg *registry)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	prev := currentRegistry.Load()
+	next := prev.clone()
+	fn(next)
+	replaceRegistry(prev, next)
+}
+
+// replaceRegistry 替换当前规则并清空识别结果缓存，调用方需持有 registryMu
+// 正在使用旧规则识别的调用可能在清空后写回结果，这些结果带有旧的 generation，读取时会被忽略
+func replaceRegistry(prev, next *registry) {
+	next.generation = prev.generation + 1
+	currentRegistry.Store(next)
+
+	// 规则变化后旧的识别结果可能不再正确
+	isBrowserCache.Clear()
+	browserInfoCache.Clear()
+	clientInfoCache.Clear()
+}
+
+// RegisterBot 注册自定义爬虫标识，匹配时不区分大小写
+func RegisterBot(identifier string) {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	if identifier == "" {
+		return
+	}
+	updateRegistry(func(reg *registry) {
+		reg.bots[identifier] = true
+	})
+}
+
+// RegisterBrowser 注册自定义浏览器识别规则
+// pattern 为匹配 User-Agent 的正则表达式；包含捕获组时第一个捕获组作为版本号，
+// 否则按 "名称/版本" 格式从匹配结果中提取版本。自定义规则优先于内置规则匹配。
+// 只按正则判断是否为浏览器，名称仅用于展示，不参与子串匹配
+func RegisterBrowser(name, pattern string) error {
+	if name == "" {
+		return errors.New("browser name cannot be empty")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid browser pattern for %s: %w", name, err)
+	}
+	updateRegistry(func(reg *registry) {
+		reg.rules = append(reg.rules, browserRule{name: name, regex: re})
+	})
+	return nil
+}
+
+// Definitions 识别规则定义文件的结构
+type Definitions struct {
+	Bots     []string            `json:"bots"`
+	Browsers []BrowserDefinition `json:"browsers"`
+}
+
+// BrowserDefinition 单个浏览器规则定义
+type BrowserDefinition struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// LoadDefinitions 从 JSON 定义文件加载爬虫与浏览器规则，所有规则校验通过后一次性生效
+//
+//	{"bots": ["mycrawler"], "browsers": [{"name": "YaBrowser", "pattern": "YaBrowser/([\\d.]+)"}]}
+func LoadDefinitions(r io.Reader) error {
+	var defs Definitions
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return fmt.Errorf("failed to decode definitions: %w", err)
+	}
+
+	rules := make([]browserRule, 0, len(defs.Browsers))
+	for _, b := range defs.Browsers {
+		if b.Name == "" {
+			return errors.New("browser name cannot be empty")
+		}
+		re, err := regexp.Compile(b.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid browser pattern for %s: %w", b.Name, err)
+		}
+		rules = append(rules, browserRule{name: b.Name, regex: re})
+	}
+
+	updateRegistry(func(reg *registry) {
+		for _, bot := range defs.Bots {
+			if bot = strings.ToLower(strings.TrimSpace(bot)); bot != "" {
+				reg.bots[bot] = true
+			}
+		}
+		for _, rule := range rules {
+			reg.rules = append(reg.rules, rule)
+		}
+	})
+	return nil
+}
+
+// ResetDefinitions 移除所有自定义规则，恢复内置标识
+func ResetDefinitions() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	replaceRegistry(currentRegistry.Load(), defaultRegistry())
+}
+
+// matchCustomBrowser 按注册顺序匹配自定义浏览器规则
+func (r *registry) matchCustomBrowser(userAgent string) (BrowserInfo, bool) {
+	for _, rule := range r.rules {
+		match := rule.regex.FindStringSubmatch(userAgent)
+		if match == nil {
+			continue
+		}
+		version := ""
+		if len(match) > 1 {
+			version = match[1]
+		} else if parts := strings.SplitN(match[0], "/", 2); len(parts) == 2 {
+			version = parts[1]
+		}
+		return BrowserInfo{IsBrowser: true, Name: rule.name, Version: version}, true
+	}
+	return BrowserInfo{}, false
+}
@@ -0,0 +1,125 @@
+package useragent
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegisterBot(t *testing.T) {
+	defer ResetDefinitions()
+
+	ua := "Mozilla/5.0 (compatible; AcmeFetcher/1.0)"
+	if !IsBrowser(ua) {
+		t.Fatal("expected UA to be treated as browser before registration")
+	}
+
+	RegisterBot("AcmeFetcher")
+
+	if IsBrowser(ua) {
+		t.Error("registered bot should not be treated as browser (cache must be invalidated)")
+	}
+	if GetBrowserInfo(ua).IsBrowser {
+		t.Error("GetBrowserInfo should report registered bot as non-browser")
+	}
+}
+
+func TestRegisterBrowser(t *testing.T) {
+	defer ResetDefinitions()
+
+	ua := "Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 Chrome/120.0.0.0 YaBrowser/24.1.2 Safari/537.36"
+	if got := GetBrowserInfo(ua); got.Name != "Chrome" {
+		t.Fatalf("expected built-in detection as Chrome, got %+v", got)
+	}
+
+	if err := RegisterBrowser("Yandex", `YaBrowser/([\d.]+)`); err != nil {
+		t.Fatalf("RegisterBrowser() error = %v", err)
+	}
+
+	got := GetBrowserInfo(ua)
+	if got.Name != "Yandex" || got.Version != "24.1.2" || !got.IsBrowser {
+		t.Errorf("GetBrowserInfo() = %+v, want Yandex 24.1.2", got)
+	}
+
+	if err := RegisterBrowser("Broken", "("); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestRegistryUpdate_IgnoresStaleCacheWrites(t *testing.T) {
+	defer ResetDefinitions()
+
+	ua := "Mozilla/5.0 (compatible; StaleFetcher/1.0)"
+	old := currentRegistry.Load()
+	RegisterBot("StaleFetcher")
+
+	// 模拟注册前已开始识别的调用在缓存清空后写回旧结果
+	isBrowserCache.Set(old, ua, true)
+	browserInfoCache.Set(old, ua, BrowserInfo{IsBrowser: true})
+	clientInfoCache.Set(old, ua, ClientInfo{})
+
+	if IsBrowser(ua) || GetBrowserInfo(ua).IsBrowser || !GetClientInfo(ua).IsBot {
+		t.Error("results computed with the old registry should be ignored")
+	}
+}
+
+func TestRegisterBrowser_NameIsNotAnIdentifier(t *testing.T) {
+	defer ResetDefinitions()
+
+	if err := RegisterBrowser("Go", `GoBrowser/([\d.]+)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, ua := range []string{"Go-http-client/1.1", "cargo/1.75.0"} {
+		if IsBrowser(ua) {
+			t.Errorf("IsBrowser(%q) = true, custom rule name must not match as a substring", ua)
+		}
+	}
+	if !IsBrowser("GoBrowser/2.0") {
+		t.Error("custom rule pattern should still match")
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	defer ResetDefinitions()
+
+	defs := `{
+		"bots": ["internal-monitor"],
+		"browsers": [{"name": "Whale", "pattern": "Whale/[\\d.]+"}]
+	}`
+	if err := LoadDefinitions(strings.NewReader(defs)); err != nil {
+		t.Fatalf("LoadDefinitions() error = %v", err)
+	}
+
+	if IsBrowser("Mozilla/5.0 internal-monitor/2.0") {
+		t.Error("loaded bot should be detected")
+	}
+	got := GetBrowserInfo("Mozilla/5.0 AppleWebKit/537.36 Whale/3.21.192.18 Safari/537.36")
+	if got.Name != "Whale" || got.Version != "3.21.192.18" {
+		t.Errorf("GetBrowserInfo() = %+v", got)
+	}
+
+	bad := `{"browsers": [{"name": "Bad", "pattern": "["}]}`
+	if err := LoadDefinitions(strings.NewReader(bad)); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestRegistry_ConcurrentReadsAndWrites(t *testing.T) {
+	defer ResetDefinitions()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				GetBrowserInfo("Mozilla/5.0 Chrome/120.0 Safari/537.36")
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			RegisterBot("concurrent-bot")
+		}(i)
+	}
+	wg.Wait()
+}
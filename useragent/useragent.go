@@ -3,8 +3,9 @@ package useragent
 import (
 	"regexp"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/iwen-conf/utils-pkg/cache"
 )
 
 // 预编译正则表达式以提高性能
@@ -17,6 +18,7 @@ var (
 	ieRegex      = regexp.MustCompile(`(?i)msie [\d.]+|trident\/[\d.]+`)
 
 	// 使用 map 存储常见的爬虫标识，提高查找效率
+	// 内置默认值，运行时生效的规则见 registry.go
 	botIdentifiers = map[string]bool{
 		"bot":                 true,
 		"spider":              true,
@@ -68,173 +70,131 @@ type BrowserInfo struct {
 }
 
 // CacheEntry 缓存条目
+//
+// Deprecated: LRUCache 已改由 cache 包实现，不再使用该类型。
 type CacheEntry struct {
 	value      interface{} // 存储的值
 	expiration int64       // 过期时间
 }
 
-// LRUCache LRU缓存实现
+// LRUCache LRU缓存，ttl 单位为秒，<= 0 表示不过期
+//
+// Deprecated: 使用 cache.New，支持泛型值、分片、GetOrLoad 与统计指标。
 type LRUCache struct {
-	capacity    int                   // 最大容量
-	mu          sync.RWMutex          // 读写锁
-	cache       map[string]CacheEntry // 缓存数据
-	keys        []string              // 按使用顺序存储的键列表
-	ttl         int64                 // 过期时间（秒）
-	cleanupTime int64                 // 上次清理时间
+	c *cache.Cache[string, interface{}]
 }
 
 // NewLRUCache 创建一个新的LRU缓存
+//
+// Deprecated: 使用 cache.New。
 func NewLRUCache(capacity int, ttl int64) *LRUCache {
-	return &LRUCache{
-		capacity:    capacity,
-		cache:       make(map[string]CacheEntry, capacity),
-		keys:        make([]string, 0, capacity),
-		ttl:         ttl,
-		cleanupTime: time.Now().Unix(),
-	}
+	return &LRUCache{c: cache.New[string, interface{}](cache.Options{
+		Capacity: capacity,
+		TTL:      time.Duration(ttl) * time.Second,
+		Shards:   1,
+	})}
 }
 
 // Get 获取缓存值
 func (c *LRUCache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	entry, ok := c.cache[key]
-	c.mu.RUnlock()
-
-	if !ok {
-		return nil, false
-	}
-
-	// 检查是否过期
-	now := time.Now().Unix()
-	if entry.expiration > 0 && now > entry.expiration {
-		c.mu.Lock()
-		delete(c.cache, key)
-		c.removeKey(key)
-		c.mu.Unlock()
-		return nil, false
-	}
-
-	// 将键移到最近使用的位置
-	c.mu.Lock()
-	c.moveToFront(key)
-
-	// 每隔一段时间清理过期项
-	if now-c.cleanupTime > 300 { // 每5分钟清理一次
-		c.cleanup(now)
-		c.cleanupTime = now
-	}
-	c.mu.Unlock()
-
-	return entry.value, true
+	return c.c.Get(key)
 }
 
 // Put 设置缓存值
 func (c *LRUCache) Put(key string, value interface{}) {
-	now := time.Now().Unix()
-	expiration := int64(0)
-	if c.ttl > 0 {
-		expiration = now + c.ttl
-	}
+	c.c.Set(key, value)
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// Clear 清空缓存
+func (c *LRUCache) Clear() {
+	c.c.Clear()
+}
 
-	// 如果键已存在，更新值并移动到前面
-	if _, ok := c.cache[key]; ok {
-		c.cache[key] = CacheEntry{value: value, expiration: expiration}
-		c.moveToFront(key)
-		return
-	}
+// resultShards 识别结果缓存的分片数
+const resultShards = 16
 
-	// 如果达到容量，删除最久未使用的键
-	if len(c.cache) >= c.capacity {
-		leastUsed := c.keys[len(c.keys)-1]
-		delete(c.cache, leastUsed)
-		c.keys = c.keys[:len(c.keys)-1]
-	}
+// ShardedCache 分片缓存，用于减少锁竞争
+//
+// Deprecated: 使用 cache.New，分片数通过 cache.Options.Shards 设置。
+type ShardedCache struct {
+	c *cache.Cache[string, interface{}]
+}
 
-	// 添加新键到缓存
-	c.cache[key] = CacheEntry{value: value, expiration: expiration}
-	c.keys = append([]string{key}, c.keys...)
+// NewShardedCache 创建一个新的分片缓存，共 16 个分片，ttl 单位为秒
+//
+// Deprecated: 使用 cache.New。
+func NewShardedCache(shardCapacity int, ttl int64) *ShardedCache {
+	return &ShardedCache{c: cache.New[string, interface{}](cache.Options{
+		Capacity: shardCapacity * resultShards,
+		TTL:      time.Duration(ttl) * time.Second,
+		Shards:   resultShards,
+	})}
 }
 
-// moveToFront 将键移到最近使用的位置
-func (c *LRUCache) moveToFront(key string) {
-	for i, k := range c.keys {
-		if k == key {
-			// 从当前位置删除
-			c.keys = append(c.keys[:i], c.keys[i+1:]...)
-			// 添加到最前面
-			c.keys = append([]string{key}, c.keys...)
-			break
-		}
-	}
+// Get 从分片缓存获取值
+func (sc *ShardedCache) Get(key string) (interface{}, bool) {
+	return sc.c.Get(key)
 }
 
-// removeKey 从keys列表中删除键
-func (c *LRUCache) removeKey(key string) {
-	for i, k := range c.keys {
-		if k == key {
-			c.keys = append(c.keys[:i], c.keys[i+1:]...)
-			break
-		}
-	}
+// Put 设置分片缓存值
+func (sc *ShardedCache) Put(key string, value interface{}) {
+	sc.c.Set(key, value)
 }
 
-// cleanup 清理过期项
-func (c *LRUCache) cleanup(now int64) {
-	for key, entry := range c.cache {
-		if entry.expiration > 0 && now > entry.expiration {
-			delete(c.cache, key)
-			c.removeKey(key)
-		}
-	}
+// Clear 清空所有分片
+func (sc *ShardedCache) Clear() {
+	sc.c.Clear()
 }
 
-// ShardedCache 分片缓存，用于减少锁竞争
-type ShardedCache struct {
-	shards [16]*LRUCache // 16个分片
+// resultCache 识别结果缓存，结果带有计算时所用规则的 generation
+type resultCache[V any] struct {
+	c *cache.Cache[string, cachedResult[V]]
 }
 
-// NewShardedCache 创建一个新的分片缓存
-func NewShardedCache(shardCapacity int, ttl int64) *ShardedCache {
-	sc := &ShardedCache{}
-	for i := 0; i < 16; i++ {
-		sc.shards[i] = NewLRUCache(shardCapacity, ttl)
-	}
-	return sc
+// cachedResult 缓存的识别结果
+type cachedResult[V any] struct {
+	generation uint64
+	value      V
+}
+
+// newResultCache 创建识别结果缓存：16 个分片，每个分片 1000 条，1 小时过期
+func newResultCache[V any]() *resultCache[V] {
+	return &resultCache[V]{c: cache.New[string, cachedResult[V]](cache.Options{
+		Capacity: 1000 * resultShards,
+		TTL:      time.Hour,
+		Shards:   resultShards,
+	})}
 }
 
-// getShard 获取键对应的分片
-func (sc *ShardedCache) getShard(key string) *LRUCache {
-	// 简单的哈希函数，用于确定分片
-	var sum uint32
-	for i := 0; i < len(key); i++ {
-		sum += uint32(key[i])
+// Get 获取按 reg 规则算出的结果，其他 generation 的结果视为未命中
+func (rc *resultCache[V]) Get(reg *registry, userAgent string) (V, bool) {
+	entry, ok := rc.c.Get(userAgent)
+	if !ok || entry.generation != reg.generation {
+		var zero V
+		return zero, false
 	}
-	return sc.shards[sum%16]
+	return entry.value, true
 }
 
-// Get 从分片缓存获取值
-func (sc *ShardedCache) Get(key string) (interface{}, bool) {
-	return sc.getShard(key).Get(key)
+// Set 缓存按 reg 规则算出的结果
+func (rc *resultCache[V]) Set(reg *registry, userAgent string, value V) {
+	rc.c.Set(userAgent, cachedResult[V]{generation: reg.generation, value: value})
 }
 
-// Put 设置分片缓存值
-func (sc *ShardedCache) Put(key string, value interface{}) {
-	sc.getShard(key).Put(key, value)
+// Clear 清空缓存
+func (rc *resultCache[V]) Clear() {
+	rc.c.Clear()
 }
 
-// 创建分片缓存实例
+// 识别结果缓存
 var (
-	// 1小时过期时间，每个分片容量1000
-	isBrowserCache   = NewShardedCache(1000, 3600)
-	browserInfoCache = NewShardedCache(1000, 3600)
+	isBrowserCache   = newResultCache[bool]()
+	browserInfoCache = newResultCache[BrowserInfo]()
 )
 
 // fastBrowserCheck 快速检查字符串中是否包含浏览器标识
-func fastBrowserCheck(ua string) bool {
-	for browser := range commonBrowserIdentifiers {
+func (r *registry) fastBrowserCheck(ua string) bool {
+	for browser := range r.browsers {
 		if strings.Contains(ua, browser) {
 			return true
 		}
@@ -243,8 +203,8 @@ func fastBrowserCheck(ua string) bool {
 }
 
 // fastBotCheck 快速检查字符串中是否包含爬虫标识
-func fastBotCheck(ua string) bool {
-	for bot := range botIdentifiers {
+func (r *registry) fastBotCheck(ua string) bool {
+	for bot := range r.bots {
 		if strings.Contains(ua, bot) {
 			return true
 		}
@@ -258,44 +218,61 @@ func IsBrowser(userAgent string) bool {
 		return false
 	}
 
+	// 整个识别过程使用同一份规则
+	reg := currentRegistry.Load()
+
 	// 检查缓存
-	if result, ok := isBrowserCache.Get(userAgent); ok {
-		return result.(bool)
+	if result, ok := isBrowserCache.Get(reg, userAgent); ok {
+		return result
 	}
 
 	// 转为小写（只进行一次转换）
 	ua := strings.ToLower(userAgent)
 
 	// 检查是否为爬虫或机器人
-	if fastBotCheck(ua) {
-		isBrowserCache.Put(userAgent, false)
+	if reg.fastBotCheck(ua) {
+		isBrowserCache.Set(reg, userAgent, false)
 		return false
 	}
 
-	// 检查是否包含任一浏览器标识
-	isBrowser := fastBrowserCheck(ua)
-	isBrowserCache.Put(userAgent, isBrowser)
+	// 检查是否包含任一浏览器标识或匹配自定义浏览器规则
+	isBrowser := reg.fastBrowserCheck(ua)
+	if !isBrowser {
+		_, isBrowser = reg.matchCustomBrowser(userAgent)
+	}
+	isBrowserCache.Set(reg, userAgent, isBrowser)
 	return isBrowser
 }
 
 // GetBrowserInfo 获取详细的浏览器信息
 func GetBrowserInfo(userAgent string) BrowserInfo {
+	return getBrowserInfo(currentRegistry.Load(), userAgent)
+}
+
+// getBrowserInfo 按 reg 规则识别浏览器信息
+func getBrowserInfo(reg *registry, userAgent string) BrowserInfo {
 	if userAgent == "" {
 		return BrowserInfo{IsBrowser: false}
 	}
 
 	// 检查缓存
-	if result, ok := browserInfoCache.Get(userAgent); ok {
-		return result.(BrowserInfo)
+	if result, ok := browserInfoCache.Get(reg, userAgent); ok {
+		return result
 	}
 
 	// 转为小写（只进行一次转换用于bot检查）
 	ua := strings.ToLower(userAgent)
 
 	// 检查是否为爬虫或机器人
-	if fastBotCheck(ua) {
+	if reg.fastBotCheck(ua) {
 		result := BrowserInfo{IsBrowser: false}
-		browserInfoCache.Put(userAgent, result)
+		browserInfoCache.Set(reg, userAgent, result)
+		return result
+	}
+
+	// 自定义规则优先于内置规则
+	if result, ok := reg.matchCustomBrowser(userAgent); ok {
+		browserInfoCache.Set(reg, userAgent, result)
 		return result
 	}
 
@@ -317,11 +294,11 @@ func GetBrowserInfo(userAgent string) BrowserInfo {
 	case safariRegex.MatchString(userAgent) && !chromeRegex.MatchString(userAgent):
 		result = extractBrowserInfo(userAgent, "Safari", safariRegex)
 	default:
-		result = BrowserInfo{IsBrowser: fastBrowserCheck(ua)}
+		result = BrowserInfo{IsBrowser: reg.fastBrowserCheck(ua)}
 	}
 
 	// 存入缓存
-	browserInfoCache.Put(userAgent, result)
+	browserInfoCache.Set(reg, userAgent, result)
 	return result
 }
 